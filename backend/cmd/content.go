@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"voicewriter/internal/content"
+	"voicewriter/internal/dto"
+	"voicewriter/internal/repository"
+	"voicewriter/internal/service"
+)
+
+// runImport 从文件批量导入句子：voicewriter import [-format csv] [-dry-run] [-default-scene 名称] <file>
+func runImport(args []string) {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	format := fs.String("format", "", "file format: "+strings.Join(content.Formats(), ", ")+" (default: by file extension)")
+	dryRun := fs.Bool("dry-run", false, "validate only, do not write")
+	defaultScene := fs.String("default-scene", "", "scene name for rows without one")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: voicewriter import [flags] <file|->")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	path := fs.Arg(0)
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		log.Fatalf("Failed to read %s: %v", path, err)
+	}
+
+	contentService := newContentService()
+	report, err := contentService.Import(context.Background(), data, &service.ImportOptions{
+		Format:       *format,
+		Filename:     path,
+		DryRun:       *dryRun,
+		DefaultScene: *defaultScene,
+	})
+	var invalid *service.ImportInvalidError
+	if errors.As(err, &invalid) {
+		report = invalid.Report
+	}
+	if report != nil {
+		out, _ := json.MarshalIndent(dto.NewImportReport(report), "", "  ")
+		fmt.Println(string(out))
+	}
+	if err != nil {
+		if invalid != nil {
+			os.Exit(1)
+		}
+		log.Fatalf("Import failed: %v", err)
+	}
+}
+
+// runExport 批量导出句子：voicewriter export [-format csv] [-scene id] [-o file]
+func runExport(args []string) {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	format := fs.String("format", "", "file format: "+strings.Join(content.Formats(), ", ")+" (default: by -o extension, else csv)")
+	sceneID := fs.Uint("scene", 0, "only export the given scene id")
+	output := fs.String("o", "-", "output file, - for stdout")
+	fs.Parse(args)
+
+	if *format == "" {
+		*format = content.DetectFormat(*output)
+	}
+	if *format == "" {
+		*format = content.FormatCSV
+	}
+	if !content.Supported(*format) {
+		log.Fatalf("Unsupported format %q, use -format with one of %v", *format, content.Formats())
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "-" {
+		f, err := os.Create(*output)
+		if err != nil {
+			log.Fatalf("Failed to create %s: %v", *output, err)
+		}
+		defer f.Close()
+		w = f
+	}
+	buffered := bufio.NewWriter(w)
+
+	contentService := newContentService()
+	if err := contentService.Export(context.Background(), buffered, *format, *sceneID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			log.Fatalf("Scene %d not found", *sceneID)
+		}
+		log.Fatalf("Export failed: %v", err)
+	}
+	if err := buffered.Flush(); err != nil {
+		log.Fatalf("Failed to write output: %v", err)
+	}
+}
+
+func newContentService() *service.ContentService {
+	db := openDatabase(loadConfig())
+	return service.NewContentService(
+		repository.NewContentRepository(db),
+		repository.NewSceneRepository(db),
+		repository.NewSentenceRepository(db),
+		// 命令行导入导出不提供搜索，服务启动时会重建索引
+		repository.NewMemorySearchIndex(),
+	)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"voicewriter/internal/database"
+	"voicewriter/internal/health"
+	"voicewriter/internal/tts"
+
+	"gorm.io/gorm"
+)
+
+// readinessTimeout 就绪探针中单个依赖检查的超时
+const readinessTimeout = 2 * time.Second
+
+// newHealthChecker 注册就绪探针检查的依赖：数据库连接、迁移状态和音频缓存目录
+func newHealthChecker(db *gorm.DB, cache *tts.FileCache) (*health.Checker, error) {
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load migrations: %w", err)
+	}
+
+	checker := health.NewChecker(readinessTimeout)
+	checker.Add("database", func(ctx context.Context) (interface{}, error) {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return nil, err
+		}
+		if err := sqlDB.PingContext(ctx); err != nil {
+			return nil, err
+		}
+		stats := sqlDB.Stats()
+		return map[string]interface{}{
+			"dialect":          db.Dialector.Name(),
+			"open_connections": stats.OpenConnections,
+			"in_use":           stats.InUse,
+			"idle":             stats.Idle,
+		}, nil
+	})
+	checker.Add("migrations", func(ctx context.Context) (interface{}, error) {
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var current uint64
+		var pending []uint64
+		for _, status := range statuses {
+			if status.AppliedAt == nil {
+				pending = append(pending, status.Version)
+			} else if status.Version > current {
+				current = status.Version
+			}
+		}
+		detail := map[string]interface{}{"version": current, "pending": len(pending)}
+		if len(pending) > 0 {
+			return detail, fmt.Errorf("%w: %d pending migration(s)", database.ErrSchemaBehind, len(pending))
+		}
+		return detail, nil
+	})
+	checker.Add("audio_cache", func(ctx context.Context) (interface{}, error) {
+		return map[string]interface{}{"dir": cache.Dir()}, cache.CheckWritable()
+	})
+	return checker, nil
+}
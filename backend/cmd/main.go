@@ -1,21 +1,59 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
+	"log/slog"
+	"net"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 
+	"voicewriter/internal/auth"
 	"voicewriter/internal/config"
 	"voicewriter/internal/database"
 	"voicewriter/internal/handler"
+	"voicewriter/internal/logging"
+	"voicewriter/internal/metrics"
+	"voicewriter/internal/middleware"
+	"voicewriter/internal/model"
 	"voicewriter/internal/repository"
 	"voicewriter/internal/service"
+	"voicewriter/internal/tts"
+	"voicewriter/internal/worker"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 func main() {
-	// 加载配置
+	// 子命令：serve（默认）、migrate、import、export
+	command, args := "serve", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
+	}
+
+	switch command {
+	case "serve":
+		runServer()
+	case "migrate":
+		runMigrate(args)
+	case "import":
+		runImport(args)
+	case "export":
+		runExport(args)
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n\nUsage: voicewriter [serve|migrate|import|export] [flags]\n", command)
+		os.Exit(2)
+	}
+}
+
+// loadConfig 加载配置，路径由 CONFIG_PATH 指定
+func loadConfig() *config.Config {
 	configPath := os.Getenv("CONFIG_PATH")
 	if configPath == "" {
 		configPath = "etc/config.yaml"
@@ -25,75 +63,242 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
+	return cfg
+}
 
-	// 设置Gin模式
-	gin.SetMode(cfg.Server.Mode)
+// fatal 记录错误日志并退出
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
 
-	// 初始化数据库连接
+// connectDatabase 连接数据库，不检查表结构
+func connectDatabase(cfg *config.Config) *gorm.DB {
 	db, err := database.NewDatabase(&cfg.Database)
 	if err != nil {
-		log.Fatalf("Failed to connect database: %v", err)
+		fatal("failed to connect database", err)
+	}
+	return db
+}
+
+// openDatabase 连接数据库并确认迁移均已执行，表结构落后时拒绝继续
+func openDatabase(cfg *config.Config) *gorm.DB {
+	db := connectDatabase(cfg)
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		fatal("failed to load migrations", err)
 	}
+	if err := migrator.Check(context.Background()); err != nil {
+		if errors.Is(err, database.ErrSchemaBehind) {
+			fatal("database schema is behind, run `voicewriter migrate up` first", err)
+		}
+		fatal("failed to check database schema", err)
+	}
+	return db
+}
+
+func runServer() {
+	// 加载配置
+	cfg := loadConfig()
 
-	// 自动迁移数据库表结构
-	if err := database.AutoMigrate(db); err != nil {
-		log.Fatalf("Failed to migrate database: %v", err)
+	// 初始化日志，标准库 log 的输出也会转到 slog
+	logger, logFile, err := logging.New(&cfg.Log)
+	if err != nil {
+		log.Fatalf("Failed to set up logging: %v", err)
 	}
+	defer logFile.Close()
+	slog.SetDefault(logger)
+
+	// 设置Gin模式
+	gin.SetMode(cfg.Server.Mode)
+
+	// 初始化数据库连接
+	db := openDatabase(cfg)
 
 	// 初始化种子数据
 	if err := database.SeedData(db); err != nil {
-		log.Fatalf("Failed to seed data: %v", err)
+		fatal("failed to seed data", err)
+	}
+
+	// 后台任务组，退出时等待其中的任务完成
+	workers := worker.NewGroup()
+
+	r, err := newRouter(cfg, db, workers)
+	if err != nil {
+		fatal("failed to initialize server", err)
 	}
 
+	// 启动服务，收到 SIGINT / SIGTERM 后优雅退出
+	srv := newServer(&cfg.Server, r, workers, db)
+	ln, err := net.Listen("tcp", srv.httpServer.Addr)
+	if err != nil {
+		fatal("failed to listen", err)
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	slog.Info("server starting", "addr", srv.httpServer.Addr, "mode", cfg.Server.Mode)
+	if err := srv.run(ctx, ln); err != nil {
+		fatal("server exited with error", err)
+	}
+}
+
+// newRouter 组装各层依赖并注册路由
+func newRouter(cfg *config.Config, db *gorm.DB, workers *worker.Group) (*gin.Engine, error) {
 	// 初始化Repository层
 	sceneRepo := repository.NewSceneRepository(db)
 	sentenceRepo := repository.NewSentenceRepository(db)
 	progressRepo := repository.NewProgressRepository(db)
+	attemptRepo := repository.NewAttemptRepository(db)
+	reviewRepo := repository.NewReviewRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	contentRepo := repository.NewContentRepository(db)
+	translationRepo := repository.NewTranslationRepository(db)
+	vocabularyRepo := repository.NewVocabularyRepository(db)
+	sessionRepo := repository.NewSessionRepository(db)
+	anonymousRepo := repository.NewAnonymousRepository(db)
+	searchIndex := repository.NewMemorySearchIndex()
+	txManager := repository.NewTxManager(db)
+
+	// 初始化语音合成
+	synthesizer, err := tts.NewSynthesizer(&cfg.TTS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tts synthesizer: %w", err)
+	}
+	audioCache, err := tts.NewFileCache(cfg.TTS.CacheDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create audio cache: %w", err)
+	}
+
+	// 初始化令牌管理
+	tokens, err := auth.NewTokenManager(&cfg.Auth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token manager: %w", err)
+	}
 
 	// 初始化Service层
-	sceneService := service.NewSceneService(sceneRepo)
-	sentenceService := service.NewSentenceService(sentenceRepo)
-	progressService := service.NewProgressService(progressRepo)
+	sceneService := service.NewSceneService(sceneRepo, sentenceRepo)
+	sentenceService := service.NewSentenceService(sentenceRepo, sceneRepo, translationRepo, searchIndex)
+	progressService := service.NewProgressService(progressRepo, attemptRepo, sentenceRepo, sceneRepo, reviewRepo, vocabularyRepo, txManager)
+	gradingService := service.NewGradingService(sentenceRepo, sceneRepo)
+	reviewService := service.NewReviewService(reviewRepo)
+	authService := service.NewAuthService(userRepo, anonymousRepo, progressRepo, attemptRepo, reviewRepo, vocabularyRepo, txManager, tokens)
+	contentService := service.NewContentService(contentRepo, sceneRepo, sentenceRepo, searchIndex)
+	translationService := service.NewTranslationService(translationRepo, sentenceRepo, sceneRepo, searchIndex)
+	searchService := service.NewSearchService(searchIndex, sentenceRepo, translationRepo)
+	statsService := service.NewStatsService(progressRepo, attemptRepo, vocabularyRepo, sceneRepo)
+	vocabularyService := service.NewVocabularyService(vocabularyRepo, sentenceRepo, searchIndex)
+	sessionService := service.NewSessionService(sessionRepo, sceneRepo, sentenceRepo, progressRepo, reviewRepo, progressService, txManager)
+
+	// 构建搜索索引
+	indexed, err := searchService.Reindex(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to build search index: %w", err)
+	}
+	slog.Info("search index built", "sentences", indexed)
+
+	// 创建初始管理员
+	if cfg.Auth.AdminUsername != "" {
+		if err := authService.EnsureAdmin(context.Background(), cfg.Auth.AdminUsername, cfg.Auth.AdminPassword); err != nil {
+			return nil, fmt.Errorf("failed to ensure admin user: %w", err)
+		}
+	}
+	// 初始化指标，各子系统注册自己的指标
+	registry := metrics.NewRegistry()
+	if err := database.RegisterMetrics(registry, db); err != nil {
+		return nil, fmt.Errorf("failed to register database metrics: %w", err)
+	}
+	progressService.RegisterMetrics(registry)
+
+	// 初始化就绪检查
+	checker, err := newHealthChecker(db, audioCache)
+	if err != nil {
+		return nil, err
+	}
+
+	audioService := service.NewAudioService(sentenceRepo, synthesizer, audioCache, workers, cfg.TTS)
+	audioService.RegisterMetrics(registry)
 
 	// 初始化Handler层
-	sceneHandler := handler.NewSceneHandler(sceneService)
-	sentenceHandler := handler.NewSentenceHandler(sentenceService)
+	sceneHandler := handler.NewSceneHandler(sceneService, translationService)
+	sentenceHandler := handler.NewSentenceHandler(sentenceService, translationService)
 	progressHandler := handler.NewProgressHandler(progressService)
+	gradingHandler := handler.NewGradingHandler(gradingService, progressService)
+	audioHandler := handler.NewAudioHandler(audioService)
+	reviewHandler := handler.NewReviewHandler(reviewService)
+	authHandler := handler.NewAuthHandler(authService)
+	adminHandler := handler.NewAdminHandler(sceneService, sentenceService, contentService, translationService)
+	searchHandler := handler.NewSearchHandler(searchService, translationService)
+	healthHandler := handler.NewHealthHandler(checker)
+	statsHandler := handler.NewStatsHandler(statsService)
+	vocabularyHandler := handler.NewVocabularyHandler(vocabularyService)
+	sessionHandler := handler.NewSessionHandler(sessionService)
 
-	// 创建Gin引擎
-	r := gin.Default()
+	// 创建Gin引擎，请求日志由 RequestLogger 以结构化格式输出
+	r := gin.New()
+	r.Use(middleware.RequestLogger(slog.Default()), middleware.Metrics(registry), gin.Recovery())
 
 	// 配置CORS
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     cfg.Cors.AllowedOrigins,
 		AllowMethods:     cfg.Cors.AllowedMethods,
 		AllowHeaders:     cfg.Cors.AllowedHeaders,
+		ExposeHeaders:    []string{middleware.RequestIDHeader},
 		AllowCredentials: true,
 	}))
 
-	// 注册路由
-	setupRoutes(r, sceneHandler, sentenceHandler, progressHandler)
+	// 统一错误响应
+	r.Use(middleware.ErrorHandler())
 
-	// 启动服务
-	addr := ":" + cfg.Server.Port
-	log.Printf("Server starting on %s in %s mode", addr, cfg.Server.Mode)
-	if err := r.Run(addr); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
-	}
+	// 注册路由
+	setupRoutes(r, tokens, registry, healthHandler, sceneHandler, sentenceHandler, progressHandler, gradingHandler, audioHandler, reviewHandler, authHandler, adminHandler, searchHandler, statsHandler, vocabularyHandler, sessionHandler)
+	return r, nil
 }
 
 func setupRoutes(
 	r *gin.Engine,
+	tokens *auth.TokenManager,
+	registry *metrics.Registry,
+	healthHandler *handler.HealthHandler,
 	sceneHandler *handler.SceneHandler,
 	sentenceHandler *handler.SentenceHandler,
 	progressHandler *handler.ProgressHandler,
+	gradingHandler *handler.GradingHandler,
+	audioHandler *handler.AudioHandler,
+	reviewHandler *handler.ReviewHandler,
+	authHandler *handler.AuthHandler,
+	adminHandler *handler.AdminHandler,
+	searchHandler *handler.SearchHandler,
+	statsHandler *handler.StatsHandler,
+	vocabularyHandler *handler.VocabularyHandler,
+	sessionHandler *handler.SessionHandler,
 ) {
-	// 健康检查
-	r.GET("/health", handler.HealthCheck)
+	// 健康检查：/livez 只表示进程存活，/readyz 检查依赖；/health 为兼容旧版的存活探针
+	r.GET("/health", healthHandler.Livez)
+	r.GET("/livez", healthHandler.Livez)
+	r.GET("/readyz", healthHandler.Readyz)
+
+	// Prometheus 指标
+	r.GET("/metrics", gin.WrapH(registry.Handler()))
 
 	// API v1
 	v1 := r.Group("/api/v1")
 	{
+		requireAuth := middleware.Auth(tokens)
+		// 匿名令牌只能访问学习数据接口，账户相关接口需要注册用户
+		requireRegistered := middleware.RequireRole(model.RoleUser, model.RoleAdmin)
+
+		// 认证相关
+		authGroup := v1.Group("/auth")
+		{
+			authGroup.POST("/register", authHandler.Register)
+			authGroup.POST("/login", authHandler.Login)
+			authGroup.POST("/refresh", authHandler.Refresh)
+			authGroup.POST("/anonymous", authHandler.IssueAnonymous)
+			authGroup.GET("/me", requireAuth, requireRegistered, authHandler.Me)
+			authGroup.POST("/claim", requireAuth, requireRegistered, authHandler.ClaimAnonymous)
+		}
+
 		// 场景相关
 		scenes := v1.Group("/scenes")
 		{
@@ -107,19 +312,78 @@ func setupRoutes(
 			sentences.GET("", sentenceHandler.GetSentences)
 			sentences.GET("/:id", sentenceHandler.GetSentenceByID)
 			sentences.GET("/scene/:sceneId", sentenceHandler.GetSentencesByScene)
+			sentences.POST("/:id/check", middleware.OptionalAuth(tokens), gradingHandler.CheckAnswer)
 		}
 
+		// 全文搜索
+		v1.GET("/search", searchHandler.Search)
+
 		// 音频相关
 		audio := v1.Group("/audio")
 		{
-			audio.GET("/:id", handler.GetAudio)
+			audio.GET("/:id", audioHandler.GetAudio)
 		}
 
 		// 用户进度相关
-		progress := v1.Group("/progress")
+		progress := v1.Group("/progress", requireAuth)
 		{
-			progress.GET("/:userId", progressHandler.GetUserProgress)
+			progress.GET("", progressHandler.GetUserProgress)
+			progress.GET("/attempts", progressHandler.GetUserAttempts)
 			progress.POST("", progressHandler.SaveUserProgress)
 		}
+
+		// 学习统计
+		v1.GET("/stats", requireAuth, statsHandler.GetMyStats)
+
+		// 间隔重复复习
+		review := v1.Group("/review", requireAuth)
+		{
+			review.GET("/due", reviewHandler.GetDueReviews)
+		}
+
+		// 生词本
+		vocabulary := v1.Group("/vocabulary", requireAuth)
+		{
+			vocabulary.GET("", vocabularyHandler.GetVocabulary)
+			vocabulary.GET("/export", vocabularyHandler.ExportVocabulary)
+			vocabulary.GET("/practice", vocabularyHandler.GetPractice)
+			vocabulary.PATCH("/:id", vocabularyHandler.UpdateVocabulary)
+		}
+
+		// 练习会话
+		sessions := v1.Group("/sessions", requireAuth)
+		{
+			sessions.POST("", sessionHandler.StartSession)
+			sessions.GET("", sessionHandler.GetSessions)
+			sessions.GET("/:id", sessionHandler.GetSession)
+			sessions.POST("/:id/answers", sessionHandler.SubmitAnswer)
+			sessions.POST("/:id/finish", sessionHandler.FinishSession)
+		}
+
+		// 内容管理（仅管理员）
+		admin := v1.Group("/admin", requireAuth, middleware.RequireRole(model.RoleAdmin))
+		{
+			admin.POST("/scenes", adminHandler.CreateScene)
+			admin.PUT("/scenes/:id", adminHandler.UpdateScene)
+			admin.PATCH("/scenes/:id", adminHandler.PatchScene)
+			admin.DELETE("/scenes/:id", adminHandler.DeleteScene)
+			admin.GET("/scenes/:id/translations", adminHandler.ListSceneTranslations)
+			admin.PUT("/scenes/:id/translations/:locale", adminHandler.SetSceneTranslation)
+			admin.DELETE("/scenes/:id/translations/:locale", adminHandler.DeleteSceneTranslation)
+
+			admin.POST("/sentences", adminHandler.CreateSentence)
+			admin.PUT("/sentences/:id", adminHandler.UpdateSentence)
+			admin.PATCH("/sentences/:id", adminHandler.PatchSentence)
+			admin.DELETE("/sentences/:id", adminHandler.DeleteSentence)
+			admin.GET("/sentences/:id/translations", adminHandler.ListSentenceTranslations)
+			admin.PUT("/sentences/:id/translations/:locale", adminHandler.SetSentenceTranslation)
+			admin.DELETE("/sentences/:id/translations/:locale", adminHandler.DeleteSentenceTranslation)
+
+			admin.POST("/import", adminHandler.ImportContent)
+			admin.GET("/export", adminHandler.ExportContent)
+
+			admin.POST("/anonymous/claim", authHandler.ClaimLegacyAnonymous)
+			admin.GET("/stats/:userId", statsHandler.GetUserStats)
+		}
 	}
 }
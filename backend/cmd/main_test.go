@@ -0,0 +1,1168 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"voicewriter/internal/config"
+	"voicewriter/internal/database"
+	"voicewriter/internal/middleware"
+	"voicewriter/internal/model"
+	"voicewriter/internal/worker"
+	"voicewriter/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// testServer 基于 SQLite 内存数据库和完整路由的测试服务
+type testServer struct {
+	t       *testing.T
+	db      *gorm.DB
+	router  *gin.Engine
+	visited map[string]bool // 已请求的 "METHOD path"
+}
+
+// apiResponse 统一响应结构，Data 保留原始 JSON 便于按需解析
+type apiResponse struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+	Page    *struct {
+		Total      int64  `json:"total"`
+		NextCursor string `json:"next_cursor"`
+		HasMore    bool   `json:"has_more"`
+	} `json:"page"`
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+	return newTestServerWithDB(t, config.DatabaseConfig{Driver: database.DriverSQLite, Path: ":memory:"})
+}
+
+// newTestServerWithDB 使用指定的数据库配置创建测试服务
+func newTestServerWithDB(t *testing.T, dbConfig config.DatabaseConfig) *testServer {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	cfg := &config.Config{
+		Database: dbConfig,
+		TTS:      config.TTSConfig{Provider: "tone", CacheDir: t.TempDir(), Language: "en", Speed: 1},
+		Auth: config.AuthConfig{
+			JWTSecret:       "test-secret",
+			Issuer:          "voicewriter-test",
+			AccessTokenTTL:  3600,
+			RefreshTokenTTL: 86400,
+			AdminUsername:   "admin",
+			AdminPassword:   "adminpass1",
+		},
+		Cors: shippedCorsConfig(t),
+	}
+
+	db, err := database.NewDatabase(&cfg.Database)
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	db = db.Session(&gorm.Session{Logger: logger.Default.LogMode(logger.Silent)})
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		t.Fatalf("load migrations: %v", err)
+	}
+	if _, err := migrator.Up(context.Background(), 0); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if err := database.SeedData(db); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+
+	workers := worker.NewGroup()
+	t.Cleanup(func() { workers.Shutdown(context.Background()) })
+	router, err := newRouter(cfg, db, workers)
+	if err != nil {
+		t.Fatalf("new router: %v", err)
+	}
+	return &testServer{t: t, db: db, router: router, visited: make(map[string]bool)}
+}
+
+// shippedCorsConfig 读取 etc/config.yaml 中的 CORS 配置，预检测试覆盖实际发布的配置
+func shippedCorsConfig(t *testing.T) config.CorsConfig {
+	t.Helper()
+	cfg, err := config.LoadConfig("../etc/config.yaml")
+	if err != nil {
+		t.Fatalf("load etc/config.yaml: %v", err)
+	}
+	return cfg.Cors
+}
+
+// request 发送请求，body 为 []byte 时原样发送，其他非空值编码为 JSON
+func (s *testServer) request(method, path, token string, body interface{}, header ...string) *httptest.ResponseRecorder {
+	s.t.Helper()
+	var reader io.Reader
+	contentType := ""
+	switch b := body.(type) {
+	case nil:
+	case []byte:
+		reader = bytes.NewReader(b)
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			s.t.Fatalf("encode body: %v", err)
+		}
+		reader = bytes.NewReader(data)
+		contentType = "application/json"
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	s.visited[method+" "+strings.SplitN(path, "?", 2)[0]] = true
+	return w
+}
+
+// call 发送请求并检查状态码，data 非空时解析响应中的 data 字段
+func (s *testServer) call(method, path, token string, body interface{}, wantStatus int, data interface{}) *apiResponse {
+	s.t.Helper()
+	w := s.request(method, path, token, body)
+	if w.Code != wantStatus {
+		s.t.Fatalf("%s %s: status = %d, want %d; body: %s", method, path, w.Code, wantStatus, w.Body.String())
+	}
+	var resp apiResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		s.t.Fatalf("%s %s: decode response: %v; body: %s", method, path, err, w.Body.String())
+	}
+	if data != nil {
+		if err := json.Unmarshal(resp.Data, data); err != nil {
+			s.t.Fatalf("%s %s: decode data: %v; data: %s", method, path, err, resp.Data)
+		}
+	}
+	return &resp
+}
+
+// login 登录并返回访问令牌和刷新令牌
+func (s *testServer) login(username, password string) (string, string) {
+	s.t.Helper()
+	var result struct {
+		Tokens struct {
+			AccessToken  string `json:"access_token"`
+			RefreshToken string `json:"refresh_token"`
+		} `json:"tokens"`
+	}
+	s.call(http.MethodPost, "/api/v1/auth/login", "", gin.H{"username": username, "password": password}, http.StatusOK, &result)
+	return result.Tokens.AccessToken, result.Tokens.RefreshToken
+}
+
+// assertAllRoutesVisited 确认 setupRoutes 注册的每个路由都被请求过
+func (s *testServer) assertAllRoutesVisited() {
+	s.t.Helper()
+	param := regexp.MustCompile(`:[^/]+`)
+	for _, route := range s.router.Routes() {
+		pattern := regexp.MustCompile("^" + route.Method + " " + param.ReplaceAllString(route.Path, `[^/]+`) + "$")
+		found := false
+		for visited := range s.visited {
+			if pattern.MatchString(visited) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			s.t.Errorf("route %s %s is not covered", route.Method, route.Path)
+		}
+	}
+}
+
+func TestRoutes(t *testing.T) {
+	s := newTestServer(t)
+
+	t.Run("Health", func(t *testing.T) {
+		s.t = t
+		// /health 是存活探针的别名
+		for _, path := range []string{"/health", "/livez"} {
+			var live struct {
+				Status string `json:"status"`
+			}
+			s.call(http.MethodGet, path, "", nil, http.StatusOK, &live)
+			if live.Status != "up" {
+				t.Errorf("%s status = %q", path, live.Status)
+			}
+		}
+
+		var ready struct {
+			Status     string            `json:"status"`
+			Components map[string]string `json:"components"`
+		}
+		s.call(http.MethodGet, "/readyz", "", nil, http.StatusOK, &ready)
+		for _, name := range []string{"database", "migrations", "audio_cache"} {
+			if ready.Components[name] != "up" {
+				t.Errorf("readyz component %s = %q", name, ready.Components[name])
+			}
+		}
+	})
+
+	var userToken string
+	t.Run("Auth", func(t *testing.T) {
+		s.t = t
+		s.call(http.MethodPost, "/api/v1/auth/register", "", gin.H{"username": "alice", "password": "short"}, http.StatusBadRequest, nil)
+		s.call(http.MethodPost, "/api/v1/auth/register", "", gin.H{"username": "alice", "password": "alicepass1"}, http.StatusOK, nil)
+		s.call(http.MethodPost, "/api/v1/auth/register", "", gin.H{"username": "alice", "password": "alicepass1"}, http.StatusConflict, nil)
+		s.call(http.MethodPost, "/api/v1/auth/login", "", gin.H{"username": "alice", "password": "wrongpass1"}, http.StatusUnauthorized, nil)
+
+		var refresh string
+		userToken, refresh = s.login("alice", "alicepass1")
+		var refreshed struct {
+			Tokens struct {
+				AccessToken string `json:"access_token"`
+			} `json:"tokens"`
+		}
+		s.call(http.MethodPost, "/api/v1/auth/refresh", "", gin.H{"refresh_token": refresh}, http.StatusOK, &refreshed)
+		if refreshed.Tokens.AccessToken == "" {
+			t.Error("refresh returned no access token")
+		}
+		s.call(http.MethodPost, "/api/v1/auth/refresh", "", gin.H{"refresh_token": userToken}, http.StatusUnauthorized, nil)
+
+		var me struct {
+			Username string `json:"username"`
+			Role     string `json:"role"`
+		}
+		s.call(http.MethodGet, "/api/v1/auth/me", userToken, nil, http.StatusOK, &me)
+		if me.Username != "alice" || me.Role != "user" {
+			t.Errorf("me = %+v", me)
+		}
+		s.call(http.MethodGet, "/api/v1/auth/me", "", nil, http.StatusUnauthorized, nil)
+	})
+
+	t.Run("AnonymousClaim", func(t *testing.T) {
+		s.t = t
+		var carol struct {
+			User struct {
+				UID string `json:"uid"`
+			} `json:"user"`
+			Tokens struct {
+				AccessToken string `json:"access_token"`
+			} `json:"tokens"`
+		}
+		s.call(http.MethodPost, "/api/v1/auth/register", "", gin.H{"username": "carol", "password": "carolpass1"}, http.StatusOK, &carol)
+		carolToken := carol.Tokens.AccessToken
+
+		var anonymous struct {
+			AnonymousID string `json:"anonymous_id"`
+			ClaimSecret string `json:"claim_secret"`
+			Tokens      struct {
+				AccessToken  string `json:"access_token"`
+				RefreshToken string `json:"refresh_token"`
+			} `json:"tokens"`
+		}
+		s.call(http.MethodPost, "/api/v1/auth/anonymous", "", nil, http.StatusOK, &anonymous)
+		if anonymous.AnonymousID == "" || anonymous.ClaimSecret == "" || anonymous.Tokens.AccessToken == "" {
+			t.Fatalf("anonymous = %+v", anonymous)
+		}
+
+		// 匿名令牌可以记录进度，但不能访问账户接口
+		anonToken := anonymous.Tokens.AccessToken
+		s.call(http.MethodPost, "/api/v1/progress", anonToken, gin.H{"sentence_id": 1, "submitted_text": "Hello, how are you?"}, http.StatusOK, nil)
+		s.call(http.MethodGet, "/api/v1/auth/me", anonToken, nil, http.StatusForbidden, nil)
+		s.call(http.MethodPost, "/api/v1/auth/claim", anonToken,
+			gin.H{"anonymous_id": anonymous.AnonymousID, "claim_secret": anonymous.ClaimSecret}, http.StatusForbidden, nil)
+		var refreshed struct {
+			User   *json.RawMessage `json:"user"`
+			Tokens struct {
+				AccessToken string `json:"access_token"`
+			} `json:"tokens"`
+		}
+		s.call(http.MethodPost, "/api/v1/auth/refresh", "", gin.H{"refresh_token": anonymous.Tokens.RefreshToken}, http.StatusOK, &refreshed)
+		if refreshed.User != nil || refreshed.Tokens.AccessToken == "" {
+			t.Errorf("anonymous refresh = %+v", refreshed)
+		}
+
+		s.call(http.MethodPost, "/api/v1/auth/claim", carolToken,
+			gin.H{"anonymous_id": anonymous.AnonymousID, "claim_secret": "wrong"}, http.StatusForbidden, nil)
+		s.call(http.MethodPost, "/api/v1/auth/claim", carolToken,
+			gin.H{"anonymous_id": "anon-never-issued", "claim_secret": anonymous.ClaimSecret}, http.StatusForbidden, nil)
+
+		var claimed struct {
+			Progress int64 `json:"progress"`
+			Attempts int64 `json:"attempts"`
+		}
+		claim := gin.H{"anonymous_id": anonymous.AnonymousID, "claim_secret": anonymous.ClaimSecret}
+		s.call(http.MethodPost, "/api/v1/auth/claim", carolToken, claim, http.StatusOK, &claimed)
+		if claimed.Progress != 1 || claimed.Attempts != 1 {
+			t.Errorf("claimed = %+v, want 1 progress and 1 attempt", claimed)
+		}
+		var progress []json.RawMessage
+		s.call(http.MethodGet, "/api/v1/progress", carolToken, nil, http.StatusOK, &progress)
+		if len(progress) != 1 {
+			t.Errorf("carol progress after claim = %d, want 1", len(progress))
+		}
+		// 同一匿名ID只能认领一次，认领后匿名令牌不能再刷新
+		s.call(http.MethodPost, "/api/v1/auth/claim", carolToken, claim, http.StatusConflict, nil)
+		s.call(http.MethodPost, "/api/v1/auth/refresh", "", gin.H{"refresh_token": anonymous.Tokens.RefreshToken}, http.StatusUnauthorized, nil)
+
+		// 服务端签发匿名ID之前客户端自行生成的ID，由管理员迁移
+		legacyID := "legacy-device-1"
+		if err := s.db.Create(&model.UserProgress{UserID: legacyID, SentenceID: 2, Attempts: 1}).Error; err != nil {
+			t.Fatal(err)
+		}
+		adminToken, _ := s.login("admin", "adminpass1")
+		legacy := gin.H{"anonymous_id": legacyID, "user_uid": carol.User.UID}
+		s.call(http.MethodPost, "/api/v1/admin/anonymous/claim", carolToken, legacy, http.StatusForbidden, nil)
+		s.call(http.MethodPost, "/api/v1/admin/anonymous/claim", adminToken, gin.H{"anonymous_id": legacyID, "user_uid": "nobody"}, http.StatusNotFound, nil)
+		s.call(http.MethodPost, "/api/v1/admin/anonymous/claim", adminToken, gin.H{"anonymous_id": carol.User.UID, "user_uid": carol.User.UID}, http.StatusBadRequest, nil)
+		s.call(http.MethodPost, "/api/v1/admin/anonymous/claim", adminToken, gin.H{"anonymous_id": anonymous.AnonymousID, "user_uid": carol.User.UID}, http.StatusConflict, nil)
+		s.call(http.MethodPost, "/api/v1/admin/anonymous/claim", adminToken, legacy, http.StatusOK, &claimed)
+		if claimed.Progress != 1 {
+			t.Errorf("legacy claimed progress = %d, want 1", claimed.Progress)
+		}
+		s.call(http.MethodPost, "/api/v1/admin/anonymous/claim", adminToken, legacy, http.StatusConflict, nil)
+		var issued struct {
+			AnonymousID string `json:"anonymous_id"`
+		}
+		s.call(http.MethodPost, "/api/v1/auth/anonymous", "", nil, http.StatusOK, &issued)
+		s.call(http.MethodPost, "/api/v1/admin/anonymous/claim", adminToken, gin.H{"anonymous_id": issued.AnonymousID, "user_uid": carol.User.UID}, http.StatusConflict, nil)
+	})
+
+	t.Run("Scenes", func(t *testing.T) {
+		s.t = t
+		var scenes []struct {
+			ID   uint   `json:"id"`
+			Name string `json:"name"`
+		}
+		resp := s.call(http.MethodGet, "/api/v1/scenes?sort=name&limit=2", "", nil, http.StatusOK, &scenes)
+		if len(scenes) != 2 || resp.Page == nil || resp.Page.Total != 3 || !resp.Page.HasMore {
+			t.Errorf("scenes = %+v, page = %+v", scenes, resp.Page)
+		}
+		s.call(http.MethodGet, "/api/v1/scenes?sort=unknown", "", nil, http.StatusBadRequest, nil)
+
+		var scene struct {
+			Name string `json:"name"`
+		}
+		s.call(http.MethodGet, "/api/v1/scenes/1", "", nil, http.StatusOK, &scene)
+		if scene.Name != "日常生活" {
+			t.Errorf("scene 1 = %q", scene.Name)
+		}
+		s.call(http.MethodGet, "/api/v1/scenes/999", "", nil, http.StatusNotFound, nil)
+		s.call(http.MethodGet, "/api/v1/scenes/abc", "", nil, http.StatusBadRequest, nil)
+	})
+
+	t.Run("Sentences", func(t *testing.T) {
+		s.t = t
+		var sentences []struct {
+			ID      uint `json:"id"`
+			SceneID uint `json:"scene_id"`
+		}
+		resp := s.call(http.MethodGet, "/api/v1/sentences?scene_id=1&limit=1", "", nil, http.StatusOK, &sentences)
+		if len(sentences) != 1 || sentences[0].SceneID != 1 || resp.Page.Total < 2 {
+			t.Errorf("sentences = %+v, page = %+v", sentences, resp.Page)
+		}
+		s.call(http.MethodGet, "/api/v1/sentences?difficulty=impossible", "", nil, http.StatusBadRequest, nil)
+
+		var sentence struct {
+			Content string `json:"content"`
+		}
+		s.call(http.MethodGet, "/api/v1/sentences/1", "", nil, http.StatusOK, &sentence)
+		if sentence.Content == "" {
+			t.Error("sentence 1 has no content")
+		}
+		s.call(http.MethodGet, "/api/v1/sentences/999", "", nil, http.StatusNotFound, nil)
+
+		s.call(http.MethodGet, "/api/v1/sentences/scene/2", "", nil, http.StatusOK, &sentences)
+		if len(sentences) == 0 || sentences[0].SceneID != 2 {
+			t.Errorf("sentences by scene 2 = %+v", sentences)
+		}
+
+		var check struct {
+			Accuracy float64 `json:"accuracy"`
+		}
+		s.call(http.MethodPost, "/api/v1/sentences/1/check", "", gin.H{"answer": sentence.Content}, http.StatusOK, &check)
+		if check.Accuracy != 100 {
+			t.Errorf("exact answer accuracy = %v, want 100", check.Accuracy)
+		}
+		s.call(http.MethodPost, "/api/v1/sentences/1/check", userToken, gin.H{"answer": "something else"}, http.StatusOK, &check)
+		if check.Accuracy >= 100 {
+			t.Errorf("wrong answer accuracy = %v", check.Accuracy)
+		}
+		s.call(http.MethodPost, "/api/v1/sentences/1/check", "", gin.H{}, http.StatusBadRequest, nil)
+		s.call(http.MethodPost, "/api/v1/sentences/2/check", "", gin.H{"answer": "what is your name"}, http.StatusOK, &check)
+		if check.Accuracy != 100 {
+			t.Errorf("expanded contraction accuracy = %v, want 100", check.Accuracy)
+		}
+	})
+
+	t.Run("Search", func(t *testing.T) {
+		s.t = t
+		var results []struct {
+			Sentence struct {
+				ID uint `json:"id"`
+			} `json:"sentence"`
+			Highlights map[string]string `json:"highlights"`
+		}
+		s.call(http.MethodGet, "/api/v1/search?q=meeting", "", nil, http.StatusOK, &results)
+		if len(results) == 0 || !strings.Contains(results[0].Highlights["content"], "<em>") {
+			t.Errorf("search meeting = %+v", results)
+		}
+		s.call(http.MethodGet, "/api/v1/search", "", nil, http.StatusBadRequest, nil)
+	})
+
+	t.Run("Audio", func(t *testing.T) {
+		s.t = t
+		w := s.request(http.MethodGet, "/api/v1/audio/1", "", nil)
+		if w.Code != http.StatusOK || w.Body.Len() == 0 {
+			t.Fatalf("audio = %d (%d bytes)", w.Code, w.Body.Len())
+		}
+		etag := w.Header().Get("ETag")
+		if w := s.request(http.MethodGet, "/api/v1/audio/1", "", nil, "If-None-Match", etag); w.Code != http.StatusNotModified {
+			t.Errorf("audio with matching etag = %d, want 304", w.Code)
+		}
+		if w := s.request(http.MethodGet, "/api/v1/audio/1", "", nil, "Range", "bytes=0-9"); w.Code != http.StatusPartialContent || w.Body.Len() != 10 {
+			t.Errorf("audio range = %d (%d bytes)", w.Code, w.Body.Len())
+		}
+		if w := s.request(http.MethodGet, "/api/v1/audio/999", "", nil); w.Code != http.StatusNotFound {
+			t.Errorf("missing audio = %d, want 404", w.Code)
+		}
+	})
+
+	t.Run("Progress", func(t *testing.T) {
+		s.t = t
+		s.call(http.MethodGet, "/api/v1/progress", "", nil, http.StatusUnauthorized, nil)
+		s.call(http.MethodPost, "/api/v1/progress", userToken, gin.H{"sentence_id": 2, "completed": true, "submitted_text": "What's your name?", "accuracy": 90}, http.StatusOK, nil)
+		// 完成状态只能由服务端评分得出，未提交作答文本时不能标记完成
+		s.call(http.MethodPost, "/api/v1/progress", userToken, gin.H{"sentence_id": 2, "completed": true}, http.StatusBadRequest, nil)
+
+		var progress []struct {
+			SentenceID uint `json:"sentence_id"`
+		}
+		s.call(http.MethodGet, "/api/v1/progress", userToken, nil, http.StatusOK, &progress)
+		found := false
+		for _, p := range progress {
+			found = found || p.SentenceID == 2
+		}
+		if !found {
+			t.Errorf("progress = %+v, want sentence 2", progress)
+		}
+
+		var attempts []struct {
+			SentenceID uint `json:"sentence_id"`
+		}
+		s.call(http.MethodGet, "/api/v1/progress/attempts?sentence_id=1", userToken, nil, http.StatusOK, &attempts)
+		if len(attempts) != 1 {
+			t.Errorf("attempts for sentence 1 = %d, want 1 from the authenticated check", len(attempts))
+		}
+		s.call(http.MethodGet, "/api/v1/progress/attempts?from=yesterday", userToken, nil, http.StatusBadRequest, nil)
+
+		// 请求体中只有句子、完成状态和作答详情生效，其余字段由服务端维护
+		s.call(http.MethodPost, "/api/v1/progress", userToken, gin.H{
+			"sentence_id": 3,
+			"id":          999,
+			"user_id":     "mallory",
+			"attempts":    50,
+			"sentence":    gin.H{"id": 3, "content": "overwritten"},
+		}, http.StatusOK, nil)
+		var raw []map[string]interface{}
+		s.call(http.MethodGet, "/api/v1/progress", userToken, nil, http.StatusOK, &raw)
+		for _, p := range raw {
+			if _, ok := p["user_id"]; ok {
+				t.Errorf("progress response exposes user_id: %v", p)
+			}
+			if p["sentence_id"] == float64(3) && (p["id"] == float64(999) || p["attempts"] != float64(1)) {
+				t.Errorf("progress for sentence 3 = %v, want server-assigned id and 1 attempt", p)
+			}
+		}
+		var sentence struct {
+			Content string `json:"content"`
+		}
+		s.call(http.MethodGet, "/api/v1/sentences/3", "", nil, http.StatusOK, &sentence)
+		if sentence.Content == "overwritten" {
+			t.Error("nested sentence in the progress body was written to the database")
+		}
+		s.call(http.MethodPost, "/api/v1/progress", userToken, gin.H{"sentence_id": 3, "duration_ms": -1}, http.StatusBadRequest, nil)
+		s.call(http.MethodPost, "/api/v1/progress", userToken, gin.H{"completed": true}, http.StatusBadRequest, nil)
+	})
+
+	t.Run("Review", func(t *testing.T) {
+		s.t = t
+		s.call(http.MethodGet, "/api/v1/review/due", "", nil, http.StatusUnauthorized, nil)
+		s.call(http.MethodGet, "/api/v1/review/due", userToken, nil, http.StatusOK, nil)
+	})
+
+	t.Run("Stats", func(t *testing.T) {
+		s.t = t
+		var me struct {
+			UID string `json:"uid"`
+		}
+		s.call(http.MethodGet, "/api/v1/auth/me", userToken, nil, http.StatusOK, &me)
+		statsPath := "/api/v1/stats"
+
+		var stats struct {
+			Summary struct {
+				CompletedSentences int64 `json:"completed_sentences"`
+				TotalAttempts      int64 `json:"total_attempts"`
+				CurrentStreak      int   `json:"current_streak"`
+			} `json:"summary"`
+			Scenes        []json.RawMessage `json:"scenes"`
+			Difficulties  []json.RawMessage `json:"difficulties"`
+			AccuracyTrend []struct {
+				Attempts int `json:"attempts"`
+			} `json:"accuracy_trend"`
+			MissedWords []struct {
+				Word  string `json:"word"`
+				Count int    `json:"count"`
+			} `json:"missed_words"`
+			Comparison struct {
+				Users              int `json:"users"`
+				CompletedSentences struct {
+					Value      float64 `json:"value"`
+					Median     float64 `json:"median"`
+					Percentile float64 `json:"percentile"`
+				} `json:"completed_sentences"`
+			} `json:"comparison"`
+		}
+		s.call(http.MethodGet, statsPath+"?tz=Asia/Shanghai", userToken, nil, http.StatusOK, &stats)
+		if stats.Summary.CompletedSentences != 1 || stats.Summary.TotalAttempts != 3 || stats.Summary.CurrentStreak != 1 {
+			t.Errorf("summary = %+v", stats.Summary)
+		}
+		if len(stats.Scenes) == 0 || len(stats.Difficulties) == 0 {
+			t.Errorf("scenes = %d, difficulties = %d", len(stats.Scenes), len(stats.Difficulties))
+		}
+		if len(stats.AccuracyTrend) != 1 || stats.AccuracyTrend[0].Attempts != 3 {
+			t.Errorf("accuracy trend = %+v", stats.AccuracyTrend)
+		}
+		// 对 "Hello, how are you?" 的作答是 "something else"，易错词来自生词本
+		missed := make(map[string]int)
+		for _, w := range stats.MissedWords {
+			missed[w.Word] = w.Count
+		}
+		if len(missed) != 4 || missed["hello"] != 1 || missed["how"] != 1 || missed["are"] != 1 || missed["you"] != 1 {
+			t.Errorf("missed words = %+v", stats.MissedWords)
+		}
+		// alice 和认领了匿名进度的 carol 各完成 1 句
+		if c := stats.Comparison; c.Users != 2 || c.CompletedSentences.Median != 1 || c.CompletedSentences.Percentile != 50 {
+			t.Errorf("comparison = %+v", c)
+		}
+
+		// 管理员通过管理接口查看任意用户，普通用户不能访问
+		adminToken, _ := s.login("admin", "adminpass1")
+		var viewed struct {
+			UserID  string `json:"user_id"`
+			Summary struct {
+				CompletedSentences int64 `json:"completed_sentences"`
+			} `json:"summary"`
+		}
+		s.call(http.MethodGet, "/api/v1/admin/stats/"+me.UID, adminToken, nil, http.StatusOK, &viewed)
+		if viewed.UserID != me.UID || viewed.Summary.CompletedSentences != 1 {
+			t.Errorf("admin view = %+v", viewed)
+		}
+		s.call(http.MethodGet, "/api/v1/admin/stats/"+me.UID, userToken, nil, http.StatusForbidden, nil)
+		s.call(http.MethodGet, statsPath, "", nil, http.StatusUnauthorized, nil)
+		s.call(http.MethodGet, statsPath+"?days=1000", userToken, nil, http.StatusBadRequest, nil)
+		s.call(http.MethodGet, statsPath+"?tz=Mars/Olympus", userToken, nil, http.StatusBadRequest, nil)
+	})
+
+	t.Run("Vocabulary", func(t *testing.T) {
+		s.t = t
+		type entry struct {
+			ID        uint   `json:"id"`
+			Word      string `json:"word"`
+			MissCount int    `json:"miss_count"`
+			Starred   bool   `json:"starred"`
+			Archived  bool   `json:"archived"`
+			Sentence  *struct {
+				Content string `json:"content"`
+			} `json:"sentence"`
+		}
+		s.call(http.MethodGet, "/api/v1/vocabulary", "", nil, http.StatusUnauthorized, nil)
+
+		// 之前对 "Hello, how are you?" 的作答是 "something else"，再漏写一次 you
+		s.call(http.MethodPost, "/api/v1/sentences/1/check", userToken, gin.H{"answer": "hello how are"}, http.StatusOK, nil)
+
+		var entries []entry
+		resp := s.call(http.MethodGet, "/api/v1/vocabulary", userToken, nil, http.StatusOK, &entries)
+		if resp.Page == nil || resp.Page.Total != 4 || len(entries) != 4 {
+			t.Fatalf("vocabulary = %+v, page = %+v", entries, resp.Page)
+		}
+		if entries[0].Word != "you" || entries[0].MissCount != 2 || entries[0].Sentence == nil {
+			t.Errorf("weakest word = %+v, want you missed twice with its sentence", entries[0])
+		}
+		weakest := entries[0]
+
+		// 按出错次数的游标翻页
+		resp = s.call(http.MethodGet, "/api/v1/vocabulary?limit=1", userToken, nil, http.StatusOK, nil)
+		if !resp.Page.HasMore || resp.Page.NextCursor == "" {
+			t.Fatalf("first page = %+v", resp.Page)
+		}
+		s.call(http.MethodGet, "/api/v1/vocabulary?limit=1&cursor="+resp.Page.NextCursor, userToken, nil, http.StatusOK, &entries)
+		if len(entries) != 1 || entries[0].Word == "you" {
+			t.Errorf("second page = %+v", entries)
+		}
+		s.call(http.MethodGet, "/api/v1/vocabulary?starred=maybe", userToken, nil, http.StatusBadRequest, nil)
+
+		var updated entry
+		s.call(http.MethodPatch, fmt.Sprintf("/api/v1/vocabulary/%d", weakest.ID), userToken, gin.H{"starred": true}, http.StatusOK, &updated)
+		if !updated.Starred || updated.MissCount != 2 {
+			t.Errorf("starred entry = %+v", updated)
+		}
+		s.call(http.MethodPatch, fmt.Sprintf("/api/v1/vocabulary/%d", entries[0].ID), userToken, gin.H{"archived": true}, http.StatusOK, nil)
+		s.call(http.MethodPatch, fmt.Sprintf("/api/v1/vocabulary/%d", weakest.ID), userToken, gin.H{}, http.StatusBadRequest, nil)
+		adminToken, _ := s.login("admin", "adminpass1")
+		s.call(http.MethodPatch, fmt.Sprintf("/api/v1/vocabulary/%d", weakest.ID), adminToken, gin.H{"starred": true}, http.StatusNotFound, nil)
+
+		resp = s.call(http.MethodGet, "/api/v1/vocabulary", userToken, nil, http.StatusOK, nil)
+		if resp.Page.Total != 3 {
+			t.Errorf("unarchived total = %d, want 3", resp.Page.Total)
+		}
+		s.call(http.MethodGet, "/api/v1/vocabulary?archived=true", userToken, nil, http.StatusOK, &entries)
+		if len(entries) != 1 || !entries[0].Archived {
+			t.Errorf("archived = %+v", entries)
+		}
+
+		var practice struct {
+			Words     []entry `json:"words"`
+			Sentences []struct {
+				Sentence struct {
+					ID uint `json:"id"`
+				} `json:"sentence"`
+				Words []string `json:"words"`
+			} `json:"sentences"`
+		}
+		s.call(http.MethodGet, "/api/v1/vocabulary/practice?limit=5", userToken, nil, http.StatusOK, &practice)
+		if len(practice.Words) != 3 || len(practice.Sentences) == 0 || len(practice.Sentences) > 5 {
+			t.Fatalf("practice = %+v", practice)
+		}
+		if first := practice.Sentences[0]; first.Sentence.ID != 1 || len(first.Words) != 3 {
+			t.Errorf("first practice sentence = %+v, want sentence 1 with all unarchived words", first)
+		}
+		s.call(http.MethodGet, "/api/v1/vocabulary/practice?limit=x", userToken, nil, http.StatusBadRequest, nil)
+
+		w := s.request(http.MethodGet, "/api/v1/vocabulary/export", userToken, nil)
+		lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+		if w.Code != http.StatusOK || len(lines) != 5 || !strings.HasPrefix(lines[0], "word,language,miss_count") {
+			t.Errorf("csv export = %d %s", w.Code, w.Body.String())
+		}
+		w = s.request(http.MethodGet, "/api/v1/vocabulary/export?format=jsonl&starred=true", userToken, nil)
+		if w.Code != http.StatusOK || strings.Count(w.Body.String(), "\n") != 1 || !strings.Contains(w.Body.String(), `"sentence":"Hello, how are you?"`) {
+			t.Errorf("jsonl export = %d %s", w.Code, w.Body.String())
+		}
+		if !strings.Contains(w.Header().Get("Content-Disposition"), ".jsonl") {
+			t.Errorf("export disposition = %q", w.Header().Get("Content-Disposition"))
+		}
+		s.call(http.MethodGet, "/api/v1/vocabulary/export?format=apkg", userToken, nil, http.StatusBadRequest, nil)
+	})
+
+	t.Run("CORS", func(t *testing.T) {
+		s.t = t
+		const origin = "http://localhost:3000"
+		w := s.request(http.MethodOptions, "/api/v1/vocabulary/1", "", nil,
+			"Origin", origin,
+			"Access-Control-Request-Method", http.MethodPatch,
+			"Access-Control-Request-Headers", "Authorization, Content-Type")
+		if w.Code != http.StatusNoContent || w.Header().Get("Access-Control-Allow-Origin") != origin {
+			t.Fatalf("preflight = %d, headers %v", w.Code, w.Header())
+		}
+		allowed := strings.Split(w.Header().Get("Access-Control-Allow-Methods"), ",")
+		// 每个路由用到的方法都要能通过预检
+		for _, route := range s.router.Routes() {
+			found := false
+			for _, method := range allowed {
+				if strings.EqualFold(strings.TrimSpace(method), route.Method) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				t.Errorf("preflight Access-Control-Allow-Methods %q does not allow %s %s", allowed, route.Method, route.Path)
+			}
+		}
+	})
+
+	t.Run("Sessions", func(t *testing.T) {
+		s.t = t
+		type session struct {
+			ID           uint   `json:"id"`
+			Status       string `json:"status"`
+			Total        int    `json:"total"`
+			Answered     int    `json:"answered"`
+			NextPosition *int   `json:"next_position"`
+			Items        []struct {
+				Position   int  `json:"position"`
+				SentenceID uint `json:"sentence_id"`
+				Answered   bool `json:"answered"`
+				Sentence   *struct {
+					Content string `json:"content"`
+				} `json:"sentence"`
+			} `json:"items"`
+			Summary *struct {
+				Answered    int      `json:"answered"`
+				Completed   int      `json:"completed"`
+				Skipped     int      `json:"skipped"`
+				Accuracy    float64  `json:"accuracy"`
+				MissedWords []string `json:"missed_words"`
+				Retry       []struct {
+					ID uint `json:"id"`
+				} `json:"retry"`
+			} `json:"summary"`
+		}
+		s.call(http.MethodPost, "/api/v1/sessions", "", gin.H{"mode": "scene", "scene_id": 1}, http.StatusUnauthorized, nil)
+		s.call(http.MethodPost, "/api/v1/sessions", userToken, gin.H{"mode": "bogus"}, http.StatusBadRequest, nil)
+		s.call(http.MethodPost, "/api/v1/sessions", userToken, gin.H{"mode": "scene"}, http.StatusBadRequest, nil)
+		s.call(http.MethodPost, "/api/v1/sessions", userToken, gin.H{"mode": "scene", "scene_id": 999}, http.StatusNotFound, nil)
+
+		// 场景 1 中句子 2 已完成，排在未完成的句子 1、3 之后
+		var started session
+		s.call(http.MethodPost, "/api/v1/sessions", userToken, gin.H{"mode": "scene", "scene_id": 1}, http.StatusOK, &started)
+		if started.Status != "active" || started.Total != 3 || len(started.Items) != 3 || started.NextPosition == nil || *started.NextPosition != 0 {
+			t.Fatalf("started session = %+v", started)
+		}
+		order := []uint{started.Items[0].SentenceID, started.Items[1].SentenceID, started.Items[2].SentenceID}
+		if order[0] != 1 || order[1] != 3 || order[2] != 2 || started.Items[0].Sentence == nil {
+			t.Errorf("session order = %v, want [1 3 2] with sentences", order)
+		}
+		sessionPath := fmt.Sprintf("/api/v1/sessions/%d", started.ID)
+
+		var answer struct {
+			Item struct {
+				Completed bool     `json:"completed"`
+				Score     float64  `json:"score"`
+				Missed    []string `json:"missed_words"`
+			} `json:"item"`
+			Result struct {
+				Correct bool `json:"correct"`
+			} `json:"result"`
+			NextPosition *int `json:"next_position"`
+		}
+		s.call(http.MethodPost, sessionPath+"/answers", userToken, gin.H{"sentence_id": 1, "answer": "Hello, how are you?", "duration_ms": 3000}, http.StatusOK, &answer)
+		if !answer.Item.Completed || !answer.Result.Correct || answer.NextPosition == nil || *answer.NextPosition != 1 {
+			t.Errorf("correct answer = %+v", answer)
+		}
+		s.call(http.MethodPost, sessionPath+"/answers", userToken, gin.H{"sentence_id": 3, "answer": "nice to meet", "duration_ms": 2000}, http.StatusOK, &answer)
+		if answer.Item.Completed || len(answer.Item.Missed) != 1 || answer.Item.Missed[0] != "you" {
+			t.Errorf("partial answer = %+v", answer)
+		}
+		// 进度中的作答记录沿用会话的评分结果
+		var stored struct {
+			Score     float64
+			Completed bool
+		}
+		if err := s.db.Table("attempts").Select("score, completed").Where("sentence_id = ?", 3).Order("id DESC").Take(&stored).Error; err != nil {
+			t.Fatalf("read attempt: %v", err)
+		}
+		if stored.Score != answer.Item.Score || stored.Completed {
+			t.Errorf("stored attempt = %+v, want score %v", stored, answer.Item.Score)
+		}
+		s.call(http.MethodPost, sessionPath+"/answers", userToken, gin.H{"sentence_id": 5, "answer": "hi"}, http.StatusBadRequest, nil)
+		s.call(http.MethodPost, sessionPath+"/answers", userToken, gin.H{"sentence_id": 1}, http.StatusBadRequest, nil)
+
+		// 在其他设备上继续：从下一句开始
+		var resumed session
+		s.call(http.MethodGet, sessionPath, userToken, nil, http.StatusOK, &resumed)
+		if resumed.Answered != 2 || resumed.NextPosition == nil || *resumed.NextPosition != 2 || !resumed.Items[1].Answered || resumed.Items[2].Answered {
+			t.Errorf("resumed session = %+v", resumed)
+		}
+		adminToken, _ := s.login("admin", "adminpass1")
+		s.call(http.MethodGet, sessionPath, adminToken, nil, http.StatusNotFound, nil)
+		s.call(http.MethodPost, sessionPath+"/finish", adminToken, nil, http.StatusNotFound, nil)
+
+		var list []session
+		resp := s.call(http.MethodGet, "/api/v1/sessions?status=active", userToken, nil, http.StatusOK, &list)
+		if resp.Page == nil || resp.Page.Total != 1 || len(list) != 1 || list[0].ID != started.ID || list[0].Items != nil {
+			t.Errorf("active sessions = %+v, page = %+v", list, resp.Page)
+		}
+		s.call(http.MethodGet, "/api/v1/sessions?status=paused", userToken, nil, http.StatusBadRequest, nil)
+
+		var finished session
+		s.call(http.MethodPost, sessionPath+"/finish", userToken, nil, http.StatusOK, &finished)
+		summary := finished.Summary
+		if finished.Status != "finished" || summary == nil || summary.Answered != 2 || summary.Completed != 1 || summary.Skipped != 1 {
+			t.Fatalf("finished session = %+v, summary = %+v", finished, summary)
+		}
+		if summary.Accuracy <= 50 || summary.Accuracy >= 100 || len(summary.MissedWords) != 1 || summary.MissedWords[0] != "you" {
+			t.Errorf("summary = %+v", summary)
+		}
+		if len(summary.Retry) != 1 || summary.Retry[0].ID != 3 {
+			t.Errorf("retry = %+v, want sentence 3", summary.Retry)
+		}
+		s.call(http.MethodPost, sessionPath+"/finish", userToken, nil, http.StatusOK, &finished)
+		if finished.Summary == nil || finished.Summary.Answered != 2 {
+			t.Errorf("finishing twice = %+v", finished)
+		}
+		s.call(http.MethodPost, sessionPath+"/answers", userToken, gin.H{"sentence_id": 2, "answer": "What's your name?"}, http.StatusConflict, nil)
+		s.call(http.MethodGet, "/api/v1/sessions?status=finished", userToken, nil, http.StatusOK, &list)
+		if len(list) != 1 || list[0].Answered != 2 {
+			t.Errorf("finished sessions = %+v", list)
+		}
+
+		// 复习会话按到期时间选取句子
+		if err := s.db.Exec("UPDATE review_states SET due_at = ?", time.Now().Add(-time.Hour)).Error; err != nil {
+			t.Fatalf("make reviews due: %v", err)
+		}
+		var review session
+		s.call(http.MethodPost, "/api/v1/sessions", userToken, gin.H{"mode": "review", "limit": 2}, http.StatusOK, &review)
+		if review.Total != 2 || len(review.Items) != 2 {
+			t.Errorf("review session = %+v", review)
+		}
+	})
+
+	t.Run("Admin", func(t *testing.T) {
+		s.t = t
+		adminToken, _ := s.login("admin", "adminpass1")
+		s.call(http.MethodPost, "/api/v1/admin/scenes", userToken, gin.H{"name": "新场景"}, http.StatusForbidden, nil)
+
+		var scene struct {
+			ID          uint   `json:"id"`
+			Name        string `json:"name"`
+			Description string `json:"description"`
+		}
+		s.call(http.MethodPost, "/api/v1/admin/scenes", adminToken, gin.H{"name": "餐厅点餐", "description": "点餐"}, http.StatusOK, &scene)
+		s.call(http.MethodPost, "/api/v1/admin/scenes", adminToken, gin.H{"name": "餐厅点餐"}, http.StatusConflict, nil)
+		scenePath := fmt.Sprintf("/api/v1/admin/scenes/%d", scene.ID)
+		s.call(http.MethodPut, scenePath, adminToken, gin.H{"name": "餐厅", "description": "点餐和结账"}, http.StatusOK, &scene)
+		s.call(http.MethodPatch, scenePath, adminToken, gin.H{"icon": "restaurant"}, http.StatusOK, &scene)
+		if scene.Name != "餐厅" || scene.Description != "点餐和结账" {
+			t.Errorf("patched scene = %+v", scene)
+		}
+
+		s.call(http.MethodPut, scenePath+"/translations/en", adminToken, gin.H{"name": "Restaurant"}, http.StatusOK, nil)
+		var sceneTranslations []struct {
+			Locale string `json:"locale"`
+		}
+		s.call(http.MethodGet, scenePath+"/translations", adminToken, nil, http.StatusOK, &sceneTranslations)
+		if len(sceneTranslations) != 1 || sceneTranslations[0].Locale != "en" {
+			t.Errorf("scene translations = %+v", sceneTranslations)
+		}
+		s.call(http.MethodGet, fmt.Sprintf("/api/v1/scenes/%d?lang=en", scene.ID), "", nil, http.StatusOK, &scene)
+		if scene.Name != "Restaurant" {
+			t.Errorf("localized scene name = %q", scene.Name)
+		}
+		s.call(http.MethodDelete, scenePath+"/translations/en", adminToken, nil, http.StatusOK, nil)
+
+		var sentence struct {
+			ID      uint   `json:"id"`
+			Content string `json:"content"`
+		}
+		s.call(http.MethodPost, "/api/v1/admin/sentences", adminToken, gin.H{"scene_id": 999, "content": "orphan"}, http.StatusBadRequest, nil)
+		s.call(http.MethodPost, "/api/v1/admin/sentences", adminToken, gin.H{"scene_id": scene.ID, "content": "A table for two, please.", "translation": "请给我两个人的桌子。"}, http.StatusOK, &sentence)
+		sentencePath := fmt.Sprintf("/api/v1/admin/sentences/%d", sentence.ID)
+		s.call(http.MethodPut, sentencePath, adminToken, gin.H{"scene_id": scene.ID, "content": "A table for three, please.", "difficulty": "medium"}, http.StatusOK, &sentence)
+		s.call(http.MethodPatch, sentencePath, adminToken, gin.H{"translation": "请给我三个人的桌子。"}, http.StatusOK, &sentence)
+		if sentence.Content != "A table for three, please." {
+			t.Errorf("patched sentence = %+v", sentence)
+		}
+		s.call(http.MethodPatch, sentencePath, adminToken, gin.H{"difficulty": "impossible"}, http.StatusBadRequest, nil)
+
+		// 默认严格程度下数字与数词、大小写等价；严格场景要求逐字一致
+		var check struct {
+			Accuracy float64 `json:"accuracy"`
+		}
+		checkPath := fmt.Sprintf("/api/v1/sentences/%d/check", sentence.ID)
+		s.call(http.MethodPost, checkPath, "", gin.H{"answer": "a table for 3 please"}, http.StatusOK, &check)
+		if check.Accuracy != 100 {
+			t.Errorf("normal scene accuracy = %v, want 100", check.Accuracy)
+		}
+		s.call(http.MethodPatch, scenePath, adminToken, gin.H{"strictness": "loose"}, http.StatusBadRequest, nil)
+		var strictScene struct {
+			Strictness string `json:"strictness"`
+		}
+		s.call(http.MethodPatch, scenePath, adminToken, gin.H{"strictness": "Strict"}, http.StatusOK, &strictScene)
+		if strictScene.Strictness != "strict" {
+			t.Errorf("scene strictness = %q, want strict", strictScene.Strictness)
+		}
+		s.call(http.MethodPost, checkPath, "", gin.H{"answer": "a table for 3 please"}, http.StatusOK, &check)
+		if check.Accuracy >= 100 {
+			t.Errorf("strict scene accuracy = %v, want below 100", check.Accuracy)
+		}
+		s.call(http.MethodPost, checkPath, "", gin.H{"answer": "A table for three please"}, http.StatusOK, &check)
+		if check.Accuracy != 100 {
+			t.Errorf("strict scene exact answer accuracy = %v, want 100", check.Accuracy)
+		}
+
+		var results []json.RawMessage
+		s.call(http.MethodGet, "/api/v1/search?q=three", "", nil, http.StatusOK, &results)
+		if len(results) != 1 {
+			t.Errorf("search for updated sentence = %d results, want 1", len(results))
+		}
+
+		s.call(http.MethodPut, sentencePath+"/translations/ja", adminToken, gin.H{"text": "二人用のテーブル"}, http.StatusOK, nil)
+		var sentenceTranslations []json.RawMessage
+		s.call(http.MethodGet, sentencePath+"/translations", adminToken, nil, http.StatusOK, &sentenceTranslations)
+		if len(sentenceTranslations) != 1 {
+			t.Errorf("sentence translations = %d, want 1", len(sentenceTranslations))
+		}
+
+		// 翻译写入后立即可以搜索到，删除后不再命中
+		var translated []struct {
+			Highlights map[string]string `json:"highlights"`
+		}
+		s.call(http.MethodGet, "/api/v1/search?q="+url.QueryEscape("テーブル"), "", nil, http.StatusOK, &translated)
+		if len(translated) != 1 || translated[0].Highlights["translation.ja"] != "二人用の<em>テーブル</em>" {
+			t.Errorf("search for sentence translation = %+v", translated)
+		}
+		s.call(http.MethodDelete, sentencePath+"/translations/ja", adminToken, nil, http.StatusOK, nil)
+		s.call(http.MethodDelete, sentencePath+"/translations/ja", adminToken, nil, http.StatusNotFound, nil)
+		s.call(http.MethodGet, "/api/v1/search?q="+url.QueryEscape("テーブル"), "", nil, http.StatusOK, &translated)
+		if len(translated) != 0 {
+			t.Errorf("search after deleting the translation = %d results, want 0", len(translated))
+		}
+
+		s.call(http.MethodDelete, sentencePath, adminToken, nil, http.StatusOK, nil)
+		s.call(http.MethodGet, fmt.Sprintf("/api/v1/sentences/%d", sentence.ID), "", nil, http.StatusNotFound, nil)
+		s.call(http.MethodDelete, scenePath, adminToken, nil, http.StatusOK, nil)
+		s.call(http.MethodDelete, scenePath, adminToken, nil, http.StatusNotFound, nil)
+	})
+
+	t.Run("ImportExport", func(t *testing.T) {
+		s.t = t
+		adminToken, _ := s.login("admin", "adminpass1")
+		csv := "scene,content,translation,difficulty\n机场,Where is gate 12?,12号登机口在哪里？,easy\n机场,My flight is delayed.,我的航班延误了。,medium\n"
+
+		var report struct {
+			Created int `json:"created"`
+		}
+		s.call(http.MethodPost, "/api/v1/admin/import?format=csv&dry_run=true", adminToken, []byte(csv), http.StatusOK, nil)
+		// 不合法的行使整批不写入，错误响应的 data 中为逐行的导入报告
+		var rejected struct {
+			Total  int `json:"total"`
+			Errors []struct {
+				Line    int    `json:"line"`
+				Message string `json:"message"`
+			} `json:"errors"`
+		}
+		resp := s.call(http.MethodPost, "/api/v1/admin/import?format=csv", adminToken, []byte("scene,content\n机场,\n"), http.StatusBadRequest, &rejected)
+		if resp.Code != response.CodeValidation || rejected.Total != 1 || len(rejected.Errors) != 1 || rejected.Errors[0].Line != 2 {
+			t.Errorf("invalid import = %d %+v", resp.Code, rejected)
+		}
+		s.call(http.MethodPost, "/api/v1/admin/import?format=xml", adminToken, []byte(csv), http.StatusBadRequest, nil)
+
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		part, err := form.CreateFormFile("file", "airport.csv")
+		if err != nil {
+			t.Fatal(err)
+		}
+		part.Write([]byte(csv))
+		form.Close()
+		w := s.request(http.MethodPost, "/api/v1/admin/import", adminToken, body.Bytes(), "Content-Type", form.FormDataContentType())
+		if w.Code != http.StatusOK {
+			t.Fatalf("multipart import = %d %s", w.Code, w.Body.String())
+		}
+		json.Unmarshal(w.Body.Bytes(), resp)
+		json.Unmarshal(resp.Data, &report)
+		if report.Created != 2 {
+			t.Errorf("import report = %s", resp.Data)
+		}
+
+		w = s.request(http.MethodGet, "/api/v1/admin/export?format=jsonl", adminToken, nil)
+		if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "Where is gate 12?") {
+			t.Errorf("export = %d %s", w.Code, w.Body.String())
+		}
+		if !strings.Contains(w.Header().Get("Content-Disposition"), ".jsonl") {
+			t.Errorf("export disposition = %q", w.Header().Get("Content-Disposition"))
+		}
+		s.call(http.MethodGet, "/api/v1/admin/export?format=xml", adminToken, nil, http.StatusBadRequest, nil)
+	})
+
+	t.Run("Metrics", func(t *testing.T) {
+		s.t = t
+		w := s.request(http.MethodGet, "/metrics", "", nil)
+		if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "text/plain") {
+			t.Fatalf("metrics = %d %s", w.Code, w.Header().Get("Content-Type"))
+		}
+		body := w.Body.String()
+		for _, want := range []string{
+			`voicewriter_http_requests_total{method="GET",route="/api/v1/audio/:id",status="200"} 1`,
+			`voicewriter_http_request_duration_seconds_count{method="GET",route="/api/v1/scenes"}`,
+			`voicewriter_db_open_connections `,
+			`voicewriter_attempts_total{graded="false",completed="false"} 1`,
+			`voicewriter_tts_cache_lookups_total{result="miss"} 1`,
+			`voicewriter_tts_cache_lookups_total{result="hit"} 2`,
+			`voicewriter_tts_cache_hit_ratio 0.6666666666666666`,
+		} {
+			if !strings.Contains(body, want) {
+				t.Errorf("metrics missing %q", want)
+			}
+		}
+		if strings.Contains(body, `route="/api/v1/scenes/1"`) {
+			t.Error("metrics should use route templates, not raw paths")
+		}
+	})
+
+	s.t = t
+	s.assertAllRoutesVisited()
+}
+
+func TestErrorResponses(t *testing.T) {
+	s := newTestServer(t)
+	adminToken, _ := s.login("admin", "adminpass1")
+	var admin struct {
+		UID string `json:"uid"`
+	}
+	s.call(http.MethodGet, "/api/v1/auth/me", adminToken, nil, http.StatusOK, &admin)
+
+	cases := []struct {
+		name       string
+		method     string
+		path       string
+		token      string
+		body       interface{}
+		wantStatus int
+		wantCode   int
+	}{
+		{"invalid path parameter", http.MethodGet, "/api/v1/scenes/abc", "", nil, http.StatusBadRequest, response.CodeValidation},
+		{"invalid sort", http.MethodGet, "/api/v1/sentences?sort=content", "", nil, http.StatusBadRequest, response.CodeValidation},
+		{"invalid locale", http.MethodPut, "/api/v1/admin/sentences/1/translations/!!", adminToken, gin.H{"text": "x"}, http.StatusBadRequest, response.CodeValidation},
+		{"missing scene", http.MethodGet, "/api/v1/scenes/999", "", nil, http.StatusNotFound, response.CodeNotFound},
+		{"missing audio", http.MethodGet, "/api/v1/audio/999", "", nil, http.StatusNotFound, response.CodeNotFound},
+		{"duplicate scene", http.MethodPost, "/api/v1/admin/scenes", adminToken, gin.H{"name": "日常生活"}, http.StatusConflict, response.CodeConflict},
+		{"scene in use", http.MethodDelete, "/api/v1/admin/scenes/1", adminToken, nil, http.StatusConflict, response.CodeConflict},
+		{"bad credentials", http.MethodPost, "/api/v1/auth/login", "", gin.H{"username": "admin", "password": "wrongpass1"}, http.StatusUnauthorized, response.CodeUnauthorized},
+		{"missing token", http.MethodGet, "/api/v1/progress", "", nil, http.StatusUnauthorized, response.CodeUnauthorized},
+		{"claim registered user id", http.MethodPost, "/api/v1/auth/claim", adminToken, gin.H{"anonymous_id": admin.UID, "claim_secret": "x"}, http.StatusForbidden, response.CodeForbidden},
+		{"claim without secret", http.MethodPost, "/api/v1/auth/claim", adminToken, gin.H{"anonymous_id": admin.UID}, http.StatusBadRequest, response.CodeValidation},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s.t = t
+			resp := s.call(tc.method, tc.path, tc.token, tc.body, tc.wantStatus, nil)
+			if resp.Code != tc.wantCode {
+				t.Errorf("code = %d, want %d (message %q)", resp.Code, tc.wantCode, resp.Message)
+			}
+		})
+	}
+
+	// 数据库不可用时返回 503，而不是 404 或 500
+	t.Run("database unavailable", func(t *testing.T) {
+		s.t = t
+		sqlDB, err := s.db.DB()
+		if err != nil {
+			t.Fatal(err)
+		}
+		sqlDB.Close()
+
+		for _, path := range []string{"/api/v1/scenes/1", "/api/v1/sentences", "/api/v1/audio/1"} {
+			resp := s.call(http.MethodGet, path, "", nil, http.StatusServiceUnavailable, nil)
+			if resp.Code != response.CodeUnavailable || strings.Contains(resp.Message, "sql") {
+				t.Errorf("%s: code = %d, message = %q", path, resp.Code, resp.Message)
+			}
+		}
+
+		// 就绪探针返回 503 和各组件的状态，错误详情只写入日志；存活探针不受影响
+		var ready struct {
+			Status     string            `json:"status"`
+			Components map[string]string `json:"components"`
+		}
+		resp := s.call(http.MethodGet, "/readyz", "", nil, http.StatusServiceUnavailable, &ready)
+		if resp.Code != response.CodeUnavailable || strings.Contains(resp.Message, "sql") || ready.Status != "down" || ready.Components["database"] != "down" {
+			t.Errorf("readyz = %d %q %+v", resp.Code, resp.Message, ready)
+		}
+		if ready.Components["audio_cache"] != "up" {
+			t.Errorf("audio cache should stay up: %q", ready.Components["audio_cache"])
+		}
+		s.call(http.MethodGet, "/livez", "", nil, http.StatusOK, nil)
+	})
+}
+
+// TestConcurrentProgressSaves 并发提交同一句子的进度，应只有一条进度记录且作答次数不丢失
+// 内存数据库只有一个连接，请求实际是串行的，这里使用临时文件数据库让多个连接同时写入
+func TestConcurrentProgressSaves(t *testing.T) {
+	const maxConns = 10
+	s := newTestServerWithDB(t, config.DatabaseConfig{
+		Driver:       database.DriverSQLite,
+		Path:         filepath.Join(t.TempDir(), "voicewriter.db"),
+		MaxOpenConns: maxConns,
+		MaxIdleConns: maxConns,
+	})
+	s.call(http.MethodPost, "/api/v1/auth/register", "", gin.H{"username": "alice", "password": "alicepass1"}, http.StatusOK, nil)
+	token, _ := s.login("alice", "alicepass1")
+
+	const workers = 50
+	body := []byte(`{"sentence_id": 1, "completed": false, "duration_ms": 1200}`)
+	// 单核环境下 goroutine 很少真正交错，放开 GOMAXPROCS 并让所有请求同时开始
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(maxConns))
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	statuses := make(chan int, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			// s.request 会写 visited 且失败时调用 Fatalf，并发请求直接走路由
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/progress", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+token)
+			w := httptest.NewRecorder()
+			s.router.ServeHTTP(w, req)
+			statuses <- w.Code
+		}()
+	}
+	close(start)
+	wg.Wait()
+	close(statuses)
+	for status := range statuses {
+		if status != http.StatusOK {
+			t.Fatalf("concurrent save status = %d, want 200", status)
+		}
+	}
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats := sqlDB.Stats(); stats.OpenConnections < 2 || stats.MaxOpenConnections != maxConns {
+		t.Fatalf("connection pool = %+v, want several connections in use", stats)
+	}
+
+	var progress []struct {
+		SentenceID uint `json:"sentence_id"`
+		Attempts   int  `json:"attempts"`
+	}
+	s.call(http.MethodGet, "/api/v1/progress", token, nil, http.StatusOK, &progress)
+	if len(progress) != 1 || progress[0].SentenceID != 1 || progress[0].Attempts != workers {
+		t.Errorf("progress = %+v, want one record for sentence 1 with %d attempts", progress, workers)
+	}
+	var attempts int64
+	if err := s.db.Table("attempts").Where("sentence_id = ?", 1).Count(&attempts).Error; err != nil {
+		t.Fatalf("count attempts: %v", err)
+	}
+	if attempts != workers {
+		t.Errorf("attempt records = %d, want %d", attempts, workers)
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	s := newTestServer(t)
+
+	w := s.request(http.MethodGet, "/health", "", nil, middleware.RequestIDHeader, "req-123")
+	if got := w.Header().Get(middleware.RequestIDHeader); got != "req-123" {
+		t.Errorf("echoed request id = %q, want %q", got, "req-123")
+	}
+
+	w = s.request(http.MethodGet, "/health", "", nil)
+	if w.Header().Get(middleware.RequestIDHeader) == "" {
+		t.Error("request id not generated")
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"voicewriter/internal/database"
+)
+
+// runMigrate 执行数据库迁移：voicewriter migrate up [-steps n] | down [-steps n] | status
+func runMigrate(args []string) {
+	usage := func() {
+		fmt.Fprintln(os.Stderr, "Usage: voicewriter migrate <up|down|status> [-steps n]")
+		os.Exit(2)
+	}
+	if len(args) == 0 {
+		usage()
+	}
+	action := args[0]
+
+	fs := flag.NewFlagSet("migrate "+action, flag.ExitOnError)
+	steps := fs.Int("steps", 0, "number of migrations to apply or revert (up: 0 = all, down: default 1)")
+	fs.Parse(args[1:])
+
+	migrator, err := database.NewMigrator(connectDatabase(loadConfig()))
+	if err != nil {
+		log.Fatalf("Failed to load migrations: %v", err)
+	}
+	ctx := context.Background()
+
+	switch action {
+	case "up":
+		applied, err := migrator.Up(ctx, *steps)
+		if err != nil {
+			log.Fatalf("Migrate up failed: %v", err)
+		}
+		if len(applied) == 0 {
+			fmt.Println("Database schema is up to date")
+		}
+	case "down":
+		if *steps <= 0 {
+			*steps = 1
+		}
+		reverted, err := migrator.Down(ctx, *steps)
+		if err != nil {
+			log.Fatalf("Migrate down failed: %v", err)
+		}
+		if len(reverted) == 0 {
+			fmt.Println("No migrations to revert")
+		}
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			log.Fatalf("Failed to get migration status: %v", err)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+		}
+		w.Flush()
+	default:
+		usage()
+	}
+}
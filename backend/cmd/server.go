@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"voicewriter/internal/config"
+	"voicewriter/internal/worker"
+
+	"gorm.io/gorm"
+)
+
+// 服务器超时的默认值
+const (
+	defaultReadTimeout       = 30 * time.Second
+	defaultReadHeaderTimeout = 10 * time.Second
+	defaultWriteTimeout      = 60 * time.Second
+	defaultIdleTimeout       = 120 * time.Second
+	defaultShutdownTimeout   = 30 * time.Second
+)
+
+// server HTTP 服务及退出时需要收尾的资源
+type server struct {
+	httpServer      *http.Server
+	workers         *worker.Group
+	db              *gorm.DB
+	shutdownTimeout time.Duration
+}
+
+// newServer 按配置创建带超时的 HTTP 服务
+func newServer(cfg *config.ServerConfig, handler http.Handler, workers *worker.Group, db *gorm.DB) *server {
+	return &server{
+		httpServer: &http.Server{
+			Addr:              ":" + cfg.Port,
+			Handler:           handler,
+			ReadTimeout:       seconds(cfg.ReadTimeout, defaultReadTimeout),
+			ReadHeaderTimeout: seconds(cfg.ReadHeaderTimeout, defaultReadHeaderTimeout),
+			WriteTimeout:      seconds(cfg.WriteTimeout, defaultWriteTimeout),
+			IdleTimeout:       seconds(cfg.IdleTimeout, defaultIdleTimeout),
+		},
+		workers:         workers,
+		db:              db,
+		shutdownTimeout: seconds(cfg.ShutdownTimeout, defaultShutdownTimeout),
+	}
+}
+
+// run 在 ln 上处理请求，直到 ctx 取消或服务出错，然后依次排空请求、等待后台任务、关闭数据库连接池
+func (s *server) run(ctx context.Context, ln net.Listener) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- s.httpServer.Serve(ln)
+	}()
+
+	select {
+	case err := <-serveErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			s.close()
+			return fmt.Errorf("server stopped: %w", err)
+		}
+	case <-ctx.Done():
+	}
+	return s.shutdown()
+}
+
+// shutdown 在 shutdownTimeout 内排空进行中的请求和后台任务，超时后强制关闭
+func (s *server) shutdown() error {
+	slog.Info("server shutting down", "timeout", s.shutdownTimeout.String())
+	ctx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+
+	var errs []error
+	if err := s.httpServer.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to drain requests: %w", err))
+		s.httpServer.Close()
+	}
+	if err := s.workers.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to finish background tasks: %w", err))
+	}
+	if err := s.close(); err != nil {
+		errs = append(errs, err)
+	}
+	if len(errs) == 0 {
+		slog.Info("server stopped")
+	}
+	return errors.Join(errs...)
+}
+
+// close 关闭数据库连接池
+func (s *server) close() error {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get database pool: %w", err)
+	}
+	if err := sqlDB.Close(); err != nil {
+		return fmt.Errorf("failed to close database: %w", err)
+	}
+	return nil
+}
+
+// seconds 将以秒为单位的配置转换为时长，未配置时返回默认值
+func seconds(value int, fallback time.Duration) time.Duration {
+	if value <= 0 {
+		return fallback
+	}
+	return time.Duration(value) * time.Second
+}
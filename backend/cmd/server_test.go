@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"voicewriter/internal/config"
+	"voicewriter/internal/database"
+	"voicewriter/internal/worker"
+)
+
+func TestServerGracefulShutdown(t *testing.T) {
+	db, err := database.NewDatabase(&config.DatabaseConfig{Driver: database.DriverSQLite, Path: ":memory:"})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	workers := worker.NewGroup()
+
+	// 请求和后台任务都在收到退出信号后才完成
+	started := make(chan struct{})
+	var taskDone atomic.Bool
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		workers.Go("test task", func(ctx context.Context) error {
+			time.Sleep(100 * time.Millisecond)
+			taskDone.Store(true)
+			return nil
+		})
+		close(started)
+		time.Sleep(100 * time.Millisecond)
+		io.WriteString(w, "done")
+	})
+
+	srv := newServer(&config.ServerConfig{ShutdownTimeout: 5}, handler, workers, db)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- srv.run(ctx, ln)
+	}()
+
+	type result struct {
+		body string
+		err  error
+	}
+	resp := make(chan result, 1)
+	go func() {
+		r, err := http.Get("http://" + ln.Addr().String())
+		if err != nil {
+			resp <- result{err: err}
+			return
+		}
+		defer r.Body.Close()
+		body, err := io.ReadAll(r.Body)
+		resp <- result{body: string(body), err: err}
+	}()
+
+	<-started
+	cancel()
+
+	if r := <-resp; r.err != nil || r.body != "done" {
+		t.Errorf("in-flight request = %q, %v; want it to complete", r.body, r.err)
+	}
+	if err := <-runErr; err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if !taskDone.Load() {
+		t.Error("background task did not finish before shutdown returned")
+	}
+	if err := workers.Go("late task", func(context.Context) error { return nil }); err != worker.ErrClosed {
+		t.Errorf("Go after shutdown = %v, want ErrClosed", err)
+	}
+	sqlDB, _ := db.DB()
+	if err := sqlDB.Ping(); err == nil {
+		t.Error("database pool still open after shutdown")
+	}
+}
@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"voicewriter/internal/config"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// TokenTypeAccess 访问令牌
+	TokenTypeAccess = "access"
+	// TokenTypeRefresh 刷新令牌
+	TokenTypeRefresh = "refresh"
+)
+
+// ErrInvalidToken 令牌无效、过期或类型不符
+var ErrInvalidToken = errors.New("invalid token")
+
+// Identity 已认证用户的身份信息，由认证中间件注入请求上下文
+type Identity struct {
+	UserID   uint   `json:"user_id"`
+	UID      string `json:"uid"` // 进度、作答等数据中使用的用户标识
+	Username string `json:"username"`
+	Role     string `json:"role"`
+}
+
+// Claims JWT 载荷
+type Claims struct {
+	UserID   uint   `json:"user_id"`
+	Username string `json:"username"`
+	Role     string `json:"role"`
+	Type     string `json:"typ"`
+	jwt.RegisteredClaims
+}
+
+// Identity 从载荷中提取身份信息
+func (c *Claims) Identity() *Identity {
+	return &Identity{
+		UserID:   c.UserID,
+		UID:      c.Subject,
+		Username: c.Username,
+		Role:     c.Role,
+	}
+}
+
+// TokenPair 一组访问令牌和刷新令牌
+type TokenPair struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	TokenType    string    `json:"token_type"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+// TokenManager JWT 签发与校验
+type TokenManager struct {
+	secret     []byte
+	issuer     string
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+// NewTokenManager 根据配置创建令牌管理器
+func NewTokenManager(cfg *config.AuthConfig) (*TokenManager, error) {
+	if cfg.JWTSecret == "" {
+		return nil, errors.New("jwt secret is required")
+	}
+	if cfg.AccessTokenTTL <= 0 || cfg.RefreshTokenTTL <= 0 {
+		return nil, errors.New("token ttl must be positive")
+	}
+	return &TokenManager{
+		secret:     []byte(cfg.JWTSecret),
+		issuer:     cfg.Issuer,
+		accessTTL:  time.Duration(cfg.AccessTokenTTL) * time.Second,
+		refreshTTL: time.Duration(cfg.RefreshTokenTTL) * time.Second,
+	}, nil
+}
+
+// Issue 为用户签发一组新令牌
+func (m *TokenManager) Issue(identity *Identity) (*TokenPair, error) {
+	now := time.Now()
+	accessExpiresAt := now.Add(m.accessTTL)
+
+	access, err := m.sign(identity, TokenTypeAccess, now, accessExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	refresh, err := m.sign(identity, TokenTypeRefresh, now, now.Add(m.refreshTTL))
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		TokenType:    "Bearer",
+		ExpiresAt:    accessExpiresAt,
+	}, nil
+}
+
+// Parse 校验令牌签名、有效期和类型，返回载荷
+func (m *TokenManager) Parse(tokenString, tokenType string) (*Claims, error) {
+	var claims Claims
+	_, err := jwt.ParseWithClaims(tokenString, &claims, func(token *jwt.Token) (interface{}, error) {
+		return m.secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(m.issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	if claims.Type != tokenType || claims.Subject == "" {
+		return nil, ErrInvalidToken
+	}
+	return &claims, nil
+}
+
+func (m *TokenManager) sign(identity *Identity, tokenType string, issuedAt, expiresAt time.Time) (string, error) {
+	claims := &Claims{
+		UserID:   identity.UserID,
+		Username: identity.Username,
+		Role:     identity.Role,
+		Type:     tokenType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    m.issuer,
+			Subject:   identity.UID,
+			IssuedAt:  jwt.NewNumericDate(issuedAt),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signed, err := token.SignedString(m.secret)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+	return signed, nil
+}
@@ -12,32 +12,51 @@ type Config struct {
 	Database DatabaseConfig `mapstructure:"database"`
 	Log      LogConfig      `mapstructure:"log"`
 	Cors     CorsConfig     `mapstructure:"cors"`
+	TTS      TTSConfig      `mapstructure:"tts"`
+	Auth     AuthConfig     `mapstructure:"auth"`
 }
 
 // ServerConfig 服务器配置
 type ServerConfig struct {
 	Port string `mapstructure:"port"`
 	Mode string `mapstructure:"mode"`
+
+	// 超时均以秒为单位，0 表示使用默认值
+	ReadTimeout       int `mapstructure:"read_timeout"`        // 读取整个请求的超时，默认 30
+	ReadHeaderTimeout int `mapstructure:"read_header_timeout"` // 读取请求头的超时，默认 10
+	WriteTimeout      int `mapstructure:"write_timeout"`       // 写响应的超时，默认 60
+	IdleTimeout       int `mapstructure:"idle_timeout"`        // keep-alive 连接的空闲超时，默认 120
+	ShutdownTimeout   int `mapstructure:"shutdown_timeout"`    // 退出时等待请求和后台任务完成的时间，默认 30
 }
 
 // DatabaseConfig 数据库配置
 type DatabaseConfig struct {
+	Driver          string `mapstructure:"driver"` // sqlite（默认）, mysql, postgres
+	Path            string `mapstructure:"path"`   // SQLite 数据库文件，:memory: 表示内存数据库
 	Host            string `mapstructure:"host"`
 	Port            int    `mapstructure:"port"`
 	Username        string `mapstructure:"username"`
 	Password        string `mapstructure:"password"`
 	DBName          string `mapstructure:"dbname"`
-	Charset         string `mapstructure:"charset"`
+	Charset         string `mapstructure:"charset"`  // 仅 MySQL
+	SSLMode         string `mapstructure:"ssl_mode"` // 仅 PostgreSQL，默认 disable
 	MaxIdleConns    int    `mapstructure:"max_idle_conns"`
 	MaxOpenConns    int    `mapstructure:"max_open_conns"`
 	ConnMaxLifetime int    `mapstructure:"conn_max_lifetime"`
+	SlowThreshold   int    `mapstructure:"slow_threshold"` // 慢查询阈值（毫秒），超过时记录警告日志，0 表示不记录
 }
 
 // LogConfig 日志配置
 type LogConfig struct {
-	Level  string `mapstructure:"level"`
-	Format string `mapstructure:"format"`
-	Output string `mapstructure:"output"`
+	Level  string `mapstructure:"level"`  // debug, info（默认）, warn, error
+	Format string `mapstructure:"format"` // json（默认）, text
+	Output string `mapstructure:"output"` // stdout（默认）, stderr, file
+
+	// 以下仅 output 为 file 时使用
+	File       string `mapstructure:"file"`        // 日志文件，默认 logs/voicewriter.log
+	MaxSize    int    `mapstructure:"max_size"`    // 单个文件上限（MB），超过后轮转，默认 100
+	MaxBackups int    `mapstructure:"max_backups"` // 保留的历史文件数，0 表示不限
+	MaxAge     int    `mapstructure:"max_age"`     // 历史文件保留天数，0 表示不限
 }
 
 // CorsConfig CORS配置
@@ -47,10 +66,38 @@ type CorsConfig struct {
 	AllowedHeaders []string `mapstructure:"allowed_headers"`
 }
 
+// TTSConfig 语音合成配置
+type TTSConfig struct {
+	Provider string  `mapstructure:"provider"` // tone, silence
+	CacheDir string  `mapstructure:"cache_dir"`
+	Language string  `mapstructure:"language"` // 句子未设置语言时使用
+	Voice    string  `mapstructure:"voice"`
+	Speed    float64 `mapstructure:"speed"`
+}
+
+// AuthConfig 认证配置
+type AuthConfig struct {
+	JWTSecret       string `mapstructure:"jwt_secret"`
+	Issuer          string `mapstructure:"issuer"`
+	AccessTokenTTL  int    `mapstructure:"access_token_ttl"`  // 秒
+	RefreshTokenTTL int    `mapstructure:"refresh_token_ttl"` // 秒
+
+	// 初始管理员账户，用户名为空时不创建
+	AdminUsername string `mapstructure:"admin_username"`
+	AdminPassword string `mapstructure:"admin_password"`
+}
+
+// DefaultCorsMethods 未配置 cors.allowed_methods 时允许的方法，需覆盖路由用到的全部方法
+var DefaultCorsMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+
+// DefaultJWTSecret 示例配置中的 JWT 密钥，release 模式下不允许使用
+const DefaultJWTSecret = "change-me-in-production"
+
 // LoadConfig 从YAML文件加载配置
 func LoadConfig(configPath string) (*Config, error) {
 	viper.SetConfigFile(configPath)
 	viper.AutomaticEnv()
+	viper.SetDefault("cors.allowed_methods", DefaultCorsMethods)
 
 	if err := viper.ReadInConfig(); err != nil {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
@@ -60,6 +107,17 @@ func LoadConfig(configPath string) (*Config, error) {
 	if err := viper.Unmarshal(&config); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
 
 	return &config, nil
 }
+
+// Validate 检查不能带到生产环境的配置
+func (c *Config) Validate() error {
+	if c.Server.Mode == "release" && (c.Auth.JWTSecret == "" || c.Auth.JWTSecret == DefaultJWTSecret) {
+		return fmt.Errorf("auth.jwt_secret must be set to a non-default value in release mode")
+	}
+	return nil
+}
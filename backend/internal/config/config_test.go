@@ -0,0 +1,42 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestValidateJWTSecret(t *testing.T) {
+	cases := []struct {
+		mode    string
+		secret  string
+		wantErr bool
+	}{
+		{"release", DefaultJWTSecret, true},
+		{"release", "", true},
+		{"release", "a-real-secret", false},
+		{"debug", DefaultJWTSecret, false},
+		{"test", "", false},
+	}
+	for _, tc := range cases {
+		cfg := &Config{Server: ServerConfig{Mode: tc.mode}, Auth: AuthConfig{JWTSecret: tc.secret}}
+		if err := cfg.Validate(); (err != nil) != tc.wantErr {
+			t.Errorf("Validate(mode=%q, secret=%q) = %v, want error %v", tc.mode, tc.secret, err, tc.wantErr)
+		}
+	}
+}
+
+func TestLoadConfigDefaultCorsMethods(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("server:\n  mode: debug\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if !slices.Contains(cfg.Cors.AllowedMethods, "PATCH") {
+		t.Errorf("default allowed methods = %v, want PATCH included", cfg.Cors.AllowedMethods)
+	}
+}
@@ -0,0 +1,176 @@
+package content
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// ankiColumns 导出时写入 #columns 头的列名，也是无列头时的默认列顺序
+var ankiColumns = []string{"Content", "Translation", "Scene", "Difficulty", "Audio", "Language"}
+
+var (
+	htmlTagPattern   = regexp.MustCompile(`<[^>]*>`)
+	soundTagPattern  = regexp.MustCompile(`\[sound:[^\]]*\]`)
+	ankiFieldAliases = map[string]string{
+		"content":     "content",
+		"front":       "content",
+		"text":        "content",
+		"sentence":    "content",
+		"translation": "translation",
+		"back":        "translation",
+		"meaning":     "translation",
+		"scene":       "scene",
+		"deck":        "scene",
+		"difficulty":  "difficulty",
+		"audio":       "audio",
+		"audio_url":   "audio",
+		"language":    "language",
+		"lang":        "language",
+	}
+)
+
+// decodeAnkiText 解析 Anki “纯文本笔记”导出，支持 #separator、#html、#columns 文件头
+func decodeAnkiText(data []byte) ([]Record, error) {
+	separator := '\t'
+	htmlEnabled := true
+	columns := []string{"content", "translation", "scene", "difficulty", "audio"}
+
+	var body bytes.Buffer
+	bodyStart := 0
+	scanner := bufio.NewScanner(bytes.NewReader(bytes.TrimPrefix(data, utf8BOM)))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "#") {
+			body.WriteString(line)
+			body.WriteByte('\n')
+			continue
+		}
+		if body.Len() == 0 {
+			bodyStart++
+		}
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "#"), ":")
+		if !ok {
+			continue
+		}
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "separator":
+			sep, err := parseAnkiSeparator(value)
+			if err != nil {
+				return nil, err
+			}
+			separator = sep
+		case "html":
+			htmlEnabled = strings.EqualFold(strings.TrimSpace(value), "true")
+		case "columns":
+			columns = nil
+			for _, name := range strings.Split(value, string(separator)) {
+				columns = append(columns, ankiFieldAliases[strings.ToLower(strings.TrimSpace(name))])
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read anki text: %w", err)
+	}
+
+	reader := csv.NewReader(&body)
+	reader.Comma = separator
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	var records []Record
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read anki text: %w", err)
+		}
+		if isBlankRow(row) {
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+		r := Record{Line: bodyStart + line}
+		for i, value := range row {
+			if i >= len(columns) {
+				break
+			}
+			if htmlEnabled {
+				value = stripAnkiHTML(value)
+			}
+			setAnkiField(&r, columns[i], strings.TrimSpace(value))
+		}
+		records = append(records, r)
+	}
+	return records, nil
+}
+
+func encodeAnkiText(w io.Writer, records []Record) error {
+	header := "#separator:tab\n#html:false\n#columns:" + strings.Join(ankiColumns, "\t") + "\n"
+	if _, err := io.WriteString(w, header); err != nil {
+		return err
+	}
+	writer := csv.NewWriter(w)
+	writer.Comma = '\t'
+	for _, r := range records {
+		if err := writer.Write(ankiFields(r)); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func parseAnkiSeparator(value string) (rune, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "tab", "\t":
+		return '\t', nil
+	case "comma", ",":
+		return ',', nil
+	case "semicolon", ";":
+		return ';', nil
+	case "pipe", "|":
+		return '|', nil
+	case "colon", ":":
+		return ':', nil
+	default:
+		return 0, fmt.Errorf("unsupported anki separator: %s", value)
+	}
+}
+
+// ankiFields 按 ankiColumns 的顺序排列字段
+func ankiFields(r Record) []string {
+	return []string{r.Content, r.Translation, r.Scene, r.Difficulty, r.AudioURL, r.Language}
+}
+
+func setAnkiField(r *Record, field, value string) {
+	switch field {
+	case "content":
+		r.Content = value
+	case "translation":
+		r.Translation = value
+	case "scene":
+		r.Scene = value
+	case "difficulty":
+		r.Difficulty = value
+	case "audio":
+		r.AudioURL = value
+	case "language":
+		r.Language = value
+	}
+}
+
+// stripAnkiHTML 去掉 Anki 字段中的 HTML 标签和 [sound:...] 引用
+func stripAnkiHTML(value string) string {
+	value = soundTagPattern.ReplaceAllString(value, "")
+	value = strings.NewReplacer("<br>", " ", "<br/>", " ", "<br />", " ").Replace(value)
+	value = htmlTagPattern.ReplaceAllString(value, "")
+	return html.UnescapeString(value)
+}
@@ -0,0 +1,368 @@
+package content
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha1"
+	"database/sql"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	// Anki 卡组包内是 SQLite 数据库
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const (
+	// ankiFieldSeparator Anki 笔记字段分隔符
+	ankiFieldSeparator = "\x1f"
+	// ankiDeckPrefix 导出时场景对应的牌组名前缀
+	ankiDeckPrefix = "VoiceWriter::"
+	// ankiModelID 导出使用的笔记类型ID，固定值保证多次导出可合并
+	ankiModelID = int64(1700000000000)
+	// maxAnkiCollectionSize 解压后集合数据库的大小上限；请求大小限制只约束压缩后的卡组包
+	maxAnkiCollectionSize = 256 << 20
+)
+
+// errAnkiCollectionTooLarge 卡组包中的集合数据库解压后超过大小上限
+var errAnkiCollectionTooLarge = fmt.Errorf("anki collection exceeds %d MiB when decompressed", maxAnkiCollectionSize>>20)
+
+// ankiCollectionFiles 按优先级排列的集合文件名；collection.anki21b 为 zstd 压缩格式，暂不支持
+var ankiCollectionFiles = []string{"collection.anki21", "collection.anki2"}
+
+// ankiSchema Anki 2.1 (schema 11) 集合的最小表结构
+const ankiSchema = `
+CREATE TABLE col (id integer primary key, crt integer not null, mod integer not null, scm integer not null, ver integer not null, dty integer not null, usn integer not null, ls integer not null, conf text not null, models text not null, decks text not null, dconf text not null, tags text not null);
+CREATE TABLE notes (id integer primary key, guid text not null, mid integer not null, mod integer not null, usn integer not null, tags text not null, flds text not null, sfld integer not null, csum integer not null, flags integer not null, data text not null);
+CREATE TABLE cards (id integer primary key, nid integer not null, did integer not null, ord integer not null, mod integer not null, usn integer not null, type integer not null, queue integer not null, due integer not null, ivl integer not null, factor integer not null, reps integer not null, lapses integer not null, left integer not null, odue integer not null, odid integer not null, flags integer not null, data text not null);
+CREATE TABLE revlog (id integer primary key, cid integer not null, usn integer not null, ease integer not null, ivl integer not null, lastIvl integer not null, factor integer not null, time integer not null, type integer not null);
+CREATE TABLE graves (usn integer not null, oid integer not null, type integer not null);
+CREATE INDEX ix_notes_usn on notes (usn);
+CREATE INDEX ix_cards_usn on cards (usn);
+CREATE INDEX ix_cards_nid on cards (nid);
+CREATE INDEX ix_cards_sched on cards (did, queue, due);
+CREATE INDEX ix_notes_csum on notes (csum);
+`
+
+type ankiModel struct {
+	Name string `json:"name"`
+	Flds []struct {
+		Name string `json:"name"`
+		Ord  int    `json:"ord"`
+	} `json:"flds"`
+}
+
+type ankiDeck struct {
+	Name string `json:"name"`
+}
+
+// decodeAPKG 解析 Anki 卡组包：字段按笔记类型中的字段名匹配，场景取自 Scene 字段或所属牌组
+func decodeAPKG(data []byte) ([]Record, error) {
+	path, cleanup, err := extractAnkiCollection(data)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	db, err := sql.Open("sqlite3", "file:"+path+"?mode=ro")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open anki collection: %w", err)
+	}
+	defer db.Close()
+
+	var modelsJSON, decksJSON string
+	if err := db.QueryRow("SELECT models, decks FROM col LIMIT 1").Scan(&modelsJSON, &decksJSON); err != nil {
+		return nil, fmt.Errorf("failed to read anki collection: %w", err)
+	}
+	var models map[string]ankiModel
+	var decks map[string]ankiDeck
+	if err := json.Unmarshal([]byte(modelsJSON), &models); err != nil {
+		return nil, fmt.Errorf("invalid anki models: %w", err)
+	}
+	if err := json.Unmarshal([]byte(decksJSON), &decks); err != nil {
+		return nil, fmt.Errorf("invalid anki decks: %w", err)
+	}
+
+	rows, err := db.Query(`
+		SELECT n.mid, n.flds, COALESCE((SELECT c.did FROM cards c WHERE c.nid = n.id ORDER BY c.ord LIMIT 1), 0)
+		FROM notes n ORDER BY n.id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read anki notes: %w", err)
+	}
+	defer rows.Close()
+
+	var records []Record
+	for line := 1; rows.Next(); line++ {
+		var mid, did int64
+		var flds string
+		if err := rows.Scan(&mid, &flds, &did); err != nil {
+			return nil, fmt.Errorf("failed to read anki notes: %w", err)
+		}
+
+		r := Record{Line: line}
+		values := strings.Split(flds, ankiFieldSeparator)
+		fields := ankiModelFields(models[strconv.FormatInt(mid, 10)], len(values))
+		for i, value := range values {
+			setAnkiField(&r, fields[i], strings.TrimSpace(stripAnkiHTML(value)))
+		}
+		if r.Scene == "" {
+			r.Scene = ankiDeckScene(decks[strconv.FormatInt(did, 10)].Name)
+		}
+		records = append(records, r)
+	}
+	return records, rows.Err()
+}
+
+// encodeAPKG 导出为 Anki 卡组包，每个场景一个子牌组
+func encodeAPKG(w io.Writer, records []Record) error {
+	dir, err := os.MkdirTemp("", "voicewriter-apkg-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "collection.anki2")
+	if err := writeAnkiCollection(path, records); err != nil {
+		return err
+	}
+	collection, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	archive := zip.NewWriter(w)
+	for name, body := range map[string][]byte{
+		"collection.anki2": collection,
+		"media":            []byte("{}"),
+	} {
+		f, err := archive.Create(name)
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(body); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+func writeAnkiCollection(path string, records []Record) error {
+	db, err := sql.Open("sqlite3", "file:"+path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(ankiSchema); err != nil {
+		return fmt.Errorf("failed to create anki schema: %w", err)
+	}
+
+	now := time.Now()
+	nowMs := now.UnixMilli()
+	deckIDs := ankiDeckIDs(records)
+	models, decks, err := ankiCollectionJSON(nowMs, deckIDs)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`INSERT INTO col VALUES (1, ?, ?, ?, 11, 0, 0, 0, '{}', ?, ?, ?, '{}')`,
+		now.Unix(), nowMs, nowMs, models, decks, `{"1":{"id":1,"name":"Default","new":{"perDay":20},"rev":{"perDay":200}}}`)
+	if err != nil {
+		return fmt.Errorf("failed to write anki collection: %w", err)
+	}
+
+	for i, r := range records {
+		id := nowMs + int64(i)
+		fields := ankiFields(r)
+		sum := sha1.Sum([]byte(r.Content))
+		checksum := int64(binary.BigEndian.Uint32(sum[:4]))
+		guid := ankiGUID(r)
+
+		if _, err := tx.Exec(`INSERT INTO notes VALUES (?, ?, ?, ?, -1, '', ?, ?, ?, 0, '')`,
+			id, guid, ankiModelID, now.Unix(), strings.Join(fields, ankiFieldSeparator), r.Content, checksum); err != nil {
+			return fmt.Errorf("failed to write anki note: %w", err)
+		}
+		if _, err := tx.Exec(`INSERT INTO cards VALUES (?, ?, ?, 0, ?, -1, 0, 0, ?, 0, 0, 0, 0, 0, 0, 0, 0, '')`,
+			id, id, deckIDs[r.Scene], now.Unix(), i+1); err != nil {
+			return fmt.Errorf("failed to write anki card: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// ankiCollectionJSON 生成 col 表中笔记类型和牌组的 JSON
+func ankiCollectionJSON(nowMs int64, deckIDs map[string]int64) (string, string, error) {
+	flds := make([]map[string]interface{}, len(ankiColumns))
+	for i, name := range ankiColumns {
+		flds[i] = map[string]interface{}{
+			"name": name, "ord": i, "sticky": false, "rtl": false,
+			"font": "Arial", "size": 20, "media": []string{},
+		}
+	}
+	models := map[string]interface{}{
+		strconv.FormatInt(ankiModelID, 10): map[string]interface{}{
+			"id": ankiModelID, "name": "VoiceWriter Dictation", "type": 0,
+			"mod": nowMs / 1000, "usn": -1, "sortf": 0, "did": 1,
+			"flds": flds,
+			"tmpls": []map[string]interface{}{{
+				"name": "Dictation", "ord": 0, "did": nil, "bqfmt": "", "bafmt": "",
+				"qfmt": "{{Audio}}<br>{{Translation}}",
+				"afmt": "{{FrontSide}}<hr id=answer>{{Content}}",
+			}},
+			"css":      ".card { font-family: arial; font-size: 20px; text-align: center; }",
+			"latexPre": "", "latexPost": "", "tags": []string{}, "vers": []int{},
+			"req": []interface{}{[]interface{}{0, "any", []int{0, 1}}},
+		},
+	}
+
+	decks := map[string]interface{}{
+		"1": ankiDeckJSON(1, "Default", nowMs),
+	}
+	for scene, id := range deckIDs {
+		decks[strconv.FormatInt(id, 10)] = ankiDeckJSON(id, ankiDeckPrefix+scene, nowMs)
+	}
+
+	modelsJSON, err := json.Marshal(models)
+	if err != nil {
+		return "", "", err
+	}
+	decksJSON, err := json.Marshal(decks)
+	if err != nil {
+		return "", "", err
+	}
+	return string(modelsJSON), string(decksJSON), nil
+}
+
+func ankiDeckJSON(id int64, name string, nowMs int64) map[string]interface{} {
+	return map[string]interface{}{
+		"id": id, "name": name, "mod": nowMs / 1000, "usn": -1, "conf": 1, "dyn": 0,
+		"desc": "", "collapsed": false, "extendNew": 10, "extendRev": 50,
+		"newToday": []int{0, 0}, "revToday": []int{0, 0}, "lrnToday": []int{0, 0}, "timeToday": []int{0, 0},
+	}
+}
+
+// ankiDeckIDs 为每个场景分配稳定的牌组ID
+func ankiDeckIDs(records []Record) map[string]int64 {
+	var scenes []string
+	seen := make(map[string]bool)
+	for _, r := range records {
+		if !seen[r.Scene] {
+			seen[r.Scene] = true
+			scenes = append(scenes, r.Scene)
+		}
+	}
+	sort.Strings(scenes)
+
+	ids := make(map[string]int64, len(scenes))
+	for i, scene := range scenes {
+		ids[scene] = ankiModelID + int64(i) + 1
+	}
+	return ids
+}
+
+// ankiGUID 由场景和原文生成，重复导出同一句子时 Anki 会识别为同一笔记
+func ankiGUID(r Record) string {
+	sum := sha1.Sum([]byte(r.Scene + ankiFieldSeparator + r.Content))
+	return hex.EncodeToString(sum[:8])
+}
+
+// ankiModelFields 返回每个字段位置对应的记录字段；无法识别字段名时按“正面、背面”处理
+func ankiModelFields(model ankiModel, count int) []string {
+	fields := make([]string, count)
+	known := false
+	for _, f := range model.Flds {
+		if f.Ord < count {
+			fields[f.Ord] = ankiFieldAliases[strings.ToLower(f.Name)]
+			known = known || fields[f.Ord] != ""
+		}
+	}
+	if !known {
+		for i := range fields {
+			fields[i] = ""
+		}
+		if count > 0 {
+			fields[0] = "content"
+		}
+		if count > 1 {
+			fields[1] = "translation"
+		}
+	}
+	return fields
+}
+
+// ankiDeckScene 取牌组名的最后一级作为场景名
+func ankiDeckScene(deck string) string {
+	if deck == "" || deck == "Default" {
+		return ""
+	}
+	parts := strings.Split(deck, "::")
+	return strings.TrimSpace(parts[len(parts)-1])
+}
+
+// extractAnkiCollection 将卡组包中的集合数据库解压到临时文件
+func extractAnkiCollection(data []byte) (string, func(), error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid apkg archive: %w", err)
+	}
+
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+
+	for _, name := range ankiCollectionFiles {
+		f, ok := files[name]
+		if !ok {
+			continue
+		}
+		// 压缩包头中的大小可以伪造，先按声明的大小拒绝，解压时再按实际读取的字节数限制
+		if f.UncompressedSize64 > maxAnkiCollectionSize {
+			return "", nil, errAnkiCollectionTooLarge
+		}
+		src, err := f.Open()
+		if err != nil {
+			return "", nil, err
+		}
+		defer src.Close()
+
+		tmp, err := os.CreateTemp("", "voicewriter-anki-*.db")
+		if err != nil {
+			return "", nil, err
+		}
+		cleanup := func() { os.Remove(tmp.Name()) }
+		n, err := io.Copy(tmp, io.LimitReader(src, maxAnkiCollectionSize+1))
+		if err == nil && n > maxAnkiCollectionSize {
+			err = errAnkiCollectionTooLarge
+		}
+		if err != nil {
+			tmp.Close()
+			cleanup()
+			return "", nil, err
+		}
+		if err := tmp.Close(); err != nil {
+			cleanup()
+			return "", nil, err
+		}
+		return tmp.Name(), cleanup, nil
+	}
+
+	if _, ok := files["collection.anki21b"]; ok {
+		return "", nil, errors.New("apkg uses the compressed anki21b format; re-export with \"support older Anki versions\" enabled")
+	}
+	return "", nil, errors.New("apkg archive does not contain an anki collection")
+}
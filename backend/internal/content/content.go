@@ -0,0 +1,106 @@
+package content
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// 支持的导入导出格式
+const (
+	FormatCSV   = "csv"
+	FormatJSONL = "jsonl"
+	FormatAnki  = "anki" // Anki 纯文本（TSV）导出
+	FormatAPKG  = "apkg" // Anki 卡组包
+)
+
+// ErrUnsupportedFormat 不支持的导入导出格式
+var ErrUnsupportedFormat = errors.New("unsupported format")
+
+// Record 导入导出的一行句子数据，场景按名称关联
+type Record struct {
+	Line        int    `json:"-"` // 源文件中的行号（或笔记序号），用于报告错误
+	Scene       string `json:"scene"`
+	Language    string `json:"language,omitempty"`
+	Content     string `json:"content"`
+	Translation string `json:"translation,omitempty"`
+	Difficulty  string `json:"difficulty,omitempty"`
+	AudioURL    string `json:"audio_url,omitempty"`
+}
+
+// Decoder 将源数据解析为句子记录
+type Decoder func(data []byte) ([]Record, error)
+
+// Encoder 将句子记录写出为指定格式
+type Encoder func(w io.Writer, records []Record) error
+
+type codec struct {
+	decode      Decoder
+	encode      Encoder
+	contentType string
+	extension   string
+}
+
+var codecs = map[string]codec{
+	FormatCSV:   {decodeCSV, encodeCSV, "text/csv; charset=utf-8", ".csv"},
+	FormatJSONL: {decodeJSONL, encodeJSONL, "application/x-ndjson", ".jsonl"},
+	FormatAnki:  {decodeAnkiText, encodeAnkiText, "text/tab-separated-values; charset=utf-8", ".txt"},
+	FormatAPKG:  {decodeAPKG, encodeAPKG, "application/octet-stream", ".apkg"},
+}
+
+// Formats 返回支持的格式名称
+func Formats() []string {
+	return []string{FormatCSV, FormatJSONL, FormatAnki, FormatAPKG}
+}
+
+// Decode 按格式解析源数据
+func Decode(format string, data []byte) ([]Record, error) {
+	c, ok := codecs[format]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+	}
+	return c.decode(data)
+}
+
+// Encode 按格式写出句子记录
+func Encode(format string, w io.Writer, records []Record) error {
+	c, ok := codecs[format]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+	}
+	return c.encode(w, records)
+}
+
+// Supported 判断格式是否受支持
+func Supported(format string) bool {
+	_, ok := codecs[format]
+	return ok
+}
+
+// ContentType 返回格式对应的 MIME 类型
+func ContentType(format string) string {
+	return codecs[format].contentType
+}
+
+// Extension 返回格式对应的文件扩展名
+func Extension(format string) string {
+	return codecs[format].extension
+}
+
+// DetectFormat 根据文件名推断格式，无法识别时返回空字符串
+func DetectFormat(filename string) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return FormatCSV
+	case ".jsonl", ".ndjson":
+		return FormatJSONL
+	case ".txt", ".tsv":
+		return FormatAnki
+	case ".apkg":
+		return FormatAPKG
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,92 @@
+package content
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// utf8BOM Excel 导出的 CSV 常带 BOM
+var utf8BOM = []byte("\ufeff")
+
+// csvColumns CSV 表头，导入时按名称匹配列，顺序不限
+var csvColumns = []string{"scene", "language", "content", "translation", "difficulty", "audio_url"}
+
+func decodeCSV(data []byte) ([]Record, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, utf8BOM)))
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read csv header: %w", err)
+	}
+
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := index["content"]; !ok {
+		return nil, errors.New("csv header must contain a content column")
+	}
+
+	var records []Record
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read csv: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+		field := func(name string) string {
+			i, ok := index[name]
+			if !ok || i >= len(row) {
+				return ""
+			}
+			return strings.TrimSpace(row[i])
+		}
+		if isBlankRow(row) {
+			continue
+		}
+		records = append(records, Record{
+			Line:        line,
+			Scene:       field("scene"),
+			Language:    field("language"),
+			Content:     field("content"),
+			Translation: field("translation"),
+			Difficulty:  field("difficulty"),
+			AudioURL:    field("audio_url"),
+		})
+	}
+	return records, nil
+}
+
+func encodeCSV(w io.Writer, records []Record) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvColumns); err != nil {
+		return err
+	}
+	for _, r := range records {
+		if err := writer.Write([]string{r.Scene, r.Language, r.Content, r.Translation, r.Difficulty, r.AudioURL}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func isBlankRow(row []string) bool {
+	for _, v := range row {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
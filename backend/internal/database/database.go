@@ -1,35 +1,58 @@
 package database
 
 import (
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	"voicewriter/internal/config"
+	"voicewriter/internal/logging"
 	"voicewriter/internal/model"
 
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 	"gorm.io/gorm/schema"
 )
 
-// NewDatabase 创建数据库连接
+// 支持的数据库驱动
+const (
+	DriverSQLite   = "sqlite"
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+)
+
+// ErrUnsupportedDriver 配置的数据库驱动不支持
+var ErrUnsupportedDriver = errors.New("unsupported database driver")
+
+const (
+	// defaultSQLitePath 未配置路径时的 SQLite 数据库文件
+	defaultSQLitePath = "data/voicewriter.db"
+	// sqliteMemory SQLite 内存数据库路径
+	sqliteMemory = ":memory:"
+)
+
+// NewDatabase 创建数据库连接，驱动由 cfg.Driver 选择，默认 SQLite
 func NewDatabase(cfg *config.DatabaseConfig) (*gorm.DB, error) {
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=%s&parseTime=True&loc=Local",
-		cfg.Username,
-		cfg.Password,
-		cfg.Host,
-		cfg.Port,
-		cfg.DBName,
-		cfg.Charset,
-	)
-
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+	dialector, err := newDialector(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{
+		Logger: logging.NewGormLogger(time.Duration(cfg.SlowThreshold) * time.Millisecond),
 		NamingStrategy: schema.NamingStrategy{
 			SingularTable: true, // 使用单数表名
 		},
+		TranslateError: true, // 将唯一键冲突等驱动错误转换为 gorm.ErrDuplicatedKey
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect database: %w", err)
@@ -44,27 +67,58 @@ func NewDatabase(cfg *config.DatabaseConfig) (*gorm.DB, error) {
 	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
 	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
 	sqlDB.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetime) * time.Second)
+	if db.Dialector.Name() == DriverSQLite && cfg.Path == sqliteMemory {
+		// 内存数据库每个连接各自独立，只能使用一个连接，且连接关闭后数据随之丢失
+		sqlDB.SetMaxOpenConns(1)
+		sqlDB.SetMaxIdleConns(1)
+		sqlDB.SetConnMaxLifetime(0)
+	}
 
-	log.Println("Database connection established successfully")
+	slog.Info("database connection established", "driver", db.Dialector.Name())
 	return db, nil
 }
 
-// AutoMigrate 自动迁移数据库表结构
-func AutoMigrate(db *gorm.DB) error {
-	log.Println("Starting database migration...")
-
-	err := db.AutoMigrate(
-		&model.Scene{},
-		&model.Sentence{},
-		&model.UserProgress{},
-	)
-
-	if err != nil {
-		return fmt.Errorf("failed to migrate database: %w", err)
+// newDialector 按驱动生成 GORM 方言
+func newDialector(cfg *config.DatabaseConfig) (gorm.Dialector, error) {
+	switch driver := strings.ToLower(strings.TrimSpace(cfg.Driver)); driver {
+	case "", DriverSQLite, "sqlite3":
+		path := cfg.Path
+		if path == "" {
+			path = defaultSQLitePath
+		}
+		if path != sqliteMemory {
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				return nil, fmt.Errorf("failed to create sqlite directory: %w", err)
+			}
+		}
+		// 开启外键约束；WAL 模式允许读写并发，写冲突时等待而不是立即报错
+		return sqlite.Open(fmt.Sprintf("file:%s?_foreign_keys=on&_busy_timeout=5000&_journal_mode=WAL", path)), nil
+	case DriverMySQL:
+		dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=%s&parseTime=True&loc=Local",
+			cfg.Username,
+			cfg.Password,
+			cfg.Host,
+			cfg.Port,
+			cfg.DBName,
+			cfg.Charset,
+		)
+		return mysql.Open(dsn), nil
+	case DriverPostgres, "postgresql":
+		sslMode := cfg.SSLMode
+		if sslMode == "" {
+			sslMode = "disable"
+		}
+		dsn := url.URL{
+			Scheme:   "postgres",
+			User:     url.UserPassword(cfg.Username, cfg.Password),
+			Host:     net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)),
+			Path:     "/" + cfg.DBName,
+			RawQuery: url.Values{"sslmode": {sslMode}}.Encode(),
+		}
+		return postgres.Open(dsn.String()), nil
+	default:
+		return nil, fmt.Errorf("%w %q, must be sqlite, mysql or postgres", ErrUnsupportedDriver, cfg.Driver)
 	}
-
-	log.Println("Database migration completed successfully")
-	return nil
 }
 
 // SeedData 初始化种子数据
@@ -73,11 +127,11 @@ func SeedData(db *gorm.DB) error {
 	var count int64
 	db.Model(&model.Scene{}).Count(&count)
 	if count > 0 {
-		log.Println("Database already has data, skipping seed")
+		slog.Info("database already has data, skipping seed")
 		return nil
 	}
 
-	log.Println("Seeding initial data...")
+	slog.Info("seeding initial data")
 
 	// 创建场景数据
 	scenes := []*model.Scene{
@@ -108,53 +162,76 @@ func SeedData(db *gorm.DB) error {
 	sentences := []*model.Sentence{
 		{
 			SceneID:     1,
+			Language:    "en",
 			Content:     "Hello, how are you?",
 			Translation: "你好，你怎么样？",
-			AudioURL:    "/audio/1.mp3",
+			AudioURL:    "/api/v1/audio/1",
 			Difficulty:  "easy",
 		},
 		{
 			SceneID:     1,
+			Language:    "en",
 			Content:     "What's your name?",
 			Translation: "你叫什么名字？",
-			AudioURL:    "/audio/2.mp3",
+			AudioURL:    "/api/v1/audio/2",
 			Difficulty:  "easy",
 		},
 		{
 			SceneID:     1,
+			Language:    "en",
 			Content:     "Nice to meet you!",
 			Translation: "很高兴见到你！",
-			AudioURL:    "/audio/3.mp3",
+			AudioURL:    "/api/v1/audio/3",
 			Difficulty:  "easy",
 		},
 		{
 			SceneID:     2,
+			Language:    "en",
 			Content:     "Could you please send me the report?",
 			Translation: "你能把报告发给我吗？",
-			AudioURL:    "/audio/4.mp3",
+			AudioURL:    "/api/v1/audio/4",
 			Difficulty:  "medium",
 		},
 		{
 			SceneID:     2,
+			Language:    "en",
 			Content:     "Let's schedule a meeting for next week.",
 			Translation: "我们下周安排一个会议吧。",
-			AudioURL:    "/audio/5.mp3",
+			AudioURL:    "/api/v1/audio/5",
 			Difficulty:  "medium",
 		},
 		{
 			SceneID:     3,
+			Language:    "en",
 			Content:     "How much does this cost?",
 			Translation: "这个多少钱？",
-			AudioURL:    "/audio/6.mp3",
+			AudioURL:    "/api/v1/audio/6",
 			Difficulty:  "easy",
 		},
 		{
 			SceneID:     3,
+			Language:    "en",
 			Content:     "Where is the nearest subway station?",
 			Translation: "最近的地铁站在哪里？",
-			AudioURL:    "/audio/7.mp3",
+			AudioURL:    "/api/v1/audio/7",
 			Difficulty:  "medium",
 		},
+		{
+			SceneID:     3,
+			Language:    "ja",
+			Content:     "駅はどこですか？",
+			Translation: "车站在哪里？",
+			AudioURL:    "/api/v1/audio/8",
+			Difficulty:  "easy",
+		},
+		{
+			SceneID:     3,
+			Language:    "ja",
+			Content:     "これはいくらですか？",
+			Translation: "这个多少钱？",
+			AudioURL:    "/api/v1/audio/9",
+			Difficulty:  "easy",
+		},
 	}
 
 	for _, sentence := range sentences {
@@ -163,6 +240,33 @@ func SeedData(db *gorm.DB) error {
 		}
 	}
 
-	log.Println("Seed data created successfully")
+	// 创建场景的多语言名称
+	sceneTranslations := []*model.SceneTranslation{
+		{SceneID: scenes[0].ID, Locale: "en", Name: "Daily Life", Description: "Everyday conversations"},
+		{SceneID: scenes[0].ID, Locale: "ko", Name: "일상생활", Description: "일상생활에서 자주 쓰는 대화"},
+		{SceneID: scenes[0].ID, Locale: "ja", Name: "日常生活", Description: "日常生活でよく使う会話"},
+		{SceneID: scenes[1].ID, Locale: "en", Name: "Workplace", Description: "Professional conversations at work"},
+		{SceneID: scenes[1].ID, Locale: "ko", Name: "직장", Description: "직장에서 쓰는 업무 대화"},
+		{SceneID: scenes[1].ID, Locale: "ja", Name: "職場", Description: "職場で使うビジネス会話"},
+		{SceneID: scenes[2].ID, Locale: "en", Name: "Travel", Description: "Useful phrases for traveling"},
+		{SceneID: scenes[2].ID, Locale: "ko", Name: "여행", Description: "여행할 때 유용한 대화"},
+		{SceneID: scenes[2].ID, Locale: "ja", Name: "旅行", Description: "旅行で役立つ会話"},
+	}
+	if err := db.Create(sceneTranslations).Error; err != nil {
+		return fmt.Errorf("failed to create scene translations: %w", err)
+	}
+
+	// 创建日语句子的其他语言翻译
+	sentenceTranslations := []*model.SentenceTranslation{
+		{SentenceID: sentences[7].ID, Locale: "ko", Text: "역은 어디예요?"},
+		{SentenceID: sentences[7].ID, Locale: "en", Text: "Where is the station?"},
+		{SentenceID: sentences[8].ID, Locale: "ko", Text: "이거 얼마예요?"},
+		{SentenceID: sentences[8].ID, Locale: "en", Text: "How much is this?"},
+	}
+	if err := db.Create(sentenceTranslations).Error; err != nil {
+		return fmt.Errorf("failed to create sentence translations: %w", err)
+	}
+
+	slog.Info("seed data created")
 	return nil
 }
@@ -0,0 +1,32 @@
+package database
+
+import (
+	"voicewriter/internal/metrics"
+
+	"gorm.io/gorm"
+)
+
+// RegisterMetrics 注册连接池指标，抓取时读取 sql.DB.Stats()
+func RegisterMetrics(registry *metrics.Registry, db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	name := func(s string) string { return metrics.Namespace + "_db_" + s }
+
+	registry.Register(metrics.CollectorFunc(func() []*metrics.Family {
+		stats := sqlDB.Stats()
+		return []*metrics.Family{
+			metrics.Gauge(name("max_open_connections"), "Maximum number of open connections to the database.", float64(stats.MaxOpenConnections)),
+			metrics.Gauge(name("open_connections"), "Number of established connections, in use and idle.", float64(stats.OpenConnections)),
+			metrics.Gauge(name("in_use_connections"), "Number of connections currently in use.", float64(stats.InUse)),
+			metrics.Gauge(name("idle_connections"), "Number of idle connections.", float64(stats.Idle)),
+			metrics.Counter(name("wait_count_total"), "Total number of connections waited for.", float64(stats.WaitCount)),
+			metrics.Counter(name("wait_duration_seconds_total"), "Total time blocked waiting for a new connection.", stats.WaitDuration.Seconds()),
+			metrics.Counter(name("max_idle_closed_total"), "Total number of connections closed due to max idle connections.", float64(stats.MaxIdleClosed)),
+			metrics.Counter(name("max_idle_time_closed_total"), "Total number of connections closed due to max idle time.", float64(stats.MaxIdleTimeClosed)),
+			metrics.Counter(name("max_lifetime_closed_total"), "Total number of connections closed due to max connection lifetime.", float64(stats.MaxLifetimeClosed)),
+		}
+	}))
+	return nil
+}
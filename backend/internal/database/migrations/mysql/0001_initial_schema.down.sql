@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS scene_translations;
+DROP TABLE IF EXISTS sentence_translations;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS review_states;
+DROP TABLE IF EXISTS attempts;
+DROP TABLE IF EXISTS user_progress;
+DROP TABLE IF EXISTS sentences;
+DROP TABLE IF EXISTS scenes;
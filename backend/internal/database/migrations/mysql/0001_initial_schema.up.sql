@@ -0,0 +1,129 @@
+-- 初始表结构，与原 scripts/init_db.sql 一致；表已存在时跳过，便于接管由 AutoMigrate 创建的数据库
+
+-- 场景表
+CREATE TABLE IF NOT EXISTS scenes (
+    id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL COMMENT '场景名称',
+    description TEXT COMMENT '场景描述',
+    icon VARCHAR(50) COMMENT '图标',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    deleted_at TIMESTAMP NULL DEFAULT NULL COMMENT '删除时间',
+    INDEX idx_deleted_at (deleted_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='场景表';
+
+-- 句子表
+CREATE TABLE IF NOT EXISTS sentences (
+    id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    scene_id INT UNSIGNED NOT NULL COMMENT '场景ID',
+    language VARCHAR(20) NOT NULL DEFAULT 'en' COMMENT '目标语言（BCP 47），如 en, ja, ko',
+    content TEXT NOT NULL COMMENT '目标语言句子',
+    translation TEXT COMMENT '中文翻译（其他语言见 sentence_translations）',
+    audio_url VARCHAR(255) COMMENT '音频URL',
+    difficulty VARCHAR(20) DEFAULT 'easy' COMMENT '难度: easy, medium, hard',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    deleted_at TIMESTAMP NULL DEFAULT NULL COMMENT '删除时间',
+    INDEX idx_scene_id (scene_id),
+    INDEX idx_sentences_language (language),
+    INDEX idx_deleted_at (deleted_at),
+    FOREIGN KEY (scene_id) REFERENCES scenes(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='句子表';
+
+-- 用户进度表
+CREATE TABLE IF NOT EXISTS user_progress (
+    id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_id VARCHAR(100) NOT NULL COMMENT '用户ID',
+    sentence_id INT UNSIGNED NOT NULL COMMENT '句子ID',
+    completed BOOLEAN DEFAULT FALSE COMMENT '是否完成',
+    attempts INT DEFAULT 0 COMMENT '尝试次数',
+    last_attempt TIMESTAMP NULL DEFAULT NULL COMMENT '最后尝试时间',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    deleted_at TIMESTAMP NULL DEFAULT NULL COMMENT '删除时间',
+    INDEX idx_user_id (user_id),
+    INDEX idx_sentence_id (sentence_id),
+    INDEX idx_deleted_at (deleted_at),
+    UNIQUE KEY uk_user_sentence (user_id, sentence_id, deleted_at),
+    FOREIGN KEY (sentence_id) REFERENCES sentences(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='用户进度表';
+
+-- 作答记录表
+CREATE TABLE IF NOT EXISTS attempts (
+    id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_id VARCHAR(100) NOT NULL COMMENT '用户ID',
+    sentence_id INT UNSIGNED NOT NULL COMMENT '句子ID',
+    scene_id INT UNSIGNED NOT NULL COMMENT '场景ID',
+    submitted_text TEXT COMMENT '用户提交的文本',
+    score DOUBLE DEFAULT 0 COMMENT '得分 0-100',
+    completed BOOLEAN DEFAULT FALSE COMMENT '是否完全正确',
+    duration_ms INT DEFAULT 0 COMMENT '作答用时（毫秒）',
+    audio_replays INT DEFAULT 0 COMMENT '音频重播次数',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP COMMENT '作答时间',
+    deleted_at TIMESTAMP NULL DEFAULT NULL COMMENT '删除时间',
+    INDEX idx_attempts_user_created (user_id, created_at),
+    INDEX idx_sentence_id (sentence_id),
+    INDEX idx_scene_id (scene_id),
+    INDEX idx_deleted_at (deleted_at),
+    FOREIGN KEY (sentence_id) REFERENCES sentences(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='作答记录表';
+
+-- 间隔重复复习状态表
+CREATE TABLE IF NOT EXISTS review_states (
+    id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_id VARCHAR(100) NOT NULL COMMENT '用户ID',
+    sentence_id INT UNSIGNED NOT NULL COMMENT '句子ID',
+    ease_factor DOUBLE NOT NULL DEFAULT 2.5 COMMENT '难易系数',
+    interval_days INT NOT NULL DEFAULT 0 COMMENT '复习间隔（天）',
+    repetitions INT NOT NULL DEFAULT 0 COMMENT '连续答对次数',
+    lapses INT NOT NULL DEFAULT 0 COMMENT '遗忘次数',
+    due_at TIMESTAMP NOT NULL COMMENT '下次复习时间',
+    last_reviewed_at TIMESTAMP NULL DEFAULT NULL COMMENT '最后复习时间',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    deleted_at TIMESTAMP NULL DEFAULT NULL COMMENT '删除时间',
+    UNIQUE KEY uk_review_user_sentence (user_id, sentence_id),
+    INDEX idx_review_user_due (user_id, due_at),
+    INDEX idx_deleted_at (deleted_at),
+    FOREIGN KEY (sentence_id) REFERENCES sentences(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='间隔重复复习状态表';
+
+-- 用户表
+CREATE TABLE IF NOT EXISTS users (
+    id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    uid VARCHAR(100) NOT NULL COMMENT '用户标识，对应进度等表中的 user_id',
+    username VARCHAR(50) NOT NULL COMMENT '用户名',
+    password_hash VARCHAR(100) NOT NULL COMMENT 'bcrypt 密码哈希',
+    role VARCHAR(20) NOT NULL DEFAULT 'user' COMMENT '角色: user, admin',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    deleted_at TIMESTAMP NULL DEFAULT NULL COMMENT '删除时间',
+    UNIQUE KEY idx_users_uid (uid),
+    UNIQUE KEY idx_users_username (username),
+    INDEX idx_deleted_at (deleted_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='用户表';
+
+-- 句子翻译表
+CREATE TABLE IF NOT EXISTS sentence_translations (
+    id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    sentence_id INT UNSIGNED NOT NULL COMMENT '句子ID',
+    locale VARCHAR(20) NOT NULL COMMENT '翻译语言（BCP 47）',
+    text TEXT NOT NULL COMMENT '翻译内容',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    UNIQUE KEY uk_sentence_translation_locale (sentence_id, locale),
+    FOREIGN KEY (sentence_id) REFERENCES sentences(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='句子翻译表';
+
+-- 场景多语言表
+CREATE TABLE IF NOT EXISTS scene_translations (
+    id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    scene_id INT UNSIGNED NOT NULL COMMENT '场景ID',
+    locale VARCHAR(20) NOT NULL COMMENT '语言（BCP 47）',
+    name VARCHAR(100) NOT NULL COMMENT '场景名称',
+    description TEXT COMMENT '场景描述',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    UNIQUE KEY uk_scene_translation_locale (scene_id, locale),
+    FOREIGN KEY (scene_id) REFERENCES scenes(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='场景多语言表';
@@ -0,0 +1,18 @@
+-- 生词本：听写中漏写或拼错的词，每个 (用户, 语言, 词) 一条
+CREATE TABLE IF NOT EXISTS vocabulary (
+    id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_id VARCHAR(100) NOT NULL COMMENT '用户ID',
+    language VARCHAR(20) NOT NULL COMMENT '词所属语言',
+    word VARCHAR(100) NOT NULL COMMENT '规范化后的词',
+    sentence_id INT UNSIGNED NOT NULL COMMENT '最近一次出错的句子ID',
+    miss_count INT NOT NULL DEFAULT 0 COMMENT '出错次数',
+    last_seen_at TIMESTAMP NOT NULL COMMENT '最近一次出错时间',
+    starred BOOLEAN NOT NULL DEFAULT FALSE COMMENT '是否标星',
+    archived BOOLEAN NOT NULL DEFAULT FALSE COMMENT '是否归档',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    UNIQUE KEY uk_vocabulary_user_word (user_id, language, word),
+    INDEX idx_vocabulary_user_misses (user_id, miss_count),
+    INDEX idx_sentence_id (sentence_id),
+    FOREIGN KEY (sentence_id) REFERENCES sentences(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='生词本';
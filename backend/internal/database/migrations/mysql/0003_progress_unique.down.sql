@@ -0,0 +1,3 @@
+ALTER TABLE user_progress
+    DROP INDEX uk_user_sentence,
+    ADD UNIQUE KEY uk_user_sentence (user_id, sentence_id, deleted_at);
@@ -0,0 +1,22 @@
+-- 原唯一索引包含 deleted_at，未删除记录的 deleted_at 为 NULL，互不冲突，并不能阻止重复的进度记录
+-- 先把每个 (用户, 句子) 的全部记录（包括已软删除的）合并到最早的一条：累加作答次数，取最近的作答时间；
+-- 只要有一条未删除，合并后的记录就是未删除的，否则保留为软删除，再次练习时按重置处理
+
+-- 合并时会改写 deleted_at，可能与旧索引冲突，先删除旧索引
+ALTER TABLE user_progress DROP INDEX uk_user_sentence;
+
+UPDATE user_progress p
+JOIN (
+    SELECT user_id, sentence_id, MIN(id) AS keep_id, SUM(attempts) AS attempts,
+           MAX(completed) AS completed, MAX(last_attempt) AS last_attempt,
+           CASE WHEN COUNT(*) > COUNT(deleted_at) THEN NULL ELSE MAX(deleted_at) END AS deleted_at
+    FROM user_progress
+    GROUP BY user_id, sentence_id
+    HAVING COUNT(*) > 1
+) d ON p.id = d.keep_id
+SET p.attempts = d.attempts, p.completed = d.completed, p.last_attempt = d.last_attempt, p.deleted_at = d.deleted_at;
+
+DELETE p FROM user_progress p
+JOIN user_progress k ON k.user_id = p.user_id AND k.sentence_id = p.sentence_id AND k.id < p.id;
+
+ALTER TABLE user_progress ADD UNIQUE KEY uk_user_sentence (user_id, sentence_id);
@@ -0,0 +1,36 @@
+-- 听写练习会话：开始时由服务端选定并排列句子，逐句作答，结束时保存小结，可在其他设备上继续
+CREATE TABLE IF NOT EXISTS practice_sessions (
+    id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_id VARCHAR(100) NOT NULL COMMENT '用户ID',
+    mode VARCHAR(20) NOT NULL COMMENT '来源：scene 场景，review 复习队列',
+    scene_id INT UNSIGNED NULL COMMENT '场景ID，复习会话为空',
+    status VARCHAR(20) NOT NULL DEFAULT 'active' COMMENT '状态：active 进行中，finished 已结束',
+    total INT NOT NULL DEFAULT 0 COMMENT '句子数',
+    answered INT NOT NULL DEFAULT 0 COMMENT '已作答句子数',
+    completed INT NOT NULL DEFAULT 0 COMMENT '完全正确的句子数',
+    accuracy DOUBLE NOT NULL DEFAULT 0 COMMENT '已作答句子的平均得分',
+    duration_ms INT NOT NULL DEFAULT 0 COMMENT '作答用时合计（毫秒）',
+    started_at TIMESTAMP NOT NULL COMMENT '开始时间',
+    finished_at TIMESTAMP NULL COMMENT '结束时间',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    INDEX idx_practice_sessions_user_status (user_id, status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='听写练习会话';
+
+CREATE TABLE IF NOT EXISTS practice_session_items (
+    id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    session_id INT UNSIGNED NOT NULL COMMENT '会话ID',
+    position INT NOT NULL COMMENT '在会话中的顺序，从 0 开始',
+    sentence_id INT UNSIGNED NOT NULL COMMENT '句子ID',
+    attempts INT NOT NULL DEFAULT 0 COMMENT '本会话内的作答次数',
+    score DOUBLE NOT NULL DEFAULT 0 COMMENT '最近一次作答得分',
+    completed BOOLEAN NOT NULL DEFAULT FALSE COMMENT '最近一次作答是否完全正确',
+    duration_ms INT NOT NULL DEFAULT 0 COMMENT '作答用时合计（毫秒）',
+    missed_words TEXT COMMENT '本会话内漏写或拼错的词，空格分隔',
+    answered_at TIMESTAMP NULL COMMENT '最近一次作答时间',
+    UNIQUE KEY uk_session_item_position (session_id, position),
+    UNIQUE KEY uk_session_item_sentence (session_id, sentence_id),
+    INDEX idx_sentence_id (sentence_id),
+    FOREIGN KEY (session_id) REFERENCES practice_sessions(id) ON DELETE CASCADE,
+    FOREIGN KEY (sentence_id) REFERENCES sentences(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='练习会话中的句子';
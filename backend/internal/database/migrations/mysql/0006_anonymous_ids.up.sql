@@ -0,0 +1,10 @@
+-- 服务端签发的匿名用户ID：保存认领密钥的哈希，每个ID只能被认领一次
+CREATE TABLE IF NOT EXISTS anonymous_ids (
+    id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    anonymous_id VARCHAR(100) NOT NULL COMMENT '匿名用户ID，对应进度等表中的 user_id',
+    secret_hash VARCHAR(64) NOT NULL COMMENT '认领密钥的 SHA-256 哈希',
+    claimed_by VARCHAR(100) NULL DEFAULT NULL COMMENT '认领的账户 uid',
+    claimed_at TIMESTAMP NULL DEFAULT NULL COMMENT '认领时间',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP COMMENT '签发时间',
+    UNIQUE KEY uk_anonymous_ids_anonymous_id (anonymous_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='匿名用户ID';
@@ -0,0 +1,2 @@
+DELETE FROM vocabulary WHERE deleted_at IS NOT NULL;
+ALTER TABLE vocabulary DROP INDEX idx_deleted_at, DROP COLUMN deleted_at;
@@ -0,0 +1,4 @@
+-- 生词本改为软删除；唯一索引不含 deleted_at，写入时由仓储恢复或清除已软删除的记录
+ALTER TABLE vocabulary
+    ADD COLUMN deleted_at TIMESTAMP NULL DEFAULT NULL COMMENT '删除时间' AFTER updated_at,
+    ADD INDEX idx_deleted_at (deleted_at);
@@ -0,0 +1,4 @@
+DELETE FROM practice_session_items WHERE deleted_at IS NOT NULL;
+DELETE FROM practice_sessions WHERE deleted_at IS NOT NULL;
+ALTER TABLE practice_session_items DROP INDEX idx_deleted_at, DROP COLUMN deleted_at;
+ALTER TABLE practice_sessions DROP INDEX idx_deleted_at, DROP COLUMN deleted_at;
@@ -0,0 +1,7 @@
+-- 练习会话及其句子改为软删除；句子只随会话一次写入，同一会话内的唯一索引不会与软删除的记录冲突
+ALTER TABLE practice_sessions
+    ADD COLUMN deleted_at TIMESTAMP NULL DEFAULT NULL COMMENT '删除时间' AFTER updated_at,
+    ADD INDEX idx_deleted_at (deleted_at);
+ALTER TABLE practice_session_items
+    ADD COLUMN deleted_at TIMESTAMP NULL DEFAULT NULL COMMENT '删除时间' AFTER answered_at,
+    ADD INDEX idx_deleted_at (deleted_at);
@@ -0,0 +1,4 @@
+DELETE FROM sentence_translations WHERE deleted_at IS NOT NULL;
+DELETE FROM scene_translations WHERE deleted_at IS NOT NULL;
+ALTER TABLE sentence_translations DROP INDEX idx_deleted_at, DROP COLUMN deleted_at;
+ALTER TABLE scene_translations DROP INDEX idx_deleted_at, DROP COLUMN deleted_at;
@@ -0,0 +1,7 @@
+-- 句子和场景的翻译改为软删除；唯一索引不含 deleted_at，重新写入已删除的翻译时由仓储恢复原记录
+ALTER TABLE sentence_translations
+    ADD COLUMN deleted_at TIMESTAMP NULL DEFAULT NULL COMMENT '删除时间' AFTER updated_at,
+    ADD INDEX idx_deleted_at (deleted_at);
+ALTER TABLE scene_translations
+    ADD COLUMN deleted_at TIMESTAMP NULL DEFAULT NULL COMMENT '删除时间' AFTER updated_at,
+    ADD INDEX idx_deleted_at (deleted_at);
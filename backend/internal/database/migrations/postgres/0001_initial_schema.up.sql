@@ -0,0 +1,123 @@
+-- 初始表结构，与 MySQL 版本保持一致；表已存在时跳过，便于接管由 AutoMigrate 创建的数据库
+
+-- 场景表
+CREATE TABLE IF NOT EXISTS scenes (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    icon VARCHAR(50),
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_scenes_deleted_at ON scenes (deleted_at);
+
+-- 句子表
+CREATE TABLE IF NOT EXISTS sentences (
+    id BIGSERIAL PRIMARY KEY,
+    scene_id BIGINT NOT NULL REFERENCES scenes (id) ON DELETE CASCADE,
+    language VARCHAR(20) NOT NULL DEFAULT 'en',
+    content TEXT NOT NULL,
+    translation TEXT,
+    audio_url VARCHAR(255),
+    difficulty VARCHAR(20) DEFAULT 'easy',
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_sentences_scene_id ON sentences (scene_id);
+CREATE INDEX IF NOT EXISTS idx_sentences_language ON sentences (language);
+CREATE INDEX IF NOT EXISTS idx_sentences_deleted_at ON sentences (deleted_at);
+
+-- 用户进度表
+CREATE TABLE IF NOT EXISTS user_progress (
+    id BIGSERIAL PRIMARY KEY,
+    user_id VARCHAR(100) NOT NULL,
+    sentence_id BIGINT NOT NULL REFERENCES sentences (id) ON DELETE CASCADE,
+    completed BOOLEAN DEFAULT FALSE,
+    attempts INTEGER DEFAULT 0,
+    last_attempt TIMESTAMPTZ,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_user_progress_user_id ON user_progress (user_id);
+CREATE INDEX IF NOT EXISTS idx_user_progress_sentence_id ON user_progress (sentence_id);
+CREATE INDEX IF NOT EXISTS idx_user_progress_deleted_at ON user_progress (deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS uk_user_sentence ON user_progress (user_id, sentence_id, deleted_at);
+
+-- 作答记录表
+CREATE TABLE IF NOT EXISTS attempts (
+    id BIGSERIAL PRIMARY KEY,
+    user_id VARCHAR(100) NOT NULL,
+    sentence_id BIGINT NOT NULL REFERENCES sentences (id) ON DELETE CASCADE,
+    scene_id BIGINT NOT NULL,
+    submitted_text TEXT,
+    score DOUBLE PRECISION DEFAULT 0,
+    completed BOOLEAN DEFAULT FALSE,
+    duration_ms INTEGER DEFAULT 0,
+    audio_replays INTEGER DEFAULT 0,
+    created_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_attempts_user_created ON attempts (user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_attempts_sentence_id ON attempts (sentence_id);
+CREATE INDEX IF NOT EXISTS idx_attempts_scene_id ON attempts (scene_id);
+CREATE INDEX IF NOT EXISTS idx_attempts_deleted_at ON attempts (deleted_at);
+
+-- 间隔重复复习状态表
+CREATE TABLE IF NOT EXISTS review_states (
+    id BIGSERIAL PRIMARY KEY,
+    user_id VARCHAR(100) NOT NULL,
+    sentence_id BIGINT NOT NULL REFERENCES sentences (id) ON DELETE CASCADE,
+    ease_factor DOUBLE PRECISION NOT NULL DEFAULT 2.5,
+    interval_days INTEGER NOT NULL DEFAULT 0,
+    repetitions INTEGER NOT NULL DEFAULT 0,
+    lapses INTEGER NOT NULL DEFAULT 0,
+    due_at TIMESTAMPTZ NOT NULL,
+    last_reviewed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS uk_review_user_sentence ON review_states (user_id, sentence_id);
+CREATE INDEX IF NOT EXISTS idx_review_user_due ON review_states (user_id, due_at);
+CREATE INDEX IF NOT EXISTS idx_review_states_deleted_at ON review_states (deleted_at);
+
+-- 用户表
+CREATE TABLE IF NOT EXISTS users (
+    id BIGSERIAL PRIMARY KEY,
+    uid VARCHAR(100) NOT NULL,
+    username VARCHAR(50) NOT NULL,
+    password_hash VARCHAR(100) NOT NULL,
+    role VARCHAR(20) NOT NULL DEFAULT 'user',
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_uid ON users (uid);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_username ON users (username);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+
+-- 句子翻译表
+CREATE TABLE IF NOT EXISTS sentence_translations (
+    id BIGSERIAL PRIMARY KEY,
+    sentence_id BIGINT NOT NULL REFERENCES sentences (id) ON DELETE CASCADE,
+    locale VARCHAR(20) NOT NULL,
+    text TEXT NOT NULL,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS uk_sentence_translation_locale ON sentence_translations (sentence_id, locale);
+
+-- 场景多语言表
+CREATE TABLE IF NOT EXISTS scene_translations (
+    id BIGSERIAL PRIMARY KEY,
+    scene_id BIGINT NOT NULL REFERENCES scenes (id) ON DELETE CASCADE,
+    locale VARCHAR(20) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS uk_scene_translation_locale ON scene_translations (scene_id, locale);
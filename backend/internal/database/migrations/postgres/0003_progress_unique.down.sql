@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS uk_user_sentence;
+CREATE UNIQUE INDEX IF NOT EXISTS uk_user_sentence ON user_progress (user_id, sentence_id, deleted_at);
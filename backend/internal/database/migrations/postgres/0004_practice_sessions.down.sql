@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS practice_session_items;
+DROP TABLE IF EXISTS practice_sessions;
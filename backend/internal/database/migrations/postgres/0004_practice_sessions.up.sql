@@ -0,0 +1,34 @@
+-- 听写练习会话：开始时由服务端选定并排列句子，逐句作答，结束时保存小结，可在其他设备上继续
+CREATE TABLE IF NOT EXISTS practice_sessions (
+    id BIGSERIAL PRIMARY KEY,
+    user_id VARCHAR(100) NOT NULL,
+    mode VARCHAR(20) NOT NULL,
+    scene_id BIGINT,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    total INTEGER NOT NULL DEFAULT 0,
+    answered INTEGER NOT NULL DEFAULT 0,
+    completed INTEGER NOT NULL DEFAULT 0,
+    accuracy DOUBLE PRECISION NOT NULL DEFAULT 0,
+    duration_ms INTEGER NOT NULL DEFAULT 0,
+    started_at TIMESTAMPTZ NOT NULL,
+    finished_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_practice_sessions_user_status ON practice_sessions (user_id, status);
+
+CREATE TABLE IF NOT EXISTS practice_session_items (
+    id BIGSERIAL PRIMARY KEY,
+    session_id BIGINT NOT NULL REFERENCES practice_sessions (id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    sentence_id BIGINT NOT NULL REFERENCES sentences (id) ON DELETE CASCADE,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    score DOUBLE PRECISION NOT NULL DEFAULT 0,
+    completed BOOLEAN NOT NULL DEFAULT FALSE,
+    duration_ms INTEGER NOT NULL DEFAULT 0,
+    missed_words TEXT,
+    answered_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS uk_session_item_position ON practice_session_items (session_id, position);
+CREATE UNIQUE INDEX IF NOT EXISTS uk_session_item_sentence ON practice_session_items (session_id, sentence_id);
+CREATE INDEX IF NOT EXISTS idx_practice_session_items_sentence_id ON practice_session_items (sentence_id);
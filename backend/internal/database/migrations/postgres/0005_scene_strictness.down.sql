@@ -0,0 +1 @@
+ALTER TABLE scenes DROP COLUMN IF EXISTS strictness;
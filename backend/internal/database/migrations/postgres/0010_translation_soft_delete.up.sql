@@ -0,0 +1,5 @@
+-- 句子和场景的翻译改为软删除；唯一索引不含 deleted_at，重新写入已删除的翻译时由仓储恢复原记录
+ALTER TABLE sentence_translations ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+CREATE INDEX IF NOT EXISTS idx_sentence_translations_deleted_at ON sentence_translations (deleted_at);
+ALTER TABLE scene_translations ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+CREATE INDEX IF NOT EXISTS idx_scene_translations_deleted_at ON scene_translations (deleted_at);
@@ -0,0 +1,17 @@
+-- 生词本：听写中漏写或拼错的词，每个 (用户, 语言, 词) 一条
+CREATE TABLE IF NOT EXISTS vocabulary (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id VARCHAR(100) NOT NULL,
+    language VARCHAR(20) NOT NULL,
+    word VARCHAR(100) NOT NULL,
+    sentence_id INTEGER NOT NULL REFERENCES sentences (id) ON DELETE CASCADE,
+    miss_count INTEGER NOT NULL DEFAULT 0,
+    last_seen_at DATETIME NOT NULL,
+    starred BOOLEAN NOT NULL DEFAULT FALSE,
+    archived BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at DATETIME,
+    updated_at DATETIME
+);
+CREATE UNIQUE INDEX IF NOT EXISTS uk_vocabulary_user_word ON vocabulary (user_id, language, word);
+CREATE INDEX IF NOT EXISTS idx_vocabulary_user_misses ON vocabulary (user_id, miss_count);
+CREATE INDEX IF NOT EXISTS idx_vocabulary_sentence_id ON vocabulary (sentence_id);
@@ -0,0 +1,23 @@
+-- 原唯一索引包含 deleted_at，未删除记录的 deleted_at 为 NULL，互不冲突，并不能阻止重复的进度记录
+-- 先把每个 (用户, 句子) 的全部记录（包括已软删除的）合并到最早的一条：累加作答次数，取最近的作答时间；
+-- 只要有一条未删除，合并后的记录就是未删除的，否则保留为软删除，再次练习时按重置处理
+
+-- 合并时会改写 deleted_at，可能与旧索引冲突，先删除旧索引
+DROP INDEX IF EXISTS uk_user_sentence;
+
+UPDATE user_progress
+SET attempts = d.attempts, completed = d.completed, last_attempt = d.last_attempt, deleted_at = d.deleted_at
+FROM (
+    SELECT user_id, sentence_id, MIN(id) AS keep_id, SUM(attempts) AS attempts,
+           MAX(completed) AS completed, MAX(last_attempt) AS last_attempt,
+           CASE WHEN COUNT(*) > COUNT(deleted_at) THEN NULL ELSE MAX(deleted_at) END AS deleted_at
+    FROM user_progress
+    GROUP BY user_id, sentence_id
+    HAVING COUNT(*) > 1
+) AS d
+WHERE user_progress.id = d.keep_id;
+
+DELETE FROM user_progress
+WHERE id NOT IN (SELECT MIN(id) FROM user_progress GROUP BY user_id, sentence_id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uk_user_sentence ON user_progress (user_id, sentence_id);
@@ -0,0 +1,2 @@
+-- 场景的评分严格程度：strict 严格，normal 默认，lenient 宽松
+ALTER TABLE scenes ADD COLUMN strictness VARCHAR(20) NOT NULL DEFAULT 'normal';
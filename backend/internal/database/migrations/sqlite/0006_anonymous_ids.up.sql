@@ -0,0 +1,10 @@
+-- 服务端签发的匿名用户ID：保存认领密钥的哈希，每个ID只能被认领一次
+CREATE TABLE IF NOT EXISTS anonymous_ids (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    anonymous_id VARCHAR(100) NOT NULL,
+    secret_hash VARCHAR(64) NOT NULL,
+    claimed_by VARCHAR(100),
+    claimed_at DATETIME,
+    created_at DATETIME
+);
+CREATE UNIQUE INDEX IF NOT EXISTS uk_anonymous_ids_anonymous_id ON anonymous_ids (anonymous_id);
@@ -0,0 +1,4 @@
+-- 作答是否由服务端评分：未提交作答文本的作答得分为 0，不计入平均得分
+ALTER TABLE attempts ADD COLUMN graded BOOLEAN NOT NULL DEFAULT FALSE;
+-- 已有记录按是否提交了作答文本回填
+UPDATE attempts SET graded = TRUE WHERE TRIM(COALESCE(submitted_text, '')) <> '';
@@ -0,0 +1,3 @@
+DELETE FROM vocabulary WHERE deleted_at IS NOT NULL;
+DROP INDEX IF EXISTS idx_vocabulary_deleted_at;
+ALTER TABLE vocabulary DROP COLUMN deleted_at;
@@ -0,0 +1,3 @@
+-- 生词本改为软删除；唯一索引不含 deleted_at，写入时由仓储恢复或清除已软删除的记录
+ALTER TABLE vocabulary ADD COLUMN deleted_at DATETIME;
+CREATE INDEX IF NOT EXISTS idx_vocabulary_deleted_at ON vocabulary (deleted_at);
@@ -0,0 +1,6 @@
+DELETE FROM practice_session_items WHERE deleted_at IS NOT NULL;
+DELETE FROM practice_sessions WHERE deleted_at IS NOT NULL;
+DROP INDEX IF EXISTS idx_practice_session_items_deleted_at;
+ALTER TABLE practice_session_items DROP COLUMN deleted_at;
+DROP INDEX IF EXISTS idx_practice_sessions_deleted_at;
+ALTER TABLE practice_sessions DROP COLUMN deleted_at;
@@ -0,0 +1,5 @@
+-- 练习会话及其句子改为软删除；句子只随会话一次写入，同一会话内的唯一索引不会与软删除的记录冲突
+ALTER TABLE practice_sessions ADD COLUMN deleted_at DATETIME;
+CREATE INDEX IF NOT EXISTS idx_practice_sessions_deleted_at ON practice_sessions (deleted_at);
+ALTER TABLE practice_session_items ADD COLUMN deleted_at DATETIME;
+CREATE INDEX IF NOT EXISTS idx_practice_session_items_deleted_at ON practice_session_items (deleted_at);
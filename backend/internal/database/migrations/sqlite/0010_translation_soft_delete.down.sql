@@ -0,0 +1,6 @@
+DELETE FROM sentence_translations WHERE deleted_at IS NOT NULL;
+DELETE FROM scene_translations WHERE deleted_at IS NOT NULL;
+DROP INDEX IF EXISTS idx_sentence_translations_deleted_at;
+ALTER TABLE sentence_translations DROP COLUMN deleted_at;
+DROP INDEX IF EXISTS idx_scene_translations_deleted_at;
+ALTER TABLE scene_translations DROP COLUMN deleted_at;
@@ -0,0 +1,89 @@
+package database_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"voicewriter/internal/config"
+	"voicewriter/internal/database"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// TestProgressUniqueMigration 0003 把每个 (用户, 句子) 的重复进度（包括软删除的）合并为一条
+func TestProgressUniqueMigration(t *testing.T) {
+	ctx := context.Background()
+	db, err := database.NewDatabase(&config.DatabaseConfig{Driver: database.DriverSQLite, Path: ":memory:"})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	db = db.Session(&gorm.Session{Logger: logger.Default.LogMode(logger.Silent)})
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		t.Fatalf("load migrations: %v", err)
+	}
+	if _, err := migrator.Up(ctx, 2); err != nil {
+		t.Fatalf("migrate to 0002: %v", err)
+	}
+
+	t1 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2, t3 := t1.Add(time.Hour), t1.Add(2*time.Hour)
+	exec := func(sql string, args ...interface{}) {
+		t.Helper()
+		if err := db.Exec(sql, args...).Error; err != nil {
+			t.Fatalf("%s: %v", sql, err)
+		}
+	}
+	exec("INSERT INTO scenes (id, name) VALUES (1, 'daily')")
+	exec("INSERT INTO sentences (id, scene_id, content) VALUES (1, 1, 'a'), (2, 1, 'b'), (3, 1, 'c')")
+	insert := "INSERT INTO user_progress (user_id, sentence_id, completed, attempts, last_attempt, deleted_at) VALUES (?, ?, ?, ?, ?, ?)"
+	// 句子 1：两条未删除 + 一条软删除
+	exec(insert, "u1", 1, false, 2, t1, t2)
+	exec(insert, "u1", 1, false, 3, t2, nil)
+	exec(insert, "u1", 1, true, 1, t3, nil)
+	// 句子 2：只有软删除的记录
+	exec(insert, "u1", 2, true, 4, t1, t2)
+	exec(insert, "u1", 2, false, 1, t2, t3)
+	// 句子 3：没有重复
+	exec(insert, "u1", 3, false, 5, t1, nil)
+
+	if _, err := migrator.Up(ctx, 1); err != nil {
+		t.Fatalf("migrate 0003: %v", err)
+	}
+
+	type row struct {
+		SentenceID  uint
+		Completed   bool
+		Attempts    int
+		LastAttempt time.Time
+		DeletedAt   *time.Time
+	}
+	var rows []row
+	if err := db.Raw("SELECT sentence_id, completed, attempts, last_attempt, deleted_at FROM user_progress ORDER BY sentence_id").Scan(&rows).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("rows = %+v, want one per sentence", rows)
+	}
+	if r := rows[0]; r.Attempts != 6 || !r.Completed || !r.LastAttempt.Equal(t3) || r.DeletedAt != nil {
+		t.Errorf("sentence 1 = %+v, want 6 attempts, completed, live", r)
+	}
+	if r := rows[1]; r.Attempts != 5 || !r.Completed || r.DeletedAt == nil || !r.DeletedAt.Equal(t3) {
+		t.Errorf("sentence 2 = %+v, want 5 attempts, still soft-deleted", r)
+	}
+	if r := rows[2]; r.Attempts != 5 || r.DeletedAt != nil {
+		t.Errorf("sentence 3 = %+v, want unchanged", r)
+	}
+
+	// 合并后唯一索引生效，包括软删除的记录
+	if err := db.Exec(insert, "u1", 2, false, 1, t1, nil).Error; err == nil {
+		t.Error("duplicate (user, sentence) inserted after migration")
+	}
+}
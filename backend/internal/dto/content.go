@@ -0,0 +1,157 @@
+package dto
+
+import (
+	"time"
+
+	"voicewriter/internal/model"
+	"voicewriter/internal/service"
+)
+
+// Scene 场景
+type Scene struct {
+	ID          uint      `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Icon        string    `json:"icon"`
+	Strictness  string    `json:"strictness"`       // 评分严格程度：strict, normal, lenient
+	Locale      string    `json:"locale,omitempty"` // 本地化后名称和描述实际使用的语言
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// NewScene 将场景模型转换为响应
+func NewScene(scene *model.Scene) *Scene {
+	return &Scene{
+		ID:          scene.ID,
+		Name:        scene.Name,
+		Description: scene.Description,
+		Icon:        scene.Icon,
+		Strictness:  scene.Strictness,
+		Locale:      scene.Locale,
+		CreatedAt:   scene.CreatedAt,
+		UpdatedAt:   scene.UpdatedAt,
+	}
+}
+
+// NewScenes 转换场景列表
+func NewScenes(scenes []*model.Scene) []*Scene {
+	return mapAll(scenes, NewScene)
+}
+
+// Sentence 句子
+type Sentence struct {
+	ID                uint      `json:"id"`
+	SceneID           uint      `json:"scene_id"`
+	Language          string    `json:"language"`
+	Content           string    `json:"content"`
+	Translation       string    `json:"translation"`
+	TranslationLocale string    `json:"translation_locale,omitempty"` // 本地化后翻译实际使用的语言
+	AudioURL          string    `json:"audio_url"`
+	Difficulty        string    `json:"difficulty"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// NewSentence 将句子模型转换为响应，nil 返回 nil
+func NewSentence(sentence *model.Sentence) *Sentence {
+	if sentence == nil {
+		return nil
+	}
+	return &Sentence{
+		ID:                sentence.ID,
+		SceneID:           sentence.SceneID,
+		Language:          sentence.Language,
+		Content:           sentence.Content,
+		Translation:       sentence.Translation,
+		TranslationLocale: sentence.TranslationLocale,
+		AudioURL:          sentence.AudioURL,
+		Difficulty:        sentence.Difficulty,
+		CreatedAt:         sentence.CreatedAt,
+		UpdatedAt:         sentence.UpdatedAt,
+	}
+}
+
+// NewSentences 转换句子列表
+func NewSentences(sentences []*model.Sentence) []*Sentence {
+	return mapAll(sentences, NewSentence)
+}
+
+// SentenceTranslation 句子在某个语言下的翻译
+type SentenceTranslation struct {
+	SentenceID uint      `json:"sentence_id"`
+	Locale     string    `json:"locale"`
+	Text       string    `json:"text"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// NewSentenceTranslation 将句子翻译模型转换为响应
+func NewSentenceTranslation(translation *model.SentenceTranslation) *SentenceTranslation {
+	return &SentenceTranslation{
+		SentenceID: translation.SentenceID,
+		Locale:     translation.Locale,
+		Text:       translation.Text,
+		UpdatedAt:  translation.UpdatedAt,
+	}
+}
+
+// NewSentenceTranslations 转换句子翻译列表
+func NewSentenceTranslations(translations []*model.SentenceTranslation) []*SentenceTranslation {
+	return mapAll(translations, NewSentenceTranslation)
+}
+
+// SceneTranslation 场景在某个语言下的名称和描述
+type SceneTranslation struct {
+	SceneID     uint      `json:"scene_id"`
+	Locale      string    `json:"locale"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// NewSceneTranslation 将场景翻译模型转换为响应
+func NewSceneTranslation(translation *model.SceneTranslation) *SceneTranslation {
+	return &SceneTranslation{
+		SceneID:     translation.SceneID,
+		Locale:      translation.Locale,
+		Name:        translation.Name,
+		Description: translation.Description,
+		UpdatedAt:   translation.UpdatedAt,
+	}
+}
+
+// NewSceneTranslations 转换场景翻译列表
+func NewSceneTranslations(translations []*model.SceneTranslation) []*SceneTranslation {
+	return mapAll(translations, NewSceneTranslation)
+}
+
+// ImportError 导入时某一行的校验错误
+type ImportError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// ImportReport 导入结果报告
+type ImportReport struct {
+	Format        string        `json:"format"`
+	DryRun        bool          `json:"dry_run"`
+	Total         int           `json:"total"`
+	Created       int           `json:"created"`
+	Skipped       int           `json:"skipped"` // 与已有句子或本批前面的行重复
+	ScenesCreated []string      `json:"scenes_created"`
+	Errors        []ImportError `json:"errors"`
+}
+
+// NewImportReport 转换导入结果报告
+func NewImportReport(report *service.ImportReport) *ImportReport {
+	return &ImportReport{
+		Format:        report.Format,
+		DryRun:        report.DryRun,
+		Total:         report.Total,
+		Created:       report.Created,
+		Skipped:       report.Skipped,
+		ScenesCreated: append([]string{}, report.ScenesCreated...),
+		Errors: mapAll(report.Errors, func(e service.ImportError) ImportError {
+			return ImportError(e)
+		}),
+	}
+}
@@ -0,0 +1,12 @@
+// Package dto 定义接口响应的数据结构，以及从 internal/model 到响应的显式映射
+// Handler 只返回这里的类型，模型新增的字段、关联和软删除等内部信息不会随之出现在响应中
+package dto
+
+// mapAll 逐个转换列表，nil 列表返回空列表，响应中始终为 []
+func mapAll[T, R any](items []T, convert func(T) R) []R {
+	result := make([]R, len(items))
+	for i, item := range items {
+		result[i] = convert(item)
+	}
+	return result
+}
@@ -0,0 +1,37 @@
+package dto
+
+import "voicewriter/internal/health"
+
+// Liveness 存活探针结果
+type Liveness struct {
+	Status string    `json:"status"`
+	Build  BuildInfo `json:"build"`
+}
+
+// BuildInfo 构建信息
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Revision  string `json:"revision,omitempty"`
+	BuildTime string `json:"build_time,omitempty"`
+	GoVersion string `json:"go_version"`
+}
+
+// Readiness 就绪探针结果，只公开各依赖的状态；错误、耗时和连接池等详情只写入日志
+type Readiness struct {
+	Status     string            `json:"status"`
+	Components map[string]string `json:"components"` // 依赖名称 -> up / down
+}
+
+// NewLiveness 转换存活探针结果
+func NewLiveness(build health.BuildInfo) *Liveness {
+	return &Liveness{Status: health.StatusUp, Build: BuildInfo(build)}
+}
+
+// NewReadiness 转换就绪检查报告
+func NewReadiness(report *health.Report) *Readiness {
+	components := make(map[string]string, len(report.Components))
+	for name, component := range report.Components {
+		components[name] = component.Status
+	}
+	return &Readiness{Status: report.Status, Components: components}
+}
@@ -0,0 +1,197 @@
+package dto
+
+import (
+	"time"
+
+	"voicewriter/internal/model"
+	"voicewriter/internal/service"
+)
+
+// Progress 当前用户在一个句子上的进度
+type Progress struct {
+	ID          uint      `json:"id"`
+	SentenceID  uint      `json:"sentence_id"`
+	Completed   bool      `json:"completed"`
+	Attempts    int       `json:"attempts"`
+	LastAttempt time.Time `json:"last_attempt"`
+}
+
+// NewProgress 将进度模型转换为响应
+func NewProgress(progress *model.UserProgress) *Progress {
+	return &Progress{
+		ID:          progress.ID,
+		SentenceID:  progress.SentenceID,
+		Completed:   progress.Completed,
+		Attempts:    progress.Attempts,
+		LastAttempt: progress.LastAttempt,
+	}
+}
+
+// NewProgressList 转换进度列表
+func NewProgressList(progress []*model.UserProgress) []*Progress {
+	return mapAll(progress, NewProgress)
+}
+
+// Attempt 一次作答记录
+type Attempt struct {
+	ID            uint      `json:"id"`
+	SentenceID    uint      `json:"sentence_id"`
+	SceneID       uint      `json:"scene_id"`
+	SubmittedText string    `json:"submitted_text"`
+	Score         float64   `json:"score"` // 0-100
+	Completed     bool      `json:"completed"`
+	DurationMs    int       `json:"duration_ms"`
+	AudioReplays  int       `json:"audio_replays"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// NewAttempt 将作答记录模型转换为响应
+func NewAttempt(attempt *model.Attempt) *Attempt {
+	return &Attempt{
+		ID:            attempt.ID,
+		SentenceID:    attempt.SentenceID,
+		SceneID:       attempt.SceneID,
+		SubmittedText: attempt.SubmittedText,
+		Score:         attempt.Score,
+		Completed:     attempt.Completed,
+		DurationMs:    attempt.DurationMs,
+		AudioReplays:  attempt.AudioReplays,
+		CreatedAt:     attempt.CreatedAt,
+	}
+}
+
+// NewAttempts 转换作答记录列表
+func NewAttempts(attempts []*model.Attempt) []*Attempt {
+	return mapAll(attempts, NewAttempt)
+}
+
+// Review 到期复习的句子，SM-2 的难易系数等内部状态不返回
+type Review struct {
+	SentenceID     uint      `json:"sentence_id"`
+	Sentence       *Sentence `json:"sentence,omitempty"`
+	DueAt          time.Time `json:"due_at"`
+	IntervalDays   int       `json:"interval_days"`
+	Repetitions    int       `json:"repetitions"` // 连续答对次数
+	LastReviewedAt time.Time `json:"last_reviewed_at"`
+}
+
+// NewReview 将复习状态模型转换为响应
+func NewReview(state *model.ReviewState) *Review {
+	return &Review{
+		SentenceID:     state.SentenceID,
+		Sentence:       NewSentence(state.Sentence),
+		DueAt:          state.DueAt,
+		IntervalDays:   state.IntervalDays,
+		Repetitions:    state.Repetitions,
+		LastReviewedAt: state.LastReviewedAt,
+	}
+}
+
+// NewReviews 转换复习列表
+func NewReviews(states []*model.ReviewState) []*Review {
+	return mapAll(states, NewReview)
+}
+
+// TokenDiff 单个词的比对结果
+type TokenDiff struct {
+	Status   string `json:"status"` // match, missing, extra, misspelled
+	Expected string `json:"expected,omitempty"`
+	Actual   string `json:"actual,omitempty"`
+}
+
+// CheckResult 听写答案检查结果
+type CheckResult struct {
+	SentenceID uint        `json:"sentence_id"`
+	Answer     string      `json:"answer"`
+	Corrected  string      `json:"corrected"`
+	Tokens     []TokenDiff `json:"tokens"`
+	Accuracy   float64     `json:"accuracy"` // 0-100
+	Correct    bool        `json:"correct"`
+}
+
+// NewCheckResult 转换答案检查结果
+func NewCheckResult(result *service.CheckResult) *CheckResult {
+	return &CheckResult{
+		SentenceID: result.SentenceID,
+		Answer:     result.Answer,
+		Corrected:  result.Corrected,
+		Tokens: mapAll(result.Tokens, func(token service.TokenDiff) TokenDiff {
+			return TokenDiff{Status: string(token.Status), Expected: token.Expected, Actual: token.Actual}
+		}),
+		Accuracy: result.Accuracy,
+		Correct:  result.Correct,
+	}
+}
+
+// SearchResult 搜索命中的句子及相关度
+type SearchResult struct {
+	Sentence   *Sentence         `json:"sentence"`
+	Score      float64           `json:"score"`
+	Highlights map[string]string `json:"highlights"`
+}
+
+// NewSearchResults 转换搜索结果
+func NewSearchResults(results service.SearchResults) []*SearchResult {
+	return mapAll(results, func(result *service.SearchResult) *SearchResult {
+		return &SearchResult{
+			Sentence:   NewSentence(result.Sentence),
+			Score:      result.Score,
+			Highlights: result.Highlights,
+		}
+	})
+}
+
+// VocabularyEntry 生词本中的一个词
+type VocabularyEntry struct {
+	ID         uint      `json:"id"`
+	Language   string    `json:"language"`
+	Word       string    `json:"word"`
+	SentenceID uint      `json:"sentence_id"` // 最近一次出错的句子
+	Sentence   *Sentence `json:"sentence,omitempty"`
+	MissCount  int       `json:"miss_count"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	Starred    bool      `json:"starred"`
+	Archived   bool      `json:"archived"`
+}
+
+// NewVocabularyEntry 将生词模型转换为响应
+func NewVocabularyEntry(entry *model.VocabularyEntry) *VocabularyEntry {
+	return &VocabularyEntry{
+		ID:         entry.ID,
+		Language:   entry.Language,
+		Word:       entry.Word,
+		SentenceID: entry.SentenceID,
+		Sentence:   NewSentence(entry.Sentence),
+		MissCount:  entry.MissCount,
+		LastSeenAt: entry.LastSeenAt,
+		Starred:    entry.Starred,
+		Archived:   entry.Archived,
+	}
+}
+
+// NewVocabulary 转换生词列表
+func NewVocabulary(entries []*model.VocabularyEntry) []*VocabularyEntry {
+	return mapAll(entries, NewVocabularyEntry)
+}
+
+// PracticeSentence 练习句子及其包含的生词
+type PracticeSentence struct {
+	Sentence *Sentence `json:"sentence"`
+	Words    []string  `json:"words"`
+}
+
+// PracticeSet 生词练习内容
+type PracticeSet struct {
+	Words     []*VocabularyEntry `json:"words"`
+	Sentences []PracticeSentence `json:"sentences"`
+}
+
+// NewPracticeSet 转换生词练习内容
+func NewPracticeSet(set *service.PracticeSet) *PracticeSet {
+	return &PracticeSet{
+		Words: NewVocabulary(set.Words),
+		Sentences: mapAll(set.Sentences, func(s service.PracticeSentence) PracticeSentence {
+			return PracticeSentence{Sentence: NewSentence(s.Sentence), Words: s.Words}
+		}),
+	}
+}
@@ -0,0 +1,132 @@
+package dto
+
+import (
+	"strings"
+	"time"
+
+	"voicewriter/internal/model"
+	"voicewriter/internal/service"
+)
+
+// Session 听写练习会话，列表中不包含句子和小结
+type Session struct {
+	ID           uint            `json:"id"`
+	Mode         string          `json:"mode"`
+	SceneID      *uint           `json:"scene_id"`
+	Status       string          `json:"status"`
+	Total        int             `json:"total"`
+	Answered     int             `json:"answered"`
+	Completed    int             `json:"completed"`
+	Accuracy     float64         `json:"accuracy"`
+	DurationMs   int             `json:"duration_ms"`
+	StartedAt    time.Time       `json:"started_at"`
+	FinishedAt   *time.Time      `json:"finished_at"`
+	NextPosition *int            `json:"next_position,omitempty"` // 第一个未作答句子的位置
+	Items        []*SessionItem  `json:"items,omitempty"`
+	Summary      *SessionSummary `json:"summary,omitempty"`
+}
+
+// SessionItem 会话中的一个句子及其作答结果
+type SessionItem struct {
+	Position    int        `json:"position"`
+	SentenceID  uint       `json:"sentence_id"`
+	Sentence    *Sentence  `json:"sentence,omitempty"`
+	Answered    bool       `json:"answered"`
+	Attempts    int        `json:"attempts"`
+	Score       float64    `json:"score"`
+	Completed   bool       `json:"completed"`
+	DurationMs  int        `json:"duration_ms"`
+	MissedWords []string   `json:"missed_words"`
+	AnsweredAt  *time.Time `json:"answered_at"`
+}
+
+// SessionSummary 会话小结
+type SessionSummary struct {
+	Total       int         `json:"total"`
+	Answered    int         `json:"answered"`
+	Completed   int         `json:"completed"`
+	Skipped     int         `json:"skipped"`
+	Accuracy    float64     `json:"accuracy"`
+	DurationMs  int         `json:"duration_ms"`
+	ElapsedMs   int64       `json:"elapsed_ms"`
+	MissedWords []string    `json:"missed_words"`
+	Retry       []*Sentence `json:"retry"`
+}
+
+// SessionAnswer 提交答案的结果
+type SessionAnswer struct {
+	Item         *SessionItem `json:"item"`
+	Result       *CheckResult `json:"result"`
+	NextPosition *int         `json:"next_position"`
+}
+
+// NewSession 将会话模型转换为列表中的响应
+func NewSession(session *model.PracticeSession) *Session {
+	return &Session{
+		ID:         session.ID,
+		Mode:       session.Mode,
+		SceneID:    session.SceneID,
+		Status:     session.Status,
+		Total:      session.Total,
+		Answered:   session.Answered,
+		Completed:  session.Completed,
+		Accuracy:   session.Accuracy,
+		DurationMs: session.DurationMs,
+		StartedAt:  session.StartedAt,
+		FinishedAt: session.FinishedAt,
+	}
+}
+
+// NewSessions 转换会话列表
+func NewSessions(sessions []*model.PracticeSession) []*Session {
+	return mapAll(sessions, NewSession)
+}
+
+// NewSessionDetail 转换会话详情，包含各句作答结果和小结
+func NewSessionDetail(detail *service.SessionDetail) *Session {
+	session := NewSession(detail.Session)
+	session.NextPosition = detail.NextPosition
+	session.Items = mapAll(detail.Session.Items, NewSessionItem)
+	session.Summary = NewSessionSummary(detail.Summary)
+	return session
+}
+
+// NewSessionItem 将会话句子模型转换为响应
+func NewSessionItem(item *model.PracticeSessionItem) *SessionItem {
+	return &SessionItem{
+		Position:    item.Position,
+		SentenceID:  item.SentenceID,
+		Sentence:    NewSentence(item.Sentence),
+		Answered:    item.AnsweredAt != nil,
+		Attempts:    item.Attempts,
+		Score:       item.Score,
+		Completed:   item.Completed,
+		DurationMs:  item.DurationMs,
+		MissedWords: strings.Fields(item.MissedWords),
+		AnsweredAt:  item.AnsweredAt,
+	}
+}
+
+// NewSessionSummary 转换会话小结
+func NewSessionSummary(summary *service.SessionSummary) *SessionSummary {
+	return &SessionSummary{
+		Total:       summary.Total,
+		Answered:    summary.Answered,
+		Completed:   summary.Completed,
+		Skipped:     summary.Skipped,
+		Accuracy:    summary.Accuracy,
+		DurationMs:  summary.DurationMs,
+		ElapsedMs:   summary.ElapsedMs,
+		MissedWords: summary.MissedWords,
+		Retry:       NewSentences(summary.Retry),
+	}
+}
+
+// NewSessionAnswer 转换提交答案的结果
+func NewSessionAnswer(answer *service.SessionAnswer) *SessionAnswer {
+	return &SessionAnswer{
+		Item:         NewSessionItem(answer.Item),
+		Result:       NewCheckResult(answer.Result),
+		NextPosition: answer.NextPosition,
+	}
+}
@@ -0,0 +1,110 @@
+package dto
+
+import (
+	"time"
+
+	"voicewriter/internal/service"
+)
+
+// LearnerStats 用户的学习统计
+type LearnerStats struct {
+	UserID        string                 `json:"user_id"`
+	Summary       StatsSummary           `json:"summary"`
+	Scenes        []SceneCompletion      `json:"scenes"`
+	Difficulties  []DifficultyCompletion `json:"difficulties"`
+	AccuracyTrend []DailyAccuracy        `json:"accuracy_trend"`
+	MissedWords   []MissedWord           `json:"missed_words"`
+	Comparison    PopulationComparison   `json:"comparison"`
+}
+
+// StatsSummary 学习概况
+type StatsSummary struct {
+	TotalSentences     int64      `json:"total_sentences"`
+	PracticedSentences int64      `json:"practiced_sentences"`
+	CompletedSentences int64      `json:"completed_sentences"`
+	CompletionRate     float64    `json:"completion_rate"` // 0-1
+	TotalAttempts      int64      `json:"total_attempts"`
+	GradedAttempts     int64      `json:"graded_attempts"`
+	AverageAccuracy    float64    `json:"average_accuracy"` // 0-100
+	PracticeTimeMs     int64      `json:"practice_time_ms"`
+	CurrentStreak      int        `json:"current_streak"`
+	LongestStreak      int        `json:"longest_streak"`
+	LastPracticedAt    *time.Time `json:"last_practiced_at,omitempty"`
+}
+
+// Completion 一组句子的完成情况
+type Completion struct {
+	Total          int64   `json:"total"`
+	Practiced      int64   `json:"practiced"`
+	Completed      int64   `json:"completed"`
+	CompletionRate float64 `json:"completion_rate"` // 0-1
+}
+
+// SceneCompletion 场景的完成情况
+type SceneCompletion struct {
+	SceneID   uint   `json:"scene_id"`
+	SceneName string `json:"scene_name"`
+	Completion
+}
+
+// DifficultyCompletion 难度的完成情况
+type DifficultyCompletion struct {
+	Difficulty string `json:"difficulty"`
+	Completion
+}
+
+// DailyAccuracy 某天的作答情况
+type DailyAccuracy struct {
+	Date            string  `json:"date"` // YYYY-MM-DD
+	Attempts        int     `json:"attempts"`
+	GradedAttempts  int     `json:"graded_attempts"`
+	AverageAccuracy float64 `json:"average_accuracy"`
+	PracticeTimeMs  int64   `json:"practice_time_ms"`
+}
+
+// MissedWord 易错词及累计出错次数
+type MissedWord struct {
+	Word  string `json:"word"`
+	Count int    `json:"count"`
+}
+
+// PopulationComparison 与全体有练习记录的用户对比
+type PopulationComparison struct {
+	Users              int              `json:"users"`
+	CompletedSentences MetricComparison `json:"completed_sentences"`
+	AverageAccuracy    MetricComparison `json:"average_accuracy"`
+	PracticeTimeMs     MetricComparison `json:"practice_time_ms"`
+}
+
+// MetricComparison 单项指标与全体用户中位数的对比
+type MetricComparison struct {
+	Value      float64 `json:"value"`
+	Median     float64 `json:"median"`
+	Percentile float64 `json:"percentile"` // 0-100
+}
+
+// NewLearnerStats 转换学习统计
+func NewLearnerStats(stats *service.LearnerStats) *LearnerStats {
+	return &LearnerStats{
+		UserID:  stats.UserID,
+		Summary: StatsSummary(stats.Summary),
+		Scenes: mapAll(stats.Scenes, func(s service.SceneCompletion) SceneCompletion {
+			return SceneCompletion{SceneID: s.SceneID, SceneName: s.SceneName, Completion: Completion(s.Completion)}
+		}),
+		Difficulties: mapAll(stats.Difficulties, func(d service.DifficultyCompletion) DifficultyCompletion {
+			return DifficultyCompletion{Difficulty: d.Difficulty, Completion: Completion(d.Completion)}
+		}),
+		AccuracyTrend: mapAll(stats.AccuracyTrend, func(d service.DailyAccuracy) DailyAccuracy {
+			return DailyAccuracy(d)
+		}),
+		MissedWords: mapAll(stats.MissedWords, func(w service.MissedWord) MissedWord {
+			return MissedWord(w)
+		}),
+		Comparison: PopulationComparison{
+			Users:              stats.Comparison.Users,
+			CompletedSentences: MetricComparison(stats.Comparison.CompletedSentences),
+			AverageAccuracy:    MetricComparison(stats.Comparison.AverageAccuracy),
+			PracticeTimeMs:     MetricComparison(stats.Comparison.PracticeTimeMs),
+		},
+	}
+}
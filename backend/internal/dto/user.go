@@ -0,0 +1,78 @@
+package dto
+
+import (
+	"time"
+
+	"voicewriter/internal/auth"
+	"voicewriter/internal/model"
+	"voicewriter/internal/service"
+)
+
+// User 用户
+type User struct {
+	ID        uint      `json:"id"`
+	UID       string    `json:"uid"` // 进度、作答等数据使用的用户标识
+	Username  string    `json:"username"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewUser 将用户模型转换为响应
+func NewUser(user *model.User) *User {
+	return &User{
+		ID:        user.ID,
+		UID:       user.UID,
+		Username:  user.Username,
+		Role:      user.Role,
+		CreatedAt: user.CreatedAt,
+	}
+}
+
+// AuthResult 注册、登录和刷新令牌的结果，匿名身份刷新令牌时 user 为 null
+type AuthResult struct {
+	User   *User           `json:"user"`
+	Tokens *auth.TokenPair `json:"tokens"`
+}
+
+// NewAuthResult 转换认证结果
+func NewAuthResult(result *service.AuthResult) *AuthResult {
+	converted := &AuthResult{Tokens: result.Tokens}
+	if result.User != nil {
+		converted.User = NewUser(result.User)
+	}
+	return converted
+}
+
+// AnonymousCredentials 签发的匿名ID、认领密钥和匿名身份的令牌
+type AnonymousCredentials struct {
+	AnonymousID string          `json:"anonymous_id"`
+	ClaimSecret string          `json:"claim_secret"` // 只在签发时返回一次
+	Tokens      *auth.TokenPair `json:"tokens"`
+}
+
+// NewAnonymousCredentials 转换匿名身份签发结果
+func NewAnonymousCredentials(credentials *service.AnonymousCredentials) *AnonymousCredentials {
+	return &AnonymousCredentials{
+		AnonymousID: credentials.AnonymousID,
+		ClaimSecret: credentials.ClaimSecret,
+		Tokens:      credentials.Tokens,
+	}
+}
+
+// ClaimResult 认领匿名进度时各表转入的记录数
+type ClaimResult struct {
+	Progress   int64 `json:"progress"`
+	Attempts   int64 `json:"attempts"`
+	Reviews    int64 `json:"reviews"`
+	Vocabulary int64 `json:"vocabulary"`
+}
+
+// NewClaimResult 转换认领结果
+func NewClaimResult(result *service.ClaimResult) *ClaimResult {
+	return &ClaimResult{
+		Progress:   result.Progress,
+		Attempts:   result.Attempts,
+		Reviews:    result.Reviews,
+		Vocabulary: result.Vocabulary,
+	}
+}
@@ -0,0 +1,517 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"voicewriter/internal/content"
+	"voicewriter/internal/dto"
+	"voicewriter/internal/service"
+	"voicewriter/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminHandler 内容管理处理器
+type AdminHandler struct {
+	sceneService       *service.SceneService
+	sentenceService    *service.SentenceService
+	contentService     *service.ContentService
+	translationService *service.TranslationService
+}
+
+// maxImportSize 导入文件大小上限
+const maxImportSize = 32 << 20
+
+// NewAdminHandler 创建内容管理处理器实例
+func NewAdminHandler(
+	sceneService *service.SceneService,
+	sentenceService *service.SentenceService,
+	contentService *service.ContentService,
+	translationService *service.TranslationService,
+) *AdminHandler {
+	return &AdminHandler{
+		sceneService:       sceneService,
+		sentenceService:    sentenceService,
+		contentService:     contentService,
+		translationService: translationService,
+	}
+}
+
+// CreateScene 创建场景
+// @Summary 创建场景
+// @Tags 管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body service.CreateSceneRequest true "场景信息"
+// @Success 200 {object} response.Response
+// @Failure 409 {object} response.Response "场景名称已存在"
+// @Router /api/v1/admin/scenes [post]
+func (h *AdminHandler) CreateScene(c *gin.Context) {
+	var req service.CreateSceneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	scene, err := h.sceneService.CreateScene(c.Request.Context(), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, dto.NewScene(scene))
+}
+
+// UpdateScene 整体更新场景
+// @Summary 整体更新场景
+// @Tags 管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "场景ID"
+// @Param request body service.UpdateSceneRequest true "场景信息"
+// @Success 200 {object} response.Response
+// @Router /api/v1/admin/scenes/{id} [put]
+func (h *AdminHandler) UpdateScene(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid scene ID")
+	if !ok {
+		return
+	}
+
+	var req service.UpdateSceneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	scene, err := h.sceneService.UpdateScene(c.Request.Context(), id, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, dto.NewScene(scene))
+}
+
+// PatchScene 部分更新场景
+// @Summary 部分更新场景
+// @Tags 管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "场景ID"
+// @Param request body service.PatchSceneRequest true "需要修改的字段"
+// @Success 200 {object} response.Response
+// @Router /api/v1/admin/scenes/{id} [patch]
+func (h *AdminHandler) PatchScene(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid scene ID")
+	if !ok {
+		return
+	}
+
+	var req service.PatchSceneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	scene, err := h.sceneService.PatchScene(c.Request.Context(), id, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, dto.NewScene(scene))
+}
+
+// DeleteScene 删除场景
+// @Summary 删除场景
+// @Description 场景下仍有句子时返回 409
+// @Tags 管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "场景ID"
+// @Success 200 {object} response.Response
+// @Router /api/v1/admin/scenes/{id} [delete]
+func (h *AdminHandler) DeleteScene(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid scene ID")
+	if !ok {
+		return
+	}
+
+	if err := h.sceneService.DeleteScene(c.Request.Context(), id); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "Scene deleted successfully", nil)
+}
+
+// CreateSentence 创建句子
+// @Summary 创建句子
+// @Tags 管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body service.CreateSentenceRequest true "句子信息"
+// @Success 200 {object} response.Response
+// @Router /api/v1/admin/sentences [post]
+func (h *AdminHandler) CreateSentence(c *gin.Context) {
+	var req service.CreateSentenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	sentence, err := h.sentenceService.CreateSentence(c.Request.Context(), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, dto.NewSentence(sentence))
+}
+
+// UpdateSentence 整体更新句子
+// @Summary 整体更新句子
+// @Tags 管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "句子ID"
+// @Param request body service.UpdateSentenceRequest true "句子信息"
+// @Success 200 {object} response.Response
+// @Router /api/v1/admin/sentences/{id} [put]
+func (h *AdminHandler) UpdateSentence(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid sentence ID")
+	if !ok {
+		return
+	}
+
+	var req service.UpdateSentenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	sentence, err := h.sentenceService.UpdateSentence(c.Request.Context(), id, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, dto.NewSentence(sentence))
+}
+
+// PatchSentence 部分更新句子
+// @Summary 部分更新句子
+// @Tags 管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "句子ID"
+// @Param request body service.PatchSentenceRequest true "需要修改的字段"
+// @Success 200 {object} response.Response
+// @Router /api/v1/admin/sentences/{id} [patch]
+func (h *AdminHandler) PatchSentence(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid sentence ID")
+	if !ok {
+		return
+	}
+
+	var req service.PatchSentenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	sentence, err := h.sentenceService.PatchSentence(c.Request.Context(), id, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, dto.NewSentence(sentence))
+}
+
+// DeleteSentence 删除句子
+// @Summary 删除句子
+// @Tags 管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "句子ID"
+// @Success 200 {object} response.Response
+// @Router /api/v1/admin/sentences/{id} [delete]
+func (h *AdminHandler) DeleteSentence(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid sentence ID")
+	if !ok {
+		return
+	}
+
+	if err := h.sentenceService.DeleteSentence(c.Request.Context(), id); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "Sentence deleted successfully", nil)
+}
+
+// ListSentenceTranslations 获取句子的全部翻译
+// @Summary 获取句子的全部翻译
+// @Tags 管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "句子ID"
+// @Success 200 {object} response.Response
+// @Router /api/v1/admin/sentences/{id}/translations [get]
+func (h *AdminHandler) ListSentenceTranslations(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid sentence ID")
+	if !ok {
+		return
+	}
+
+	translations, err := h.translationService.ListSentenceTranslations(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, dto.NewSentenceTranslations(translations))
+}
+
+// SetSentenceTranslation 创建或覆盖句子的某个语言翻译
+// @Summary 设置句子翻译
+// @Tags 管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "句子ID"
+// @Param locale path string true "语言代码，如 ko、ja、en"
+// @Param request body service.SetSentenceTranslationRequest true "翻译内容"
+// @Success 200 {object} response.Response
+// @Router /api/v1/admin/sentences/{id}/translations/{locale} [put]
+func (h *AdminHandler) SetSentenceTranslation(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid sentence ID")
+	if !ok {
+		return
+	}
+
+	var req service.SetSentenceTranslationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	translation, err := h.translationService.SetSentenceTranslation(c.Request.Context(), id, c.Param("locale"), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, dto.NewSentenceTranslation(translation))
+}
+
+// DeleteSentenceTranslation 删除句子的某个语言翻译
+// @Summary 删除句子翻译
+// @Tags 管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "句子ID"
+// @Param locale path string true "语言代码"
+// @Success 200 {object} response.Response
+// @Router /api/v1/admin/sentences/{id}/translations/{locale} [delete]
+func (h *AdminHandler) DeleteSentenceTranslation(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid sentence ID")
+	if !ok {
+		return
+	}
+
+	if err := h.translationService.DeleteSentenceTranslation(c.Request.Context(), id, c.Param("locale")); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "Translation deleted successfully", nil)
+}
+
+// ListSceneTranslations 获取场景的全部多语言名称
+// @Summary 获取场景的全部多语言名称
+// @Tags 管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "场景ID"
+// @Success 200 {object} response.Response
+// @Router /api/v1/admin/scenes/{id}/translations [get]
+func (h *AdminHandler) ListSceneTranslations(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid scene ID")
+	if !ok {
+		return
+	}
+
+	translations, err := h.translationService.ListSceneTranslations(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, dto.NewSceneTranslations(translations))
+}
+
+// SetSceneTranslation 创建或覆盖场景的某个语言名称和描述
+// @Summary 设置场景多语言名称
+// @Tags 管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "场景ID"
+// @Param locale path string true "语言代码，如 ko、ja、en"
+// @Param request body service.SetSceneTranslationRequest true "名称和描述"
+// @Success 200 {object} response.Response
+// @Router /api/v1/admin/scenes/{id}/translations/{locale} [put]
+func (h *AdminHandler) SetSceneTranslation(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid scene ID")
+	if !ok {
+		return
+	}
+
+	var req service.SetSceneTranslationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	translation, err := h.translationService.SetSceneTranslation(c.Request.Context(), id, c.Param("locale"), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, dto.NewSceneTranslation(translation))
+}
+
+// DeleteSceneTranslation 删除场景的某个语言名称和描述
+// @Summary 删除场景多语言名称
+// @Tags 管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "场景ID"
+// @Param locale path string true "语言代码"
+// @Success 200 {object} response.Response
+// @Router /api/v1/admin/scenes/{id}/translations/{locale} [delete]
+func (h *AdminHandler) DeleteSceneTranslation(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid scene ID")
+	if !ok {
+		return
+	}
+
+	if err := h.translationService.DeleteSceneTranslation(c.Request.Context(), id, c.Param("locale")); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "Translation deleted successfully", nil)
+}
+
+// ImportContent 批量导入句子
+// @Summary 批量导入句子
+// @Description 支持 csv、jsonl、anki（TSV）和 apkg；可用 multipart 字段 file 上传，也可直接作为请求体发送。任一行不合法时整批不写入并返回 400，data 中为导入报告
+// @Tags 管理
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file false "导入文件"
+// @Param format query string false "格式，默认按文件扩展名推断"
+// @Param dry_run query bool false "只校验不写入"
+// @Param default_scene query string false "行内未指定场景时使用的场景名"
+// @Success 200 {object} response.Response{data=dto.ImportReport}
+// @Failure 400 {object} response.Response{data=dto.ImportReport}
+// @Router /api/v1/admin/import [post]
+func (h *AdminHandler) ImportContent(c *gin.Context) {
+	data, filename, err := readImportBody(c)
+	if err != nil {
+		response.BadRequest(c, "Invalid import file: "+err.Error())
+		return
+	}
+
+	dryRun, _ := strconv.ParseBool(c.Query("dry_run"))
+	report, err := h.contentService.Import(c.Request.Context(), data, &service.ImportOptions{
+		Format:       c.Query("format"),
+		Filename:     filename,
+		DryRun:       dryRun,
+		DefaultScene: c.Query("default_scene"),
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, dto.NewImportReport(report))
+}
+
+// ExportContent 批量导出句子
+// @Summary 批量导出句子
+// @Tags 管理
+// @Produce octet-stream
+// @Security BearerAuth
+// @Param format query string false "格式：csv、jsonl、anki、apkg，默认 csv"
+// @Param scene_id query int false "只导出指定场景"
+// @Success 200 {file} file
+// @Router /api/v1/admin/export [get]
+func (h *AdminHandler) ExportContent(c *gin.Context) {
+	format := c.DefaultQuery("format", content.FormatCSV)
+	sceneID, err := parseUintQuery(c, "scene_id")
+	if err != nil {
+		response.BadRequest(c, "Invalid scene_id")
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := h.contentService.Export(c.Request.Context(), &buf, format, sceneID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	filename := "sentences-" + time.Now().Format("20060102") + content.Extension(format)
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Data(http.StatusOK, content.ContentType(format), buf.Bytes())
+}
+
+// readImportBody 读取 multipart 字段 file，没有时读取整个请求体
+func readImportBody(c *gin.Context) ([]byte, string, error) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+
+	if file, err := c.FormFile("file"); err == nil {
+		f, err := file.Open()
+		if err != nil {
+			return nil, "", err
+		}
+		defer f.Close()
+		data, err := io.ReadAll(f)
+		return data, file.Filename, err
+	}
+
+	data, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(data) == 0 {
+		return nil, "", errors.New("empty request body")
+	}
+	return data, "", nil
+}
+
+// parseIDParam 解析路径中的ID参数，失败时直接写入 400 响应
+func parseIDParam(c *gin.Context, key, message string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(key), 10, 32)
+	if err != nil || id == 0 {
+		response.BadRequest(c, message)
+		return 0, false
+	}
+	return uint(id), true
+}
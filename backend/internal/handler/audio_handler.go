@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"voicewriter/internal/service"
+	"voicewriter/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AudioHandler 音频处理器
+type AudioHandler struct {
+	audioService *service.AudioService
+}
+
+// NewAudioHandler 创建音频处理器实例
+func NewAudioHandler(audioService *service.AudioService) *AudioHandler {
+	return &AudioHandler{
+		audioService: audioService,
+	}
+}
+
+// GetAudio 获取句子音频
+// @Summary 获取句子音频
+// @Description 返回句子的音频文件，首次请求时合成并缓存；支持 ETag 和 Range 请求
+// @Tags 音频
+// @Produce audio/wav
+// @Param id path int true "句子ID"
+// @Success 200 {file} binary
+// @Success 206 {file} binary
+// @Success 304
+// @Router /api/v1/audio/{id} [get]
+func (h *AudioHandler) GetAudio(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid sentence ID")
+		return
+	}
+
+	audio, err := h.audioService.GetSentenceAudio(c.Request.Context(), uint(id))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	defer audio.File.Close()
+
+	c.Header("Content-Type", audio.ContentType)
+	c.Header("ETag", audio.ETag)
+	c.Header("Cache-Control", "public, max-age=86400")
+	// ServeContent 负责 Range、If-None-Match 和 If-Modified-Since
+	http.ServeContent(c.Writer, c.Request, audio.Name, audio.ModTime, audio.File)
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"voicewriter/internal/config"
+	"voicewriter/internal/middleware"
+	"voicewriter/internal/model"
+	"voicewriter/internal/repository"
+	"voicewriter/internal/service"
+	"voicewriter/internal/tts"
+	"voicewriter/internal/worker"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newAudioRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	scene := &model.Scene{Name: "日常生活"}
+	if err := repository.NewMemorySceneRepository(store).Create(ctx, scene); err != nil {
+		t.Fatal(err)
+	}
+	sentences := repository.NewMemorySentenceRepository(store)
+	if err := sentences.Create(ctx, &model.Sentence{SceneID: scene.ID, Content: "Hello, how are you?"}); err != nil {
+		t.Fatal(err)
+	}
+	cache, err := tts.NewFileCache(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	workers := worker.NewGroup()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		workers.Shutdown(ctx)
+	})
+
+	audioService := service.NewAudioService(sentences, tts.NewToneSynthesizer(), cache, workers, config.TTSConfig{Language: "en", Speed: 1})
+	r := gin.New()
+	r.Use(middleware.ErrorHandler())
+	r.GET("/audio/:id", NewAudioHandler(audioService).GetAudio)
+	return r
+}
+
+func serveAudio(r *gin.Engine, path string, headers ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestGetAudio(t *testing.T) {
+	r := newAudioRouter(t)
+
+	w := serveAudio(r, "/audio/1")
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "audio/wav" {
+		t.Fatalf("audio = %d %s", w.Code, w.Header().Get("Content-Type"))
+	}
+	full := w.Body.Bytes()
+	if string(full[:4]) != "RIFF" || w.Header().Get("Accept-Ranges") != "bytes" {
+		t.Errorf("audio body starts %q, Accept-Ranges %q", full[:4], w.Header().Get("Accept-Ranges"))
+	}
+	etag := w.Header().Get("ETag")
+	if etag == "" || w.Header().Get("Cache-Control") == "" {
+		t.Fatalf("missing caching headers: %v", w.Header())
+	}
+
+	// 第二次请求命中缓存，内容和 ETag 不变
+	w = serveAudio(r, "/audio/1")
+	if w.Code != http.StatusOK || w.Header().Get("ETag") != etag || w.Body.Len() != len(full) {
+		t.Errorf("cached audio = %d, etag %s, %d bytes", w.Code, w.Header().Get("ETag"), w.Body.Len())
+	}
+
+	if w := serveAudio(r, "/audio/1", "If-None-Match", etag); w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Errorf("matching If-None-Match = %d (%d bytes), want 304", w.Code, w.Body.Len())
+	}
+	if w := serveAudio(r, "/audio/1", "If-None-Match", `"stale"`); w.Code != http.StatusOK {
+		t.Errorf("stale If-None-Match = %d, want 200", w.Code)
+	}
+
+	w = serveAudio(r, "/audio/1", "Range", "bytes=4-11")
+	if w.Code != http.StatusPartialContent || w.Body.String() != string(full[4:12]) {
+		t.Errorf("range = %d %q", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("Content-Range"); got != fmt.Sprintf("bytes 4-11/%d", len(full)) {
+		t.Errorf("Content-Range = %q", got)
+	}
+	if w := serveAudio(r, "/audio/1", "Range", "bytes=999999-"); w.Code != http.StatusRequestedRangeNotSatisfiable {
+		t.Errorf("unsatisfiable range = %d, want 416", w.Code)
+	}
+
+	if w := serveAudio(r, "/audio/2"); w.Code != http.StatusNotFound {
+		t.Errorf("missing sentence = %d, want 404", w.Code)
+	}
+	if w := serveAudio(r, "/audio/abc"); w.Code != http.StatusBadRequest {
+		t.Errorf("invalid id = %d, want 400", w.Code)
+	}
+}
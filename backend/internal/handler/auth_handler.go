@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"voicewriter/internal/dto"
+	"voicewriter/internal/middleware"
+	"voicewriter/internal/service"
+	"voicewriter/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuthHandler 用户认证处理器
+type AuthHandler struct {
+	authService *service.AuthService
+}
+
+// NewAuthHandler 创建用户认证处理器实例
+func NewAuthHandler(authService *service.AuthService) *AuthHandler {
+	return &AuthHandler{
+		authService: authService,
+	}
+}
+
+// Register 用户注册
+// @Summary 用户注册
+// @Description 创建账户并返回访问令牌和刷新令牌
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param request body service.RegisterRequest true "注册信息"
+// @Success 200 {object} response.Response
+// @Router /api/v1/auth/register [post]
+func (h *AuthHandler) Register(c *gin.Context) {
+	var req service.RegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	result, err := h.authService.Register(c.Request.Context(), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, dto.NewAuthResult(result))
+}
+
+// Login 用户登录
+// @Summary 用户登录
+// @Description 校验用户名密码，返回访问令牌和刷新令牌
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param request body service.LoginRequest true "登录信息"
+// @Success 200 {object} response.Response
+// @Router /api/v1/auth/login [post]
+func (h *AuthHandler) Login(c *gin.Context) {
+	var req service.LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	result, err := h.authService.Login(c.Request.Context(), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, dto.NewAuthResult(result))
+}
+
+// Refresh 刷新令牌
+// @Summary 刷新令牌
+// @Description 使用刷新令牌换取新的访问令牌和刷新令牌
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param request body service.RefreshRequest true "刷新令牌"
+// @Success 200 {object} response.Response
+// @Router /api/v1/auth/refresh [post]
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req service.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	result, err := h.authService.Refresh(c.Request.Context(), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, dto.NewAuthResult(result))
+}
+
+// Me 获取当前用户
+// @Summary 获取当前用户
+// @Description 返回访问令牌对应的用户信息
+// @Tags 认证
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response
+// @Router /api/v1/auth/me [get]
+func (h *AuthHandler) Me(c *gin.Context) {
+	identity, ok := middleware.CurrentUser(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	user, err := h.authService.GetUser(c.Request.Context(), identity.UID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, dto.NewUser(user))
+}
+
+// IssueAnonymous 签发匿名ID
+// @Summary 签发匿名ID
+// @Description 为未登录客户端签发匿名用户ID、认领密钥和匿名身份的令牌；数据记在匿名ID下，注册后凭密钥认领；密钥只返回这一次
+// @Tags 认证
+// @Produce json
+// @Success 200 {object} response.Response
+// @Router /api/v1/auth/anonymous [post]
+func (h *AuthHandler) IssueAnonymous(c *gin.Context) {
+	credentials, err := h.authService.IssueAnonymous(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, dto.NewAnonymousCredentials(credentials))
+}
+
+// ClaimAnonymous 认领匿名进度
+// @Summary 认领匿名进度
+// @Description 凭签发时的认领密钥，将匿名用户ID下的进度、作答记录和复习计划转入当前账户；每个匿名ID只能认领一次
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body service.ClaimRequest true "匿名用户ID和认领密钥"
+// @Success 200 {object} response.Response
+// @Router /api/v1/auth/claim [post]
+func (h *AuthHandler) ClaimAnonymous(c *gin.Context) {
+	identity, ok := middleware.CurrentUser(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	var req service.ClaimRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	result, err := h.authService.ClaimAnonymous(c.Request.Context(), identity.UID, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, dto.NewClaimResult(result))
+}
+
+// ClaimLegacyAnonymous 迁移旧匿名ID
+// @Summary 迁移旧匿名ID
+// @Description 将服务端签发匿名ID之前、客户端自行生成的匿名ID下的数据转入指定用户；每个ID只能迁移一次，服务端签发的ID需由持有者凭密钥认领
+// @Tags 管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body service.LegacyClaimRequest true "旧匿名ID和目标用户 uid"
+// @Success 200 {object} response.Response
+// @Router /api/v1/admin/anonymous/claim [post]
+func (h *AuthHandler) ClaimLegacyAnonymous(c *gin.Context) {
+	var req service.LegacyClaimRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	result, err := h.authService.ClaimLegacyAnonymous(c.Request.Context(), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, dto.NewClaimResult(result))
+}
@@ -1,30 +1,62 @@
 package handler
 
 import (
-	"net/http"
+	"errors"
+	"strconv"
+
+	"voicewriter/internal/i18n"
+	"voicewriter/internal/service"
+	"voicewriter/pkg/response"
 
 	"github.com/gin-gonic/gin"
 )
 
-// HealthCheck 健康检查
-func HealthCheck(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
-		"status":  "ok",
-		"message": "VoiceWriter API is running",
-	})
+// requestLocales 从 ?lang= 和 Accept-Language 解析候选语言，响应随 Accept-Language 变化
+func requestLocales(c *gin.Context) []string {
+	c.Header("Vary", "Accept-Language")
+	return i18n.Candidates(c.Query("lang"), c.GetHeader("Accept-Language"))
+}
+
+// parseListQuery 解析通用的分页排序参数：sort、limit、offset、cursor
+func parseListQuery(c *gin.Context) (service.ListQuery, error) {
+	query := service.ListQuery{
+		Sort:   c.Query("sort"),
+		Cursor: c.Query("cursor"),
+	}
+	var err error
+	if value := c.Query("limit"); value != "" {
+		if query.Limit, err = strconv.Atoi(value); err != nil {
+			return query, errors.New("invalid limit")
+		}
+	}
+	if value := c.Query("offset"); value != "" {
+		if query.Offset, err = strconv.Atoi(value); err != nil || query.Offset < 0 {
+			return query, errors.New("invalid offset")
+		}
+	}
+	return query, nil
 }
 
-// GetAudio 获取音频
-// TODO: 实现音频获取逻辑（集成TTS或返回预录音频）
-func GetAudio(c *gin.Context) {
-	id := c.Param("id")
+// parseBoolQuery 解析可选的布尔查询参数，未提供时返回 nil
+func parseBoolQuery(c *gin.Context, key string) (*bool, error) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
 
-	// 暂时返回占位符
-	c.JSON(http.StatusOK, gin.H{
-		"code": 0,
-		"data": gin.H{
-			"url": "/audio/" + id + ".mp3",
-		},
-		"message": "success",
-	})
+// toPage 将服务层分页信息转换为响应中的分页结构
+func toPage(info *service.PageInfo) *response.Page {
+	return &response.Page{
+		Total:      info.Total,
+		Limit:      info.Limit,
+		Offset:     info.Offset,
+		NextCursor: info.NextCursor,
+		HasMore:    info.HasMore,
+	}
 }
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"strconv"
+
+	"voicewriter/internal/dto"
+	"voicewriter/internal/middleware"
+	"voicewriter/internal/service"
+	"voicewriter/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GradingHandler 听写评分处理器
+type GradingHandler struct {
+	gradingService  *service.GradingService
+	progressService *service.ProgressService
+}
+
+// NewGradingHandler 创建听写评分处理器实例
+func NewGradingHandler(gradingService *service.GradingService, progressService *service.ProgressService) *GradingHandler {
+	return &GradingHandler{
+		gradingService:  gradingService,
+		progressService: progressService,
+	}
+}
+
+// CheckAnswer 检查听写答案
+// @Summary 检查听写答案
+// @Description 将用户输入与句子原文逐词比对，返回对齐结果、准确率和正确句子；已登录时同时记录进度和作答
+// @Tags 句子
+// @Accept json
+// @Produce json
+// @Param id path int true "句子ID"
+// @Param request body service.CheckAnswerRequest true "用户答案"
+// @Success 200 {object} response.Response
+// @Router /api/v1/sentences/{id}/check [post]
+func (h *GradingHandler) CheckAnswer(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid sentence ID")
+		return
+	}
+
+	var req service.CheckAnswerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	result, err := h.gradingService.CheckAnswer(c.Request.Context(), uint(id), req.Answer)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if identity, ok := middleware.CurrentUser(c); ok {
+		progress := &service.SaveProgressRequest{
+			SentenceID: result.SentenceID,
+			Completed:  result.Correct,
+			AttemptDetail: service.AttemptDetail{
+				SubmittedText: req.Answer,
+				DurationMs:    req.DurationMs,
+				AudioReplays:  req.AudioReplays,
+			},
+		}
+		if err := h.progressService.SaveProgress(c.Request.Context(), identity.UID, progress); err != nil {
+			c.Error(err)
+			return
+		}
+	}
+
+	response.Success(c, dto.NewCheckResult(result))
+}
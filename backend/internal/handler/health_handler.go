@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"voicewriter/internal/dto"
+	"voicewriter/internal/health"
+	"voicewriter/internal/logging"
+	apperrors "voicewriter/pkg/errors"
+	"voicewriter/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HealthHandler 存活和就绪探针处理器
+type HealthHandler struct {
+	checker *health.Checker
+}
+
+// NewHealthHandler 创建探针处理器实例
+func NewHealthHandler(checker *health.Checker) *HealthHandler {
+	return &HealthHandler{
+		checker: checker,
+	}
+}
+
+// Livez 存活探针
+// @Summary 存活探针
+// @Description 进程能处理请求即返回 200，不检查数据库等依赖，失败时应重启实例；/health 为兼容旧版的别名
+// @Tags 健康检查
+// @Produce json
+// @Success 200 {object} response.Response
+// @Router /livez [get]
+func (h *HealthHandler) Livez(c *gin.Context) {
+	response.Success(c, dto.NewLiveness(h.checker.Build()))
+}
+
+// Readyz 就绪探针
+// @Summary 就绪探针
+// @Description 检查数据库连接、迁移状态和音频缓存目录，任一依赖不可用时返回 503，失败时应停止向实例转发流量；响应只给出各组件的状态，错误等详情写入日志
+// @Tags 健康检查
+// @Produce json
+// @Success 200 {object} response.Response
+// @Failure 503 {object} response.Response
+// @Router /readyz [get]
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	report := h.checker.Run(c.Request.Context())
+	logger := logging.FromContext(c.Request.Context())
+	for name, component := range report.Components {
+		if component.Status != health.StatusUp {
+			logger.Warn("readiness check failed", "component", name, "error", component.Error,
+				"latency_ms", component.LatencyMs, "detail", component.Detail)
+		} else {
+			logger.Debug("readiness check passed", "component", name, "latency_ms", component.LatencyMs, "detail", component.Detail)
+		}
+	}
+
+	readiness := dto.NewReadiness(report)
+	if report.Status != health.StatusUp {
+		c.Error(apperrors.WithDetails(apperrors.KindUnavailable, "service not ready", readiness))
+		return
+	}
+	response.Success(c, readiness)
+}
@@ -1,7 +1,11 @@
 package handler
 
 import (
-	"voicewriter/internal/model"
+	"strconv"
+	"time"
+
+	"voicewriter/internal/dto"
+	"voicewriter/internal/middleware"
 	"voicewriter/internal/service"
 	"voicewriter/pkg/response"
 
@@ -22,49 +26,146 @@ func NewProgressHandler(progressService *service.ProgressService) *ProgressHandl
 
 // GetUserProgress 获取用户进度
 // @Summary 获取用户进度
-// @Description 根据用户ID获取学习进度
+// @Description 获取当前登录用户的学习进度
 // @Tags 进度
 // @Accept json
 // @Produce json
-// @Param userId path string true "用户ID"
+// @Security BearerAuth
 // @Success 200 {object} response.Response
-// @Router /api/v1/progress/{userId} [get]
+// @Router /api/v1/progress [get]
 func (h *ProgressHandler) GetUserProgress(c *gin.Context) {
-	userID := c.Param("userId")
-	if userID == "" {
-		response.BadRequest(c, "User ID is required")
+	identity, ok := middleware.CurrentUser(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
 		return
 	}
 
-	progress, err := h.progressService.GetUserProgress(c.Request.Context(), userID)
+	progress, err := h.progressService.GetUserProgress(c.Request.Context(), identity.UID)
 	if err != nil {
-		response.InternalServerError(c, "Failed to get user progress")
+		c.Error(err)
 		return
 	}
 
-	response.Success(c, progress)
+	response.Success(c, dto.NewProgressList(progress))
 }
 
 // SaveUserProgress 保存用户进度
 // @Summary 保存用户进度
-// @Description 保存或更新用户学习进度
+// @Description 保存或更新当前登录用户的学习进度，并记录本次作答
 // @Tags 进度
 // @Accept json
 // @Produce json
-// @Param progress body model.UserProgress true "进度信息"
+// @Security BearerAuth
+// @Param progress body service.SaveProgressRequest true "进度信息"
 // @Success 200 {object} response.Response
 // @Router /api/v1/progress [post]
 func (h *ProgressHandler) SaveUserProgress(c *gin.Context) {
-	var progress model.UserProgress
-	if err := c.ShouldBindJSON(&progress); err != nil {
+	identity, ok := middleware.CurrentUser(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	var req service.SaveProgressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		response.BadRequest(c, "Invalid request body")
 		return
 	}
 
-	if err := h.progressService.SaveProgress(c.Request.Context(), &progress); err != nil {
-		response.InternalServerError(c, "Failed to save progress")
+	// 用户身份只认令牌
+	if err := h.progressService.SaveProgress(c.Request.Context(), identity.UID, &req); err != nil {
+		c.Error(err)
 		return
 	}
 
 	response.SuccessWithMessage(c, "Progress saved successfully", nil)
 }
+
+// GetUserAttempts 获取用户作答记录
+// @Summary 获取用户作答记录
+// @Description 按时间倒序返回当前登录用户每次听写的作答记录，可按句子、场景和日期范围过滤
+// @Tags 进度
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param sentence_id query int false "句子ID"
+// @Param scene_id query int false "场景ID"
+// @Param from query string false "开始日期（YYYY-MM-DD 或 RFC3339）"
+// @Param to query string false "结束日期（YYYY-MM-DD 或 RFC3339，按日期时包含当天）"
+// @Param limit query int false "返回条数，默认100，最大500"
+// @Success 200 {object} response.Response
+// @Router /api/v1/progress/attempts [get]
+func (h *ProgressHandler) GetUserAttempts(c *gin.Context) {
+	identity, ok := middleware.CurrentUser(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	query := service.AttemptQuery{UserID: identity.UID}
+
+	var err error
+	if query.SentenceID, err = parseUintQuery(c, "sentence_id"); err != nil {
+		response.BadRequest(c, "Invalid sentence ID")
+		return
+	}
+	if query.SceneID, err = parseUintQuery(c, "scene_id"); err != nil {
+		response.BadRequest(c, "Invalid scene ID")
+		return
+	}
+	if query.From, err = parseTimeQuery(c, "from", false); err != nil {
+		response.BadRequest(c, "Invalid from date")
+		return
+	}
+	if query.To, err = parseTimeQuery(c, "to", true); err != nil {
+		response.BadRequest(c, "Invalid to date")
+		return
+	}
+	if limit := c.Query("limit"); limit != "" {
+		if query.Limit, err = strconv.Atoi(limit); err != nil {
+			response.BadRequest(c, "Invalid limit")
+			return
+		}
+	}
+
+	attempts, err := h.progressService.ListAttempts(c.Request.Context(), &query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, dto.NewAttempts(attempts))
+}
+
+// parseUintQuery 解析可选的无符号整数查询参数，缺省时返回 0
+func parseUintQuery(c *gin.Context, key string) (uint, error) {
+	value := c.Query(key)
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	return uint(n), nil
+}
+
+// parseTimeQuery 解析可选的时间查询参数，支持 RFC3339 和 YYYY-MM-DD
+// endOfDay 为 true 时，纯日期解析为次日零点，使区间包含当天
+func parseTimeQuery(c *gin.Context, key string, endOfDay bool) (time.Time, error) {
+	value := c.Query(key)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation(time.DateOnly, value, time.Local)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"strconv"
+
+	"voicewriter/internal/dto"
+	"voicewriter/internal/middleware"
+	"voicewriter/internal/service"
+	"voicewriter/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReviewHandler 复习队列处理器
+type ReviewHandler struct {
+	reviewService *service.ReviewService
+}
+
+// NewReviewHandler 创建复习队列处理器实例
+func NewReviewHandler(reviewService *service.ReviewService) *ReviewHandler {
+	return &ReviewHandler{
+		reviewService: reviewService,
+	}
+}
+
+// GetDueReviews 获取到期复习句子
+// @Summary 获取到期复习句子
+// @Description 按间隔重复算法返回当前登录用户到期的句子，跨所有场景按到期时间排序
+// @Tags 复习
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "返回条数，默认20，最大100"
+// @Success 200 {object} response.Response
+// @Router /api/v1/review/due [get]
+func (h *ReviewHandler) GetDueReviews(c *gin.Context) {
+	identity, ok := middleware.CurrentUser(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	var limit int
+	if limitStr := c.Query("limit"); limitStr != "" {
+		n, err := strconv.Atoi(limitStr)
+		if err != nil {
+			response.BadRequest(c, "Invalid limit")
+			return
+		}
+		limit = n
+	}
+
+	reviews, err := h.reviewService.GetDueReviews(c.Request.Context(), identity.UID, limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, dto.NewReviews(reviews))
+}
@@ -1,9 +1,10 @@
 package handler
 
 import (
-	"net/http"
 	"strconv"
 
+	"voicewriter/internal/dto"
+	"voicewriter/internal/model"
 	"voicewriter/internal/service"
 	"voicewriter/pkg/response"
 
@@ -12,32 +13,54 @@ import (
 
 // SceneHandler 场景处理器
 type SceneHandler struct {
-	sceneService *service.SceneService
+	sceneService       *service.SceneService
+	translationService *service.TranslationService
 }
 
 // NewSceneHandler 创建场景处理器实例
-func NewSceneHandler(sceneService *service.SceneService) *SceneHandler {
+func NewSceneHandler(sceneService *service.SceneService, translationService *service.TranslationService) *SceneHandler {
 	return &SceneHandler{
-		sceneService: sceneService,
+		sceneService:       sceneService,
+		translationService: translationService,
 	}
 }
 
-// GetScenes 获取所有场景
-// @Summary 获取所有场景
-// @Description 获取所有场景列表
+// GetScenes 分页查询场景
+// @Summary 分页查询场景
+// @Description 按名称或描述搜索，支持排序、offset 分页和游标分页；名称和描述按 lang 参数或 Accept-Language 本地化
 // @Tags 场景
 // @Accept json
 // @Produce json
-// @Success 200 {object} response.Response
+// @Param q query string false "名称或描述包含的文本"
+// @Param sort query string false "排序字段：id, name, created_at，前缀 - 表示降序"
+// @Param limit query int false "每页条数，默认 20，最大 100"
+// @Param offset query int false "偏移量"
+// @Param cursor query string false "上一页返回的 next_cursor"
+// @Param lang query string false "界面语言，优先于 Accept-Language"
+// @Success 200 {object} response.PageResponse
 // @Router /api/v1/scenes [get]
 func (h *SceneHandler) GetScenes(c *gin.Context) {
-	scenes, err := h.sceneService.GetAllScenes(c.Request.Context())
+	listQuery, err := parseListQuery(c)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	scenes, page, err := h.sceneService.ListScenes(c.Request.Context(), &service.SceneQuery{
+		ListQuery: listQuery,
+		Query:     c.Query("q"),
+	})
 	if err != nil {
-		response.InternalServerError(c, "Failed to get scenes")
+		c.Error(err)
 		return
 	}
 
-	response.Success(c, scenes)
+	if err := h.translationService.LocalizeScenes(c.Request.Context(), scenes, requestLocales(c)); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.SuccessWithPage(c, dto.NewScenes(scenes), toPage(page))
 }
 
 // GetSceneByID 根据ID获取场景
@@ -47,6 +70,7 @@ func (h *SceneHandler) GetScenes(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "场景ID"
+// @Param lang query string false "界面语言，优先于 Accept-Language"
 // @Success 200 {object} response.Response
 // @Router /api/v1/scenes/{id} [get]
 func (h *SceneHandler) GetSceneByID(c *gin.Context) {
@@ -59,9 +83,14 @@ func (h *SceneHandler) GetSceneByID(c *gin.Context) {
 
 	scene, err := h.sceneService.GetSceneByID(c.Request.Context(), uint(id))
 	if err != nil {
-		response.NotFound(c, "Scene not found")
+		c.Error(err)
+		return
+	}
+
+	if err := h.translationService.LocalizeScenes(c.Request.Context(), []*model.Scene{scene}, requestLocales(c)); err != nil {
+		c.Error(err)
 		return
 	}
 
-	response.Success(c, scene)
+	response.Success(c, dto.NewScene(scene))
 }
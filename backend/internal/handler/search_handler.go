@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"strconv"
+
+	"voicewriter/internal/dto"
+	"voicewriter/internal/service"
+	"voicewriter/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SearchHandler 搜索处理器
+type SearchHandler struct {
+	searchService      *service.SearchService
+	translationService *service.TranslationService
+}
+
+// NewSearchHandler 创建搜索处理器实例
+func NewSearchHandler(searchService *service.SearchService, translationService *service.TranslationService) *SearchHandler {
+	return &SearchHandler{
+		searchService:      searchService,
+		translationService: translationService,
+	}
+}
+
+// Search 全文搜索句子
+// @Summary 全文搜索句子
+// @Description 搜索句子原文和翻译，中日韩文本按二元组匹配，结果按相关度排序；highlights 中的文本已转义，命中部分用 <em> 标记
+// @Tags 搜索
+// @Accept json
+// @Produce json
+// @Param q query string true "搜索文本"
+// @Param scene_id query int false "场景ID"
+// @Param language query string false "目标语言，如 ja"
+// @Param limit query int false "每页条数，默认 20，最大 100"
+// @Param offset query int false "偏移量"
+// @Param lang query string false "翻译语言，优先于 Accept-Language"
+// @Success 200 {object} response.PageResponse
+// @Router /api/v1/search [get]
+func (h *SearchHandler) Search(c *gin.Context) {
+	sceneID, err := parseUintQuery(c, "scene_id")
+	if err != nil {
+		response.BadRequest(c, "Invalid scene_id")
+		return
+	}
+	req := &service.SearchRequest{
+		Query:    c.Query("q"),
+		SceneID:  sceneID,
+		Language: c.Query("language"),
+	}
+	if value := c.Query("limit"); value != "" {
+		if req.Limit, err = strconv.Atoi(value); err != nil {
+			response.BadRequest(c, "Invalid limit")
+			return
+		}
+	}
+	if value := c.Query("offset"); value != "" {
+		if req.Offset, err = strconv.Atoi(value); err != nil || req.Offset < 0 {
+			response.BadRequest(c, "Invalid offset")
+			return
+		}
+	}
+
+	results, page, err := h.searchService.Search(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.translationService.LocalizeSentences(c.Request.Context(), results.Sentences(), requestLocales(c)); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.SuccessWithPage(c, dto.NewSearchResults(results), toPage(page))
+}
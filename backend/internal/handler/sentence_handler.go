@@ -3,6 +3,8 @@ package handler
 import (
 	"strconv"
 
+	"voicewriter/internal/dto"
+	"voicewriter/internal/model"
 	"voicewriter/internal/service"
 	"voicewriter/pkg/response"
 
@@ -11,32 +13,72 @@ import (
 
 // SentenceHandler 句子处理器
 type SentenceHandler struct {
-	sentenceService *service.SentenceService
+	sentenceService    *service.SentenceService
+	translationService *service.TranslationService
 }
 
 // NewSentenceHandler 创建句子处理器实例
-func NewSentenceHandler(sentenceService *service.SentenceService) *SentenceHandler {
+func NewSentenceHandler(sentenceService *service.SentenceService, translationService *service.TranslationService) *SentenceHandler {
 	return &SentenceHandler{
-		sentenceService: sentenceService,
+		sentenceService:    sentenceService,
+		translationService: translationService,
 	}
 }
 
-// GetSentences 获取所有句子
-// @Summary 获取所有句子
-// @Description 获取所有句子列表
+// GetSentences 分页查询句子
+// @Summary 分页查询句子
+// @Description 按场景、难度、目标语言、文本和是否有音频过滤，支持排序、offset 分页和游标分页；翻译按 lang 参数或 Accept-Language 本地化
 // @Tags 句子
 // @Accept json
 // @Produce json
-// @Success 200 {object} response.Response
+// @Param scene_id query int false "场景ID"
+// @Param difficulty query string false "难度：easy, medium, hard"
+// @Param language query string false "目标语言，如 ja"
+// @Param q query string false "原文或翻译包含的文本"
+// @Param has_audio query bool false "是否设置了音频URL"
+// @Param sort query string false "排序字段：id, created_at, updated_at，前缀 - 表示降序"
+// @Param limit query int false "每页条数，默认 20，最大 100"
+// @Param offset query int false "偏移量"
+// @Param cursor query string false "上一页返回的 next_cursor"
+// @Param lang query string false "翻译语言，优先于 Accept-Language"
+// @Success 200 {object} response.PageResponse
 // @Router /api/v1/sentences [get]
 func (h *SentenceHandler) GetSentences(c *gin.Context) {
-	sentences, err := h.sentenceService.GetAllSentences(c.Request.Context())
+	listQuery, err := parseListQuery(c)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	sceneID, err := parseUintQuery(c, "scene_id")
+	if err != nil {
+		response.BadRequest(c, "Invalid scene_id")
+		return
+	}
+	hasAudio, err := parseBoolQuery(c, "has_audio")
+	if err != nil {
+		response.BadRequest(c, "Invalid has_audio")
+		return
+	}
+
+	sentences, page, err := h.sentenceService.ListSentences(c.Request.Context(), &service.SentenceQuery{
+		ListQuery:  listQuery,
+		SceneID:    sceneID,
+		Difficulty: c.Query("difficulty"),
+		Language:   c.Query("language"),
+		Query:      c.Query("q"),
+		HasAudio:   hasAudio,
+	})
 	if err != nil {
-		response.InternalServerError(c, "Failed to get sentences")
+		c.Error(err)
 		return
 	}
 
-	response.Success(c, sentences)
+	if err := h.translationService.LocalizeSentences(c.Request.Context(), sentences, requestLocales(c)); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.SuccessWithPage(c, dto.NewSentences(sentences), toPage(page))
 }
 
 // GetSentenceByID 根据ID获取句子
@@ -46,6 +88,7 @@ func (h *SentenceHandler) GetSentences(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "句子ID"
+// @Param lang query string false "翻译语言，优先于 Accept-Language"
 // @Success 200 {object} response.Response
 // @Router /api/v1/sentences/{id} [get]
 func (h *SentenceHandler) GetSentenceByID(c *gin.Context) {
@@ -58,11 +101,16 @@ func (h *SentenceHandler) GetSentenceByID(c *gin.Context) {
 
 	sentence, err := h.sentenceService.GetSentenceByID(c.Request.Context(), uint(id))
 	if err != nil {
-		response.NotFound(c, "Sentence not found")
+		c.Error(err)
+		return
+	}
+
+	if err := h.translationService.LocalizeSentences(c.Request.Context(), []*model.Sentence{sentence}, requestLocales(c)); err != nil {
+		c.Error(err)
 		return
 	}
 
-	response.Success(c, sentence)
+	response.Success(c, dto.NewSentence(sentence))
 }
 
 // GetSentencesByScene 根据场景ID获取句子列表
@@ -72,6 +120,7 @@ func (h *SentenceHandler) GetSentenceByID(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param sceneId path int true "场景ID"
+// @Param lang query string false "翻译语言，优先于 Accept-Language"
 // @Success 200 {object} response.Response
 // @Router /api/v1/sentences/scene/{sceneId} [get]
 func (h *SentenceHandler) GetSentencesByScene(c *gin.Context) {
@@ -84,9 +133,14 @@ func (h *SentenceHandler) GetSentencesByScene(c *gin.Context) {
 
 	sentences, err := h.sentenceService.GetSentencesBySceneID(c.Request.Context(), uint(sceneID))
 	if err != nil {
-		response.InternalServerError(c, "Failed to get sentences")
+		c.Error(err)
+		return
+	}
+
+	if err := h.translationService.LocalizeSentences(c.Request.Context(), sentences, requestLocales(c)); err != nil {
+		c.Error(err)
 		return
 	}
 
-	response.Success(c, sentences)
+	response.Success(c, dto.NewSentences(sentences))
 }
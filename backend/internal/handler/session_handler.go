@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"voicewriter/internal/dto"
+	"voicewriter/internal/middleware"
+	"voicewriter/internal/service"
+	"voicewriter/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SessionHandler 听写练习会话处理器
+type SessionHandler struct {
+	sessionService *service.SessionService
+}
+
+// NewSessionHandler 创建练习会话处理器实例
+func NewSessionHandler(sessionService *service.SessionService) *SessionHandler {
+	return &SessionHandler{
+		sessionService: sessionService,
+	}
+}
+
+// StartSession 开始练习会话
+// @Summary 开始练习会话
+// @Description 按场景或复习队列开始会话，由服务端选定并排列句子：场景会话中未完成的句子在前，复习会话按到期时间排列
+// @Tags 练习会话
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body service.StartSessionRequest true "会话来源和句子数"
+// @Success 200 {object} response.Response
+// @Router /api/v1/sessions [post]
+func (h *SessionHandler) StartSession(c *gin.Context) {
+	identity, ok := middleware.CurrentUser(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+	var req service.StartSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	detail, err := h.sessionService.StartSession(c.Request.Context(), identity.UID, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, dto.NewSessionDetail(detail))
+}
+
+// GetSessions 分页查询当前用户的练习会话
+// @Summary 分页查询练习会话
+// @Description 用于在其他设备上找到未结束的会话继续练习；默认最新的在前
+// @Tags 练习会话
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "状态：active, finished"
+// @Param sort query string false "排序字段：id, started_at，前缀 - 表示降序，默认 -id"
+// @Param limit query int false "每页条数，默认 20，最大 100"
+// @Param offset query int false "偏移量"
+// @Param cursor query string false "上一页返回的 next_cursor"
+// @Success 200 {object} response.PageResponse
+// @Router /api/v1/sessions [get]
+func (h *SessionHandler) GetSessions(c *gin.Context) {
+	identity, ok := middleware.CurrentUser(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+	listQuery, err := parseListQuery(c)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	query := &service.SessionQuery{ListQuery: listQuery, UserID: identity.UID, Status: c.Query("status")}
+	sessions, page, err := h.sessionService.ListSessions(c.Request.Context(), query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.SuccessWithPage(c, dto.NewSessions(sessions), toPage(page))
+}
+
+// GetSession 获取练习会话
+// @Summary 获取练习会话
+// @Description 返回会话的句子、各句作答结果、下一句的位置和当前小结，用于继续练习
+// @Tags 练习会话
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "会话ID"
+// @Success 200 {object} response.Response
+// @Router /api/v1/sessions/{id} [get]
+func (h *SessionHandler) GetSession(c *gin.Context) {
+	identity, ok := middleware.CurrentUser(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+	id, ok := parseIDParam(c, "id", "Invalid session ID")
+	if !ok {
+		return
+	}
+
+	detail, err := h.sessionService.GetSession(c.Request.Context(), identity.UID, id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, dto.NewSessionDetail(detail))
+}
+
+// SubmitAnswer 提交会话中一句的答案
+// @Summary 提交会话答案
+// @Description 评分并记录进度、作答记录、生词本和复习计划；同一句可以重复作答，会话保留最近一次的得分。会话已结束时返回 409
+// @Tags 练习会话
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "会话ID"
+// @Param body body service.SubmitAnswerRequest true "句子和答案"
+// @Success 200 {object} response.Response
+// @Router /api/v1/sessions/{id}/answers [post]
+func (h *SessionHandler) SubmitAnswer(c *gin.Context) {
+	identity, ok := middleware.CurrentUser(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+	id, ok := parseIDParam(c, "id", "Invalid session ID")
+	if !ok {
+		return
+	}
+	var req service.SubmitAnswerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	answer, err := h.sessionService.SubmitAnswer(c.Request.Context(), identity.UID, id, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, dto.NewSessionAnswer(answer))
+}
+
+// FinishSession 结束练习会话
+// @Summary 结束练习会话
+// @Description 保存并返回小结：准确率、用时、本次出错的词和需要再练的句子；重复调用返回已保存的小结
+// @Tags 练习会话
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "会话ID"
+// @Success 200 {object} response.Response
+// @Router /api/v1/sessions/{id}/finish [post]
+func (h *SessionHandler) FinishSession(c *gin.Context) {
+	identity, ok := middleware.CurrentUser(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+	id, ok := parseIDParam(c, "id", "Invalid session ID")
+	if !ok {
+		return
+	}
+
+	detail, err := h.sessionService.FinishSession(c.Request.Context(), identity.UID, id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, dto.NewSessionDetail(detail))
+}
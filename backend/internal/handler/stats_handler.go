@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"strconv"
+
+	"voicewriter/internal/dto"
+	"voicewriter/internal/middleware"
+	"voicewriter/internal/service"
+	"voicewriter/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StatsHandler 学习统计处理器
+type StatsHandler struct {
+	statsService *service.StatsService
+}
+
+// NewStatsHandler 创建学习统计处理器实例
+func NewStatsHandler(statsService *service.StatsService) *StatsHandler {
+	return &StatsHandler{
+		statsService: statsService,
+	}
+}
+
+// GetMyStats 获取当前用户的学习统计
+// @Summary 获取当前用户的学习统计
+// @Description 返回按场景和难度的完成率、每日准确率趋势、连续练习天数、练习总时长、易错词以及与全体用户中位数的对比
+// @Tags 统计
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param days query int false "准确率趋势覆盖的天数，默认30，最大365"
+// @Param tz query string false "划分日期使用的时区（IANA 名称），默认 UTC"
+// @Success 200 {object} response.Response
+// @Router /api/v1/stats [get]
+func (h *StatsHandler) GetMyStats(c *gin.Context) {
+	h.getStats(c, "")
+}
+
+// GetUserStats 获取指定用户的学习统计
+// @Summary 获取指定用户的学习统计
+// @Description 管理员查看任意用户的学习统计，内容同 /api/v1/stats
+// @Tags 管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param userId path string true "用户标识（uid）"
+// @Param days query int false "准确率趋势覆盖的天数，默认30，最大365"
+// @Param tz query string false "划分日期使用的时区（IANA 名称），默认 UTC"
+// @Success 200 {object} response.Response
+// @Router /api/v1/admin/stats/{userId} [get]
+func (h *StatsHandler) GetUserStats(c *gin.Context) {
+	h.getStats(c, c.Param("userId"))
+}
+
+// getStats 查询 userID 的学习统计，userID 为空时查询当前用户
+func (h *StatsHandler) getStats(c *gin.Context, userID string) {
+	identity, ok := middleware.CurrentUser(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	query := service.StatsQuery{Viewer: identity, UserID: userID, TimeZone: c.Query("tz")}
+	if days := c.Query("days"); days != "" {
+		var err error
+		if query.Days, err = strconv.Atoi(days); err != nil {
+			response.BadRequest(c, "Invalid days")
+			return
+		}
+	}
+
+	stats, err := h.statsService.GetLearnerStats(c.Request.Context(), &query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, dto.NewLearnerStats(stats))
+}
@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"time"
+
+	"voicewriter/internal/content"
+	"voicewriter/internal/dto"
+	"voicewriter/internal/middleware"
+	"voicewriter/internal/service"
+	"voicewriter/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// VocabularyHandler 生词本处理器
+type VocabularyHandler struct {
+	vocabularyService *service.VocabularyService
+}
+
+// NewVocabularyHandler 创建生词本处理器实例
+func NewVocabularyHandler(vocabularyService *service.VocabularyService) *VocabularyHandler {
+	return &VocabularyHandler{
+		vocabularyService: vocabularyService,
+	}
+}
+
+// GetVocabulary 分页查询当前用户的生词
+// @Summary 分页查询生词本
+// @Description 听写评分时漏写或拼错的词自动加入生词本；默认按出错次数降序，只列出未归档的词
+// @Tags 生词本
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param language query string false "词所属语言，如 en"
+// @Param q query string false "词包含的文本"
+// @Param starred query bool false "是否标星"
+// @Param archived query bool false "是否已归档，默认 false"
+// @Param sort query string false "排序字段：id, word, miss_count, last_seen_at，前缀 - 表示降序，默认 -miss_count"
+// @Param limit query int false "每页条数，默认 20，最大 100"
+// @Param offset query int false "偏移量"
+// @Param cursor query string false "上一页返回的 next_cursor"
+// @Success 200 {object} response.PageResponse
+// @Router /api/v1/vocabulary [get]
+func (h *VocabularyHandler) GetVocabulary(c *gin.Context) {
+	identity, ok := middleware.CurrentUser(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+	listQuery, err := parseListQuery(c)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	query, ok := parseVocabularyQuery(c, identity.UID)
+	if !ok {
+		return
+	}
+	query.ListQuery = listQuery
+
+	entries, page, err := h.vocabularyService.ListVocabulary(c.Request.Context(), query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.SuccessWithPage(c, dto.NewVocabulary(entries), toPage(page))
+}
+
+// UpdateVocabulary 标星或归档生词
+// @Summary 标星或归档生词
+// @Description 归档表示已掌握，不再出现在列表和练习中；再次写错时自动取消归档
+// @Tags 生词本
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "生词ID"
+// @Param body body service.UpdateVocabularyRequest true "标星和归档状态"
+// @Success 200 {object} response.Response
+// @Router /api/v1/vocabulary/{id} [patch]
+func (h *VocabularyHandler) UpdateVocabulary(c *gin.Context) {
+	identity, ok := middleware.CurrentUser(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+	id, ok := parseIDParam(c, "id", "Invalid vocabulary ID")
+	if !ok {
+		return
+	}
+	var req service.UpdateVocabularyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	entry, err := h.vocabularyService.UpdateEntry(c.Request.Context(), identity.UID, id, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, dto.NewVocabularyEntry(entry))
+}
+
+// ExportVocabulary 导出当前用户的生词本
+// @Summary 导出生词本
+// @Description 导出生词、出错次数和最近出错的句子；未指定 archived 时包含已归档的词
+// @Tags 生词本
+// @Produce octet-stream
+// @Security BearerAuth
+// @Param format query string false "格式：csv、jsonl，默认 csv"
+// @Param language query string false "词所属语言"
+// @Param starred query bool false "是否标星"
+// @Param archived query bool false "是否已归档"
+// @Success 200 {file} file
+// @Router /api/v1/vocabulary/export [get]
+func (h *VocabularyHandler) ExportVocabulary(c *gin.Context) {
+	identity, ok := middleware.CurrentUser(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+	query, ok := parseVocabularyQuery(c, identity.UID)
+	if !ok {
+		return
+	}
+	format := c.DefaultQuery("format", content.FormatCSV)
+
+	var buf bytes.Buffer
+	if err := h.vocabularyService.Export(c.Request.Context(), &buf, format, query); err != nil {
+		c.Error(err)
+		return
+	}
+
+	filename := "vocabulary-" + time.Now().Format("20060102") + content.Extension(format)
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Data(http.StatusOK, content.ContentType(format), buf.Bytes())
+}
+
+// GetPractice 获取生词练习句子
+// @Summary 获取生词练习句子
+// @Description 选出出错次数最多的未归档生词，返回包含这些词最多的句子，每个句子附带其中的生词
+// @Tags 生词本
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param language query string false "只练习该语言的生词"
+// @Param limit query int false "返回句子数，默认10，最大50"
+// @Success 200 {object} response.Response
+// @Router /api/v1/vocabulary/practice [get]
+func (h *VocabularyHandler) GetPractice(c *gin.Context) {
+	identity, ok := middleware.CurrentUser(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	query := service.PracticeQuery{UserID: identity.UID, Language: c.Query("language")}
+	if limit := c.Query("limit"); limit != "" {
+		var err error
+		if query.Limit, err = strconv.Atoi(limit); err != nil {
+			response.BadRequest(c, "Invalid limit")
+			return
+		}
+	}
+
+	set, err := h.vocabularyService.Practice(c.Request.Context(), &query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, dto.NewPracticeSet(set))
+}
+
+// parseVocabularyQuery 解析生词过滤条件，参数不合法时已写入错误响应
+func parseVocabularyQuery(c *gin.Context, userID string) (*service.VocabularyQuery, bool) {
+	starred, err := parseBoolQuery(c, "starred")
+	if err != nil {
+		response.BadRequest(c, "Invalid starred")
+		return nil, false
+	}
+	archived, err := parseBoolQuery(c, "archived")
+	if err != nil {
+		response.BadRequest(c, "Invalid archived")
+		return nil, false
+	}
+	return &service.VocabularyQuery{
+		UserID:   userID,
+		Language: c.Query("language"),
+		Query:    c.Query("q"),
+		Starred:  starred,
+		Archived: archived,
+	}, true
+}
@@ -0,0 +1,158 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// Version 构建版本，发布时通过 -ldflags "-X voicewriter/internal/health.Version=v1.2.3" 注入
+var Version = "dev"
+
+// 组件状态
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// CheckFunc 检查一个依赖，返回的 detail 随检查结果写入日志，如迁移版本；不会返回给客户端
+type CheckFunc func(ctx context.Context) (detail interface{}, err error)
+
+// Component 单个依赖的检查结果
+type Component struct {
+	Status    string      `json:"status"`
+	LatencyMs float64     `json:"latency_ms"`
+	Detail    interface{} `json:"detail,omitempty"`
+	Error     string      `json:"error,omitempty"`
+}
+
+// Report 就绪检查报告，任一组件不可用时 Status 为 down；包含错误等内部信息，对外只返回各组件的状态
+type Report struct {
+	Status     string               `json:"status"`
+	Components map[string]Component `json:"components"`
+	Build      BuildInfo            `json:"build"`
+}
+
+// BuildInfo 构建信息
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Revision  string `json:"revision,omitempty"`
+	BuildTime string `json:"build_time,omitempty"`
+	GoVersion string `json:"go_version"`
+}
+
+type check struct {
+	name string
+	fn   CheckFunc
+}
+
+// Checker 依次注册依赖检查，Run 时并发执行，每个检查都有超时
+type Checker struct {
+	timeout time.Duration
+	checks  []check
+	build   BuildInfo
+}
+
+// NewChecker 创建依赖检查器，timeout 为单个检查的超时
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout, build: readBuildInfo()}
+}
+
+// Add 注册依赖检查，name 为报告中的组件名
+func (c *Checker) Add(name string, fn CheckFunc) {
+	c.checks = append(c.checks, check{name: name, fn: fn})
+}
+
+// Build 返回构建信息
+func (c *Checker) Build() BuildInfo {
+	return c.build
+}
+
+// Run 执行全部检查并汇总结果
+func (c *Checker) Run(ctx context.Context) *Report {
+	report := &Report{
+		Status:     StatusUp,
+		Components: make(map[string]Component, len(c.checks)),
+		Build:      c.build,
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, chk := range c.checks {
+		wg.Add(1)
+		go func(chk check) {
+			defer wg.Done()
+			component := c.run(ctx, chk.fn)
+			mu.Lock()
+			defer mu.Unlock()
+			report.Components[chk.name] = component
+			if component.Status != StatusUp {
+				report.Status = StatusDown
+			}
+		}(chk)
+	}
+	wg.Wait()
+	return report
+}
+
+// run 在超时内执行单个检查；检查未响应上下文取消时按超时处理，不再等待
+func (c *Checker) run(ctx context.Context, fn CheckFunc) Component {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	type result struct {
+		detail interface{}
+		err    error
+	}
+	start := time.Now()
+	done := make(chan result, 1)
+	go func() {
+		detail, err := fn(ctx)
+		done <- result{detail, err}
+	}()
+
+	var res result
+	select {
+	case res = <-done:
+	case <-ctx.Done():
+		res.err = fmt.Errorf("check timed out after %s", c.timeout)
+	}
+
+	component := Component{
+		Status:    StatusUp,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+		Detail:    res.detail,
+	}
+	if res.err != nil {
+		component.Status = StatusDown
+		component.Error = res.err.Error()
+	}
+	return component
+}
+
+// readBuildInfo 从编译信息读取版本控制修订号和构建时间
+func readBuildInfo() BuildInfo {
+	build := BuildInfo{Version: Version, GoVersion: runtime.Version()}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return build
+	}
+	modified := false
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			build.Revision = setting.Value
+		case "vcs.time":
+			build.BuildTime = setting.Value
+		case "vcs.modified":
+			modified = setting.Value == "true"
+		}
+	}
+	if modified && build.Revision != "" {
+		build.Revision += "-dirty"
+	}
+	return build
+}
@@ -0,0 +1,47 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCheckerRun(t *testing.T) {
+	checker := NewChecker(50 * time.Millisecond)
+	checker.Add("ok", func(ctx context.Context) (interface{}, error) {
+		return "fine", nil
+	})
+	checker.Add("failing", func(ctx context.Context) (interface{}, error) {
+		return nil, errors.New("connection refused")
+	})
+	// 不响应取消的检查按超时处理
+	block := make(chan struct{})
+	defer close(block)
+	checker.Add("hanging", func(ctx context.Context) (interface{}, error) {
+		<-block
+		return nil, nil
+	})
+
+	start := time.Now()
+	report := checker.Run(context.Background())
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Run took %s, want it bounded by the check timeout", elapsed)
+	}
+
+	if report.Status != StatusDown {
+		t.Errorf("report status = %q, want %q", report.Status, StatusDown)
+	}
+	if c := report.Components["ok"]; c.Status != StatusUp || c.Detail != "fine" {
+		t.Errorf("ok component = %+v", c)
+	}
+	if c := report.Components["failing"]; c.Status != StatusDown || c.Error != "connection refused" {
+		t.Errorf("failing component = %+v", c)
+	}
+	if c := report.Components["hanging"]; c.Status != StatusDown || c.Error == "" {
+		t.Errorf("hanging component = %+v", c)
+	}
+	if report.Build.Version == "" || report.Build.GoVersion == "" {
+		t.Errorf("build info = %+v", report.Build)
+	}
+}
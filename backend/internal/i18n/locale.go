@@ -0,0 +1,68 @@
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+const (
+	// DefaultLocale 句子 Translation、场景 Name/Description 字段所用的语言
+	DefaultLocale = "zh"
+	// DefaultTargetLanguage 句子未指定语言时的默认目标语言
+	DefaultTargetLanguage = "en"
+	// maxCandidates 从 Accept-Language 中最多取用的语言数，防止超长请求头
+	maxCandidates = 8
+)
+
+// ErrInvalidLocale 语言代码为空或不是合法的 BCP 47 标签
+var ErrInvalidLocale = errors.New("invalid locale")
+
+// Normalize 将语言代码规范化为 BCP 47 形式，如 "ZH_tw" -> "zh-TW"
+func Normalize(locale string) (string, error) {
+	locale = strings.ReplaceAll(strings.TrimSpace(locale), "_", "-")
+	if locale == "" {
+		return "", fmt.Errorf("%w: locale is required", ErrInvalidLocale)
+	}
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return "", fmt.Errorf("%w %q", ErrInvalidLocale, locale)
+	}
+	return tag.String(), nil
+}
+
+// Candidates 按优先级返回候选语言：先 ?lang= 指定的语言，再 Accept-Language 中按权重排序的语言
+// 带地区的语言后追加其基础语言（zh-TW 之后是 zh），无法解析的条目忽略
+func Candidates(lang, acceptLanguage string) []string {
+	var tags []language.Tag
+	if lang != "" {
+		if tag, err := language.Parse(strings.ReplaceAll(lang, "_", "-")); err == nil {
+			tags = append(tags, tag)
+		}
+	}
+	if acceptLanguage != "" {
+		// 解析出错时仍会返回已成功解析的部分
+		accepted, _, _ := language.ParseAcceptLanguage(acceptLanguage)
+		tags = append(tags, accepted...)
+	}
+
+	seen := make(map[string]bool)
+	var locales []string
+	add := func(locale string) {
+		if locale != "" && locale != "und" && !seen[locale] {
+			seen[locale] = true
+			locales = append(locales, locale)
+		}
+	}
+	for _, tag := range tags {
+		if len(locales) >= maxCandidates {
+			break
+		}
+		add(tag.String())
+		base, _ := tag.Base()
+		add(base.String())
+	}
+	return locales
+}
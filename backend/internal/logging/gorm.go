@@ -0,0 +1,93 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// gormLogger 将 GORM 日志写入 slog：查询失败记为 error，超过慢查询阈值记为 warn，其余查询记为 debug
+// 日志器从上下文获取，请求内的 SQL 日志带有请求ID；SQL 只记录占位符，不记录参数值
+type gormLogger struct {
+	level         gormlogger.LogLevel
+	slowThreshold time.Duration
+}
+
+// NewGormLogger 创建 GORM 日志适配器，slowThreshold 为 0 时不记录慢查询
+func NewGormLogger(slowThreshold time.Duration) gormlogger.Interface {
+	return &gormLogger{
+		level:         gormlogger.Info,
+		slowThreshold: slowThreshold,
+	}
+}
+
+func (l *gormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	copied := *l
+	copied.level = level
+	return &copied
+}
+
+func (l *gormLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Info {
+		FromContext(ctx).InfoContext(ctx, fmt.Sprintf(msg, data...))
+	}
+}
+
+func (l *gormLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Warn {
+		FromContext(ctx).WarnContext(ctx, fmt.Sprintf(msg, data...))
+	}
+}
+
+func (l *gormLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Error {
+		FromContext(ctx).ErrorContext(ctx, fmt.Sprintf(msg, data...))
+	}
+}
+
+// ParamsFilter 日志中的 SQL 不代入参数，避免记录密码哈希、用户名和作答内容等数据
+func (l *gormLogger) ParamsFilter(ctx context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	return sql, nil
+}
+
+func (l *gormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if l.level <= gormlogger.Silent {
+		return
+	}
+	elapsed := time.Since(begin)
+	logger := FromContext(ctx)
+
+	switch {
+	case err != nil && expectedError(err):
+		if l.level >= gormlogger.Info && logger.Enabled(ctx, slog.LevelDebug) {
+			sql, rows := fc()
+			logger.DebugContext(ctx, "database query rejected", "error", err, "sql", sql, "rows", rows, "elapsed_ms", milliseconds(elapsed))
+		}
+	case err != nil && l.level >= gormlogger.Error:
+		sql, rows := fc()
+		logger.ErrorContext(ctx, "database query failed", "error", err, "sql", sql, "rows", rows, "elapsed_ms", milliseconds(elapsed))
+	case l.slowThreshold > 0 && elapsed > l.slowThreshold && l.level >= gormlogger.Warn:
+		sql, rows := fc()
+		logger.WarnContext(ctx, "slow query", "sql", sql, "rows", rows, "elapsed_ms", milliseconds(elapsed), "threshold_ms", milliseconds(l.slowThreshold))
+	case l.level >= gormlogger.Info && logger.Enabled(ctx, slog.LevelDebug):
+		sql, rows := fc()
+		logger.DebugContext(ctx, "database query", "sql", sql, "rows", rows, "elapsed_ms", milliseconds(elapsed))
+	}
+}
+
+// expectedError 记录不存在和约束冲突由仓储转换为业务错误（404、409 等），属于正常结果而不是故障
+func expectedError(err error) bool {
+	return errors.Is(err, gorm.ErrRecordNotFound) ||
+		errors.Is(err, gorm.ErrDuplicatedKey) ||
+		errors.Is(err, gorm.ErrForeignKeyViolated)
+}
+
+// milliseconds 将时长转换为保留三位小数的毫秒数
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
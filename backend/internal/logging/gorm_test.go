@@ -0,0 +1,75 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type gormTestUser struct {
+	ID           uint   `gorm:"primarykey"`
+	Username     string `gorm:"uniqueIndex"`
+	PasswordHash string
+}
+
+func TestGormLogger(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger:         NewGormLogger(0),
+		TranslateError: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&gormTestUser{}); err != nil {
+		t.Fatal(err)
+	}
+
+	capture := func(level slog.Level, query func(db *gorm.DB) error) (string, error) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: level}))
+		err := query(db.WithContext(NewContext(context.Background(), logger)))
+		return buf.String(), err
+	}
+	user := gormTestUser{Username: "alice", PasswordHash: "secret-hash"}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// 唯一键冲突由仓储转换为 409，不记为错误
+	out, err := capture(slog.LevelInfo, func(db *gorm.DB) error {
+		return db.Create(&gormTestUser{Username: "alice", PasswordHash: "secret-hash"}).Error
+	})
+	if err == nil || out != "" {
+		t.Errorf("duplicate key: err = %v, log = %q", err, out)
+	}
+	out, _ = capture(slog.LevelDebug, func(db *gorm.DB) error {
+		return db.Create(&gormTestUser{Username: "alice", PasswordHash: "secret-hash"}).Error
+	})
+	if !strings.Contains(out, "level=DEBUG") || !strings.Contains(out, "database query rejected") {
+		t.Errorf("duplicate key at debug = %q", out)
+	}
+
+	// 意外的失败记为错误；SQL 只带占位符，不带参数值
+	failed, err := capture(slog.LevelInfo, func(db *gorm.DB) error {
+		return db.Exec("UPDATE missing_table SET password_hash = ? WHERE username = ?", "secret-hash", "alice").Error
+	})
+	if err == nil || !strings.Contains(failed, "level=ERROR") || !strings.Contains(failed, "password_hash = ?") {
+		t.Errorf("failed query: err = %v, log = %q", err, failed)
+	}
+	query, err := capture(slog.LevelDebug, func(db *gorm.DB) error {
+		return db.Where("username = ? AND password_hash = ?", "alice", "secret-hash").First(&gormTestUser{}).Error
+	})
+	if err != nil || !strings.Contains(query, "database query") {
+		t.Errorf("debug query: err = %v, log = %q", err, query)
+	}
+	for name, log := range map[string]string{"rejected": out, "failed": failed, "query": query} {
+		if strings.Contains(log, "secret-hash") || strings.Contains(log, "alice") {
+			t.Errorf("%s log contains bound values: %q", name, log)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"voicewriter/internal/config"
+)
+
+// defaultLogFile output 为 file 且未配置文件路径时使用
+const defaultLogFile = "logs/voicewriter.log"
+
+// defaultMaxSize 未配置时单个日志文件的大小上限（MB）
+const defaultMaxSize = 100
+
+type contextKey struct{}
+
+// New 根据日志配置创建 slog 日志器，返回的 Closer 用于在退出时关闭日志文件
+func New(cfg *config.LogConfig) (*slog.Logger, io.Closer, error) {
+	level, err := ParseLevel(cfg.Level)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var out io.WriteCloser
+	switch strings.ToLower(cfg.Output) {
+	case "", "stdout":
+		out = nopCloser{os.Stdout}
+	case "stderr":
+		out = nopCloser{os.Stderr}
+	case "file":
+		path := cfg.File
+		if path == "" {
+			path = defaultLogFile
+		}
+		maxSize := cfg.MaxSize
+		if maxSize <= 0 {
+			maxSize = defaultMaxSize
+		}
+		file, err := NewRotatingFile(path, int64(maxSize)<<20, cfg.MaxBackups, cfg.MaxAge)
+		if err != nil {
+			return nil, nil, err
+		}
+		out = file
+	default:
+		return nil, nil, fmt.Errorf("unsupported log output %q, expected stdout, stderr or file", cfg.Output)
+	}
+
+	opts := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	switch strings.ToLower(cfg.Format) {
+	case "", "json":
+		handler = slog.NewJSONHandler(out, opts)
+	case "text":
+		handler = slog.NewTextHandler(out, opts)
+	default:
+		out.Close()
+		return nil, nil, fmt.Errorf("unsupported log format %q, expected json or text", cfg.Format)
+	}
+	return slog.New(handler), out, nil
+}
+
+// ParseLevel 解析日志级别，空字符串为 info
+func ParseLevel(level string) (slog.Level, error) {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return 0, fmt.Errorf("unsupported log level %q, expected debug, info, warn or error", level)
+	}
+}
+
+// NewContext 返回携带日志器的上下文，用于在请求范围内附加请求ID等字段
+func NewContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext 返回上下文中的日志器，没有时返回默认日志器
+func FromContext(ctx context.Context) *slog.Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+			return logger
+		}
+	}
+	return slog.Default()
+}
+
+// nopCloser 标准输出不随日志器关闭
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
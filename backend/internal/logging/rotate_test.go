@@ -0,0 +1,79 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRotatingFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	f, err := NewRotatingFile(path, 10, 2, 0)
+	if err != nil {
+		t.Fatalf("NewRotatingFile: %v", err)
+	}
+	defer f.Close()
+
+	// 每次写入都会超过上限，第一次之后每次写入前都轮转
+	lines := []string{"line-1\n", "line-2\n", "line-3\n", "line-4\n"}
+	for _, line := range lines {
+		if _, err := f.Write([]byte(line)); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+		// 历史文件名精确到毫秒
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	current, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read current file: %v", err)
+	}
+	if string(current) != "line-4\n" {
+		t.Errorf("current file = %q, want %q", current, "line-4\n")
+	}
+
+	backups := f.backups()
+	if len(backups) != 2 {
+		t.Fatalf("backups = %v, want 2 files", backups)
+	}
+	var contents []string
+	for _, backup := range backups {
+		data, err := os.ReadFile(backup)
+		if err != nil {
+			t.Fatalf("read backup: %v", err)
+		}
+		contents = append(contents, string(data))
+	}
+	if got := strings.Join(contents, ""); got != "line-2\nline-3\n" {
+		t.Errorf("backups contain %q, want the two newest rotated lines", got)
+	}
+}
+
+func TestRotatingFileAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "app.log")
+
+	for _, line := range []string{"first\n", "second\n"} {
+		f, err := NewRotatingFile(path, 1<<20, 0, 0)
+		if err != nil {
+			t.Fatalf("NewRotatingFile: %v", err)
+		}
+		if _, err := f.Write([]byte(line)); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+		if err := f.Close(); err != nil {
+			t.Fatalf("Close: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read file: %v", err)
+	}
+	if string(data) != "first\nsecond\n" {
+		t.Errorf("file = %q, want both writes appended", data)
+	}
+}
@@ -0,0 +1,199 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Namespace 本服务指标名的统一前缀
+const Namespace = "voicewriter"
+
+// 指标类型，对应 Prometheus 文本格式中的 # TYPE
+const (
+	TypeCounter   = "counter"
+	TypeGauge     = "gauge"
+	TypeHistogram = "histogram"
+)
+
+// contentType Prometheus 文本格式 0.0.4
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefBuckets 默认的耗时直方图分桶（秒）
+var DefBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Label 指标标签
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Sample 一个样本，Suffix 附加在指标名之后，如直方图的 _bucket
+type Sample struct {
+	Suffix string
+	Labels []Label
+	Value  float64
+}
+
+// Family 同名指标的全部样本
+type Family struct {
+	Name    string
+	Help    string
+	Type    string
+	Samples []Sample
+}
+
+// Collector 在抓取时提供指标，各子系统实现并注册自己的 Collector
+type Collector interface {
+	Collect() []*Family
+}
+
+// CollectorFunc 函数形式的 Collector，适合抓取时读取状态的指标，如连接池统计
+type CollectorFunc func() []*Family
+
+// Collect 调用函数本身
+func (f CollectorFunc) Collect() []*Family {
+	return f()
+}
+
+// Registry 指标注册表，Handler 以 Prometheus 文本格式输出全部已注册的指标
+type Registry struct {
+	mu         sync.RWMutex
+	collectors []Collector
+}
+
+// NewRegistry 创建指标注册表
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register 注册 Collector
+func (r *Registry) Register(collectors ...Collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.collectors = append(r.collectors, collectors...)
+}
+
+// Gather 收集全部指标，同名指标合并，按名称排序
+func (r *Registry) Gather() []*Family {
+	r.mu.RLock()
+	collectors := append([]Collector(nil), r.collectors...)
+	r.mu.RUnlock()
+
+	byName := make(map[string]*Family)
+	var families []*Family
+	for _, collector := range collectors {
+		for _, family := range collector.Collect() {
+			if existing, ok := byName[family.Name]; ok {
+				existing.Samples = append(existing.Samples, family.Samples...)
+				continue
+			}
+			byName[family.Name] = family
+			families = append(families, family)
+		}
+	}
+	sort.Slice(families, func(i, j int) bool { return families[i].Name < families[j].Name })
+	return families
+}
+
+// WriteTo 以 Prometheus 文本格式写出全部指标
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: bufio.NewWriter(w)}
+	for _, family := range r.Gather() {
+		fmt.Fprintf(cw, "# HELP %s %s\n", family.Name, escapeHelp(family.Help))
+		fmt.Fprintf(cw, "# TYPE %s %s\n", family.Name, family.Type)
+		for _, sample := range family.Samples {
+			cw.WriteString(family.Name + sample.Suffix)
+			writeLabels(cw, sample.Labels)
+			cw.WriteString(" " + formatValue(sample.Value) + "\n")
+		}
+	}
+	if err := cw.w.Flush(); err != nil && cw.err == nil {
+		cw.err = err
+	}
+	return cw.n, cw.err
+}
+
+// Handler 返回输出指标的 HTTP 处理器
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		r.WriteTo(w)
+	})
+}
+
+// Gauge 创建单个样本的仪表盘指标族，常用于 CollectorFunc
+func Gauge(name, help string, value float64, labels ...Label) *Family {
+	return &Family{Name: name, Help: help, Type: TypeGauge, Samples: []Sample{{Labels: labels, Value: value}}}
+}
+
+// Counter 创建单个样本的计数器指标族，常用于 CollectorFunc
+func Counter(name, help string, value float64, labels ...Label) *Family {
+	return &Family{Name: name, Help: help, Type: TypeCounter, Samples: []Sample{{Labels: labels, Value: value}}}
+}
+
+func writeLabels(w *countingWriter, labels []Label) {
+	if len(labels) == 0 {
+		return
+	}
+	w.WriteString("{")
+	for i, label := range labels {
+		if i > 0 {
+			w.WriteString(",")
+		}
+		w.WriteString(label.Name + `="` + escapeLabel(label.Value) + `"`)
+	}
+	w.WriteString("}")
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string {
+	return helpEscaper.Replace(s)
+}
+
+func escapeLabel(s string) string {
+	return labelEscaper.Replace(s)
+}
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// countingWriter 记录写出的字节数和第一个错误
+type countingWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	c.err = err
+	return n, err
+}
+
+func (c *countingWriter) WriteString(s string) {
+	c.Write([]byte(s))
+}
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRegistryExposition(t *testing.T) {
+	registry := NewRegistry()
+
+	requests := NewCounterVec("requests_total", "Requests by path.", "path")
+	requests.Inc("/a")
+	requests.Add(2, `/b"\`)
+
+	latency := NewHistogramVec("latency_seconds", "Latency.", []float64{0.1, 1}, "path")
+	latency.Observe(0.05, "/a")
+	latency.Observe(0.5, "/a")
+	latency.Observe(3, "/a")
+
+	registry.Register(requests, latency, CollectorFunc(func() []*Family {
+		return []*Family{Gauge("voicewriter_up", "Whether the service is up.\nMultiline help.", 1)}
+	}))
+
+	w := httptest.NewRecorder()
+	registry.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if got := w.Header().Get("Content-Type"); got != contentType {
+		t.Errorf("content type = %q", got)
+	}
+
+	want := strings.Join([]string{
+		`# HELP voicewriter_latency_seconds Latency.`,
+		`# TYPE voicewriter_latency_seconds histogram`,
+		`voicewriter_latency_seconds_bucket{path="/a",le="0.1"} 1`,
+		`voicewriter_latency_seconds_bucket{path="/a",le="1"} 2`,
+		`voicewriter_latency_seconds_bucket{path="/a",le="+Inf"} 3`,
+		`voicewriter_latency_seconds_sum{path="/a"} 3.55`,
+		`voicewriter_latency_seconds_count{path="/a"} 3`,
+		`# HELP voicewriter_requests_total Requests by path.`,
+		`# TYPE voicewriter_requests_total counter`,
+		`voicewriter_requests_total{path="/a"} 1`,
+		`voicewriter_requests_total{path="/b\"\\"} 2`,
+		`# HELP voicewriter_up Whether the service is up.\nMultiline help.`,
+		`# TYPE voicewriter_up gauge`,
+		`voicewriter_up 1`,
+		``,
+	}, "\n")
+	if got := w.Body.String(); got != want {
+		t.Errorf("exposition mismatch\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestCounterVecLabelCount(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Inc with wrong label count should panic")
+		}
+	}()
+	NewCounterVec("x_total", "x", "a", "b").Inc("only-one")
+}
@@ -0,0 +1,183 @@
+package metrics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// labelSet 一组标签值，key 用于在 map 中定位
+type labelSet struct {
+	key    string
+	values []string
+}
+
+// vec 按标签值分组的样本集合
+type vec struct {
+	name       string
+	help       string
+	labelNames []string
+}
+
+func (v *vec) labelSet(values []string) labelSet {
+	if len(values) != len(v.labelNames) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", v.name, len(v.labelNames), len(values)))
+	}
+	return labelSet{key: strings.Join(values, "\xff"), values: append([]string(nil), values...)}
+}
+
+func (v *vec) labels(values []string, extra ...Label) []Label {
+	labels := make([]Label, 0, len(values)+len(extra))
+	for i, value := range values {
+		labels = append(labels, Label{Name: v.labelNames[i], Value: value})
+	}
+	return append(labels, extra...)
+}
+
+// sortedKeys 返回按 key 排序的标签组，保证输出顺序稳定
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// CounterVec 按标签分组的计数器
+type CounterVec struct {
+	vec
+	mu     sync.Mutex
+	values map[string]*counterValue
+}
+
+type counterValue struct {
+	labels []string
+	value  float64
+}
+
+// NewCounterVec 创建计数器，名称会加上 Namespace 前缀
+func NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	return &CounterVec{
+		vec:    vec{name: Namespace + "_" + name, help: help, labelNames: labelNames},
+		values: make(map[string]*counterValue),
+	}
+}
+
+// Inc 计数加一，标签值顺序与创建时的标签名一致
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add 计数增加 delta，delta 不能为负
+func (c *CounterVec) Add(delta float64, labelValues ...string) {
+	if delta < 0 {
+		panic(fmt.Sprintf("metrics: counter %s cannot decrease", c.name))
+	}
+	set := c.labelSet(labelValues)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	value, ok := c.values[set.key]
+	if !ok {
+		value = &counterValue{labels: set.values}
+		c.values[set.key] = value
+	}
+	value.value += delta
+}
+
+// Value 返回标签组当前的计数，主要用于测试
+func (c *CounterVec) Value(labelValues ...string) float64 {
+	set := c.labelSet(labelValues)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if value, ok := c.values[set.key]; ok {
+		return value.value
+	}
+	return 0
+}
+
+// Collect 实现 Collector
+func (c *CounterVec) Collect() []*Family {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	family := &Family{Name: c.name, Help: c.help, Type: TypeCounter}
+	for _, key := range sortedKeys(c.values) {
+		value := c.values[key]
+		family.Samples = append(family.Samples, Sample{Labels: c.labels(value.labels), Value: value.value})
+	}
+	return []*Family{family}
+}
+
+// HistogramVec 按标签分组的直方图
+type HistogramVec struct {
+	vec
+	buckets []float64
+	mu      sync.Mutex
+	values  map[string]*histogramValue
+}
+
+type histogramValue struct {
+	labels []string
+	counts []uint64 // 各分桶的非累计计数
+	count  uint64
+	sum    float64
+}
+
+// NewHistogramVec 创建直方图，buckets 为升序的分桶上界，名称会加上 Namespace 前缀
+func NewHistogramVec(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	if !sort.Float64sAreSorted(buckets) {
+		panic(fmt.Sprintf("metrics: histogram %s buckets must be sorted", name))
+	}
+	return &HistogramVec{
+		vec:     vec{name: Namespace + "_" + name, help: help, labelNames: labelNames},
+		buckets: buckets,
+		values:  make(map[string]*histogramValue),
+	}
+}
+
+// Observe 记录一次观测值
+func (h *HistogramVec) Observe(v float64, labelValues ...string) {
+	set := h.labelSet(labelValues)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	value, ok := h.values[set.key]
+	if !ok {
+		value = &histogramValue{labels: set.values, counts: make([]uint64, len(h.buckets))}
+		h.values[set.key] = value
+	}
+	if i := sort.SearchFloat64s(h.buckets, v); i < len(h.buckets) {
+		value.counts[i]++
+	}
+	value.count++
+	value.sum += v
+}
+
+// Collect 实现 Collector，输出累计分桶、_sum 和 _count
+func (h *HistogramVec) Collect() []*Family {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	family := &Family{Name: h.name, Help: h.help, Type: TypeHistogram}
+	for _, key := range sortedKeys(h.values) {
+		value := h.values[key]
+		var cumulative uint64
+		for i, upper := range h.buckets {
+			cumulative += value.counts[i]
+			family.Samples = append(family.Samples, Sample{
+				Suffix: "_bucket",
+				Labels: h.labels(value.labels, Label{Name: "le", Value: formatValue(upper)}),
+				Value:  float64(cumulative),
+			})
+		}
+		family.Samples = append(family.Samples,
+			Sample{Suffix: "_bucket", Labels: h.labels(value.labels, Label{Name: "le", Value: "+Inf"}), Value: float64(value.count)},
+			Sample{Suffix: "_sum", Labels: h.labels(value.labels), Value: value.sum},
+			Sample{Suffix: "_count", Labels: h.labels(value.labels), Value: float64(value.count)},
+		)
+	}
+	return []*Family{family}
+}
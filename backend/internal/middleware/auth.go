@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"strings"
+
+	"voicewriter/internal/auth"
+	"voicewriter/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// identityKey 身份信息在 gin.Context 中的键
+const identityKey = "identity"
+
+// Auth 要求请求携带有效的访问令牌，并将身份信息注入上下文
+func Auth(tokens *auth.TokenManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		identity, ok := authenticate(c, tokens)
+		if !ok {
+			response.Unauthorized(c, "Invalid or missing access token")
+			c.Abort()
+			return
+		}
+		c.Set(identityKey, identity)
+		c.Next()
+	}
+}
+
+// OptionalAuth 携带有效令牌时注入身份信息，未携带时按匿名请求放行
+func OptionalAuth(tokens *auth.TokenManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.Next()
+			return
+		}
+		identity, ok := authenticate(c, tokens)
+		if !ok {
+			response.Unauthorized(c, "Invalid access token")
+			c.Abort()
+			return
+		}
+		c.Set(identityKey, identity)
+		c.Next()
+	}
+}
+
+// CurrentUser 获取当前请求的已认证用户
+func CurrentUser(c *gin.Context) (*auth.Identity, bool) {
+	value, ok := c.Get(identityKey)
+	if !ok {
+		return nil, false
+	}
+	identity, ok := value.(*auth.Identity)
+	return identity, ok
+}
+
+func authenticate(c *gin.Context, tokens *auth.TokenManager) (*auth.Identity, bool) {
+	header := c.GetHeader("Authorization")
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return nil, false
+	}
+	claims, err := tokens.Parse(strings.TrimSpace(token), auth.TokenTypeAccess)
+	if err != nil {
+		return nil, false
+	}
+	return claims.Identity(), true
+}
+
+// RequireRole 要求已认证用户具有指定角色之一，需放在 Auth 之后
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		identity, ok := CurrentUser(c)
+		if !ok {
+			response.Unauthorized(c, "Authentication required")
+			c.Abort()
+			return
+		}
+		for _, role := range roles {
+			if identity.Role == role {
+				c.Next()
+				return
+			}
+		}
+		response.Forbidden(c, "Insufficient permissions")
+		c.Abort()
+	}
+}
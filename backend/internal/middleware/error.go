@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"voicewriter/internal/dto"
+	"voicewriter/internal/logging"
+	"voicewriter/internal/service"
+	apperrors "voicewriter/pkg/errors"
+	"voicewriter/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorHandler 将处理器通过 c.Error 记录的错误统一转换为响应，按错误类别决定状态码和错误码
+// 处理器已经写入响应时不做处理；内部错误只记录日志，不向客户端暴露原因
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		err := c.Errors.Last().Err
+		kind := service.KindOf(err)
+		if kind == apperrors.KindInternal || kind == apperrors.KindUnavailable {
+			logging.FromContext(c.Request.Context()).Error("request failed", "error", err, "route", c.FullPath())
+		}
+
+		status, code := statusOf(kind)
+		if details := errorDetails(err); details != nil {
+			response.ErrorWithCodeAndData(c, status, code, service.PublicMessage(err), details)
+			return
+		}
+		response.ErrorWithCode(c, status, code, service.PublicMessage(err))
+	}
+}
+
+// errorDetails 返回随错误响应一起返回的数据：带详情的服务错误转换为 dto，业务错误返回其 Details
+func errorDetails(err error) interface{} {
+	var importInvalid *service.ImportInvalidError
+	if errors.As(err, &importInvalid) {
+		return dto.NewImportReport(importInvalid.Report)
+	}
+	if e, ok := apperrors.As(err); ok && e.Details != nil {
+		return e.Details
+	}
+	return nil
+}
+
+// statusOf 返回错误类别对应的 HTTP 状态码和错误码
+func statusOf(kind apperrors.Kind) (int, int) {
+	switch kind {
+	case apperrors.KindValidation:
+		return http.StatusBadRequest, response.CodeValidation
+	case apperrors.KindNotFound:
+		return http.StatusNotFound, response.CodeNotFound
+	case apperrors.KindConflict:
+		return http.StatusConflict, response.CodeConflict
+	case apperrors.KindUnauthorized:
+		return http.StatusUnauthorized, response.CodeUnauthorized
+	case apperrors.KindForbidden:
+		return http.StatusForbidden, response.CodeForbidden
+	case apperrors.KindUnavailable:
+		return http.StatusServiceUnavailable, response.CodeUnavailable
+	default:
+		return http.StatusInternalServerError, response.CodeInternal
+	}
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"voicewriter/internal/logging"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader 请求ID所在的请求头和响应头，客户端未提供时由服务端生成
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength 客户端提供的请求ID最大长度，超过时重新生成
+const maxRequestIDLength = 128
+
+// RequestLogger 为每个请求分配请求ID，并在请求结束后记录方法、路径、状态码、耗时和用户
+// 带请求ID的日志器放入请求上下文，后续通过 logging.FromContext 获取；需注册在其他中间件之前
+func RequestLogger(logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" || len(requestID) > maxRequestIDLength {
+			requestID = uuid.NewString()
+		}
+		c.Header(RequestIDHeader, requestID)
+
+		reqLogger := logger.With("request_id", requestID)
+		c.Request = c.Request.WithContext(logging.NewContext(c.Request.Context(), reqLogger))
+
+		c.Next()
+
+		status := c.Writer.Status()
+		attrs := []interface{}{
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"route", c.FullPath(),
+			"status", status,
+			"latency_ms", float64(time.Since(start).Microseconds()) / 1000,
+			"client_ip", c.ClientIP(),
+			"bytes", c.Writer.Size(),
+		}
+		if identity, ok := CurrentUser(c); ok {
+			attrs = append(attrs, "user", identity.UID)
+		}
+
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+		reqLogger.Log(c.Request.Context(), level, "request", attrs...)
+	}
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"voicewriter/internal/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// unmatchedRoute 未匹配到路由的请求统一使用的标签值，避免任意路径导致标签基数膨胀
+const unmatchedRoute = "unmatched"
+
+// Metrics 按路由模板统计请求数和耗时，并将指标注册到 registry
+func Metrics(registry *metrics.Registry) gin.HandlerFunc {
+	requests := metrics.NewCounterVec("http_requests_total", "HTTP requests by route, method and status code.", "method", "route", "status")
+	duration := metrics.NewHistogramVec("http_request_duration_seconds", "HTTP request latency by route and method.", metrics.DefBuckets, "method", "route")
+	registry.Register(requests, duration)
+
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		requests.Inc(c.Request.Method, route, strconv.Itoa(c.Writer.Status()))
+		duration.Observe(time.Since(start).Seconds(), c.Request.Method, route)
+	}
+}
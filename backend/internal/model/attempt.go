@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Attempt 听写作答记录，每次提交保存一条
+type Attempt struct {
+	ID            uint           `gorm:"primarykey" json:"id"`
+	UserID        string         `gorm:"type:varchar(100);not null;index:idx_attempts_user_created,priority:1" json:"user_id"`
+	SentenceID    uint           `gorm:"not null;index" json:"sentence_id"`
+	SceneID       uint           `gorm:"not null;index" json:"scene_id"`
+	SubmittedText string         `gorm:"type:text" json:"submitted_text"`
+	Graded        bool           `gorm:"not null;default:false" json:"graded"` // 服务端按提交的文本评过分；未评分的作答得分为 0，不计入平均得分
+	Score         float64        `gorm:"default:0" json:"score"`               // 0-100
+	Completed     bool           `gorm:"default:false" json:"completed"`
+	DurationMs    int            `gorm:"default:0" json:"duration_ms"`
+	AudioReplays  int            `gorm:"default:0" json:"audio_replays"`
+	CreatedAt     time.Time      `gorm:"autoCreateTime;index:idx_attempts_user_created,priority:2" json:"created_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// 关联
+	Sentence *Sentence `gorm:"foreignKey:SentenceID" json:"sentence,omitempty"`
+}
+
+// TableName 指定表名
+func (Attempt) TableName() string {
+	return "attempts"
+}
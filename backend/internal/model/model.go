@@ -12,10 +12,14 @@ type Scene struct {
 	Name        string         `gorm:"type:varchar(100);not null" json:"name"`
 	Description string         `gorm:"type:text" json:"description"`
 	Icon        string         `gorm:"type:varchar(50)" json:"icon"`
+	Strictness  string         `gorm:"type:varchar(20);not null;default:'normal'" json:"strictness"` // 评分严格程度：strict, normal, lenient
 	CreatedAt   time.Time      `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt   time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
 
+	// Locale 本地化后 Name/Description 实际使用的语言，不入库
+	Locale string `gorm:"-" json:"locale,omitempty"`
+
 	// 关联
 	Sentences []Sentence `gorm:"foreignKey:SceneID" json:"sentences,omitempty"`
 }
@@ -29,14 +33,18 @@ func (Scene) TableName() string {
 type Sentence struct {
 	ID          uint           `gorm:"primarykey" json:"id"`
 	SceneID     uint           `gorm:"not null;index" json:"scene_id"`
+	Language    string         `gorm:"type:varchar(20);not null;default:'en';index" json:"language"` // 句子所属的目标语言，BCP 47
 	Content     string         `gorm:"type:text;not null" json:"content"`
-	Translation string         `gorm:"type:text" json:"translation"`
+	Translation string         `gorm:"type:text" json:"translation"` // 默认语言（中文）翻译，其他语言见 sentence_translations
 	AudioURL    string         `gorm:"type:varchar(255)" json:"audio_url"`
 	Difficulty  string         `gorm:"type:varchar(20);default:'easy'" json:"difficulty"` // easy, medium, hard
 	CreatedAt   time.Time      `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt   time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
 
+	// TranslationLocale 本地化后 Translation 实际使用的语言，不入库
+	TranslationLocale string `gorm:"-" json:"translation_locale,omitempty"`
+
 	// 关联
 	Scene *Scene `gorm:"foreignKey:SceneID" json:"scene,omitempty"`
 }
@@ -49,8 +57,8 @@ func (Sentence) TableName() string {
 // UserProgress 用户进度模型
 type UserProgress struct {
 	ID          uint           `gorm:"primarykey" json:"id"`
-	UserID      string         `gorm:"type:varchar(100);not null;index" json:"user_id"`
-	SentenceID  uint           `gorm:"not null;index" json:"sentence_id"`
+	UserID      string         `gorm:"type:varchar(100);not null;uniqueIndex:uk_user_sentence,priority:1" json:"user_id"`
+	SentenceID  uint           `gorm:"not null;uniqueIndex:uk_user_sentence,priority:2;index" json:"sentence_id"`
 	Completed   bool           `gorm:"default:false" json:"completed"`
 	Attempts    int            `gorm:"default:0" json:"attempts"`
 	LastAttempt time.Time      `json:"last_attempt"`
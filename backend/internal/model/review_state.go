@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ReviewState 间隔重复复习状态，每个 (用户, 句子) 一条
+type ReviewState struct {
+	ID             uint           `gorm:"primarykey" json:"id"`
+	UserID         string         `gorm:"type:varchar(100);not null;uniqueIndex:uk_review_user_sentence,priority:1;index:idx_review_user_due,priority:1" json:"user_id"`
+	SentenceID     uint           `gorm:"not null;uniqueIndex:uk_review_user_sentence,priority:2" json:"sentence_id"`
+	EaseFactor     float64        `gorm:"not null;default:2.5" json:"ease_factor"`
+	IntervalDays   int            `gorm:"not null;default:0" json:"interval_days"`
+	Repetitions    int            `gorm:"not null;default:0" json:"repetitions"` // 连续答对次数
+	Lapses         int            `gorm:"not null;default:0" json:"lapses"`      // 遗忘次数
+	DueAt          time.Time      `gorm:"not null;index:idx_review_user_due,priority:2" json:"due_at"`
+	LastReviewedAt time.Time      `json:"last_reviewed_at"`
+	CreatedAt      time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// 关联
+	Sentence *Sentence `gorm:"foreignKey:SentenceID" json:"sentence,omitempty"`
+}
+
+// TableName 指定表名
+func (ReviewState) TableName() string {
+	return "review_states"
+}
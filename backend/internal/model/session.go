@@ -0,0 +1,69 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 练习会话来源
+const (
+	SessionModeScene  = "scene"  // 按场景练习
+	SessionModeReview = "review" // 练习到期的复习句子
+)
+
+// 练习会话状态
+const (
+	SessionStatusActive   = "active"
+	SessionStatusFinished = "finished"
+)
+
+// PracticeSession 听写练习会话，开始时由服务端选定并排列句子，结束时保存小结
+type PracticeSession struct {
+	ID         uint           `gorm:"primarykey" json:"id"`
+	UserID     string         `gorm:"type:varchar(100);not null;index:idx_practice_sessions_user_status,priority:1" json:"user_id"`
+	Mode       string         `gorm:"type:varchar(20);not null" json:"mode"`
+	SceneID    *uint          `json:"scene_id"` // 复习会话为空
+	Status     string         `gorm:"type:varchar(20);not null;default:active;index:idx_practice_sessions_user_status,priority:2" json:"status"`
+	Total      int            `gorm:"not null;default:0" json:"total"`
+	Answered   int            `gorm:"not null;default:0" json:"answered"`
+	Completed  int            `gorm:"not null;default:0" json:"completed"`
+	Accuracy   float64        `gorm:"not null;default:0" json:"accuracy"`    // 已作答句子的平均得分
+	DurationMs int            `gorm:"not null;default:0" json:"duration_ms"` // 作答用时合计
+	StartedAt  time.Time      `gorm:"not null" json:"started_at"`
+	FinishedAt *time.Time     `json:"finished_at"`
+	CreatedAt  time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// 关联
+	Items []*PracticeSessionItem `gorm:"foreignKey:SessionID" json:"items,omitempty"`
+}
+
+// TableName 指定表名
+func (PracticeSession) TableName() string {
+	return "practice_sessions"
+}
+
+// PracticeSessionItem 会话中的一个句子及其作答结果，同一句子可以重复作答，保留最近一次的得分
+type PracticeSessionItem struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	SessionID   uint           `gorm:"not null;uniqueIndex:uk_session_item_position,priority:1;uniqueIndex:uk_session_item_sentence,priority:1" json:"session_id"`
+	Position    int            `gorm:"not null;uniqueIndex:uk_session_item_position,priority:2" json:"position"`
+	SentenceID  uint           `gorm:"not null;uniqueIndex:uk_session_item_sentence,priority:2;index" json:"sentence_id"`
+	Attempts    int            `gorm:"not null;default:0" json:"attempts"`
+	Score       float64        `gorm:"not null;default:0" json:"score"`
+	Completed   bool           `gorm:"not null;default:false" json:"completed"`
+	DurationMs  int            `gorm:"not null;default:0" json:"duration_ms"`
+	MissedWords string         `gorm:"type:text" json:"missed_words"` // 空格分隔
+	AnsweredAt  *time.Time     `json:"answered_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"` // 句子只随会话一次写入，同一会话内的唯一索引不会与软删除的记录冲突
+
+	// 关联
+	Sentence *Sentence `gorm:"foreignKey:SentenceID" json:"sentence,omitempty"`
+}
+
+// TableName 指定表名
+func (PracticeSessionItem) TableName() string {
+	return "practice_session_items"
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SentenceTranslation 句子的多语言翻译，每个 (句子, 语言) 一条
+// 默认语言（中文）的翻译仍保存在 Sentence.Translation 中；唯一索引不含 deleted_at，重新写入已删除的翻译时恢复原记录
+type SentenceTranslation struct {
+	ID         uint           `gorm:"primarykey" json:"id"`
+	SentenceID uint           `gorm:"not null;uniqueIndex:uk_sentence_translation_locale,priority:1" json:"sentence_id"`
+	Locale     string         `gorm:"type:varchar(20);not null;uniqueIndex:uk_sentence_translation_locale,priority:2" json:"locale"`
+	Text       string         `gorm:"type:text;not null" json:"text"`
+	CreatedAt  time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName 指定表名
+func (SentenceTranslation) TableName() string {
+	return "sentence_translations"
+}
+
+// SceneTranslation 场景名称和描述的多语言版本，每个 (场景, 语言) 一条，删除后的处理与句子翻译相同
+type SceneTranslation struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	SceneID     uint           `gorm:"not null;uniqueIndex:uk_scene_translation_locale,priority:1" json:"scene_id"`
+	Locale      string         `gorm:"type:varchar(20);not null;uniqueIndex:uk_scene_translation_locale,priority:2" json:"locale"`
+	Name        string         `gorm:"type:varchar(100);not null" json:"name"`
+	Description string         `gorm:"type:text" json:"description"`
+	CreatedAt   time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName 指定表名
+func (SceneTranslation) TableName() string {
+	return "scene_translations"
+}
@@ -0,0 +1,48 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// RoleUser 普通学习者
+	RoleUser = "user"
+	// RoleAdmin 管理员
+	RoleAdmin = "admin"
+	// RoleAnonymous 服务端签发的匿名身份，没有对应的用户记录
+	RoleAnonymous = "anonymous"
+)
+
+// User 用户模型
+type User struct {
+	ID           uint           `gorm:"primarykey" json:"id"`
+	UID          string         `gorm:"type:varchar(100);not null;uniqueIndex" json:"uid"` // 对应进度、作答等表中的 user_id
+	Username     string         `gorm:"type:varchar(50);not null;uniqueIndex" json:"username"`
+	PasswordHash string         `gorm:"type:varchar(100);not null" json:"-"`
+	Role         string         `gorm:"type:varchar(20);not null;default:'user'" json:"role"` // user, admin
+	CreatedAt    time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName 指定表名
+func (User) TableName() string {
+	return "users"
+}
+
+// AnonymousID 服务端签发给未登录客户端的匿名用户ID，凭认领密钥转入注册账户，只能认领一次
+type AnonymousID struct {
+	ID          uint       `gorm:"primarykey" json:"id"`
+	AnonymousID string     `gorm:"type:varchar(100);not null;uniqueIndex:uk_anonymous_ids_anonymous_id" json:"anonymous_id"`
+	SecretHash  string     `gorm:"type:varchar(64);not null" json:"-"` // 认领密钥的 SHA-256 哈希，不保存明文；管理员迁移的旧ID为空
+	ClaimedBy   *string    `gorm:"type:varchar(100)" json:"claimed_by"`
+	ClaimedAt   *time.Time `json:"claimed_at"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName 指定表名
+func (AnonymousID) TableName() string {
+	return "anonymous_ids"
+}
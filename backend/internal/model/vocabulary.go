@@ -0,0 +1,32 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// VocabularyEntry 生词本中的一个词，每个 (用户, 语言, 词) 一条
+// 听写评分时漏写或拼错的词自动加入，再次出错时累加次数并更新来源句子
+type VocabularyEntry struct {
+	ID         uint           `gorm:"primarykey" json:"id"`
+	UserID     string         `gorm:"type:varchar(100);not null;uniqueIndex:uk_vocabulary_user_word,priority:1;index:idx_vocabulary_user_misses,priority:1" json:"user_id"`
+	Language   string         `gorm:"type:varchar(20);not null;uniqueIndex:uk_vocabulary_user_word,priority:2" json:"language"`
+	Word       string         `gorm:"type:varchar(100);not null;uniqueIndex:uk_vocabulary_user_word,priority:3" json:"word"` // 小写、去掉标点后的形式
+	SentenceID uint           `gorm:"not null;index" json:"sentence_id"`                                                     // 最近一次出错的句子
+	MissCount  int            `gorm:"not null;default:0;index:idx_vocabulary_user_misses,priority:2" json:"miss_count"`
+	LastSeenAt time.Time      `gorm:"not null" json:"last_seen_at"` // 最近一次出错时间
+	Starred    bool           `gorm:"not null;default:false" json:"starred"`
+	Archived   bool           `gorm:"not null;default:false" json:"archived"` // 已掌握，不再出现在练习中；再次出错时取消归档
+	CreatedAt  time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"` // 唯一索引不含 deleted_at，再次出错时恢复为新记录
+
+	// 关联
+	Sentence *Sentence `gorm:"foreignKey:SentenceID" json:"sentence,omitempty"`
+}
+
+// TableName 指定表名
+func (VocabularyEntry) TableName() string {
+	return "vocabulary"
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"voicewriter/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type anonymousRepository struct {
+	db *gorm.DB
+}
+
+// NewAnonymousRepository 创建匿名用户ID仓储实例
+func NewAnonymousRepository(db *gorm.DB) AnonymousRepository {
+	return &anonymousRepository{db: db}
+}
+
+func (r *anonymousRepository) Create(ctx context.Context, anonymous *model.AnonymousID) error {
+	return translateError(dbFrom(ctx, r.db).Create(anonymous).Error)
+}
+
+func (r *anonymousRepository) GetByAnonymousID(ctx context.Context, anonymousID string) (*model.AnonymousID, error) {
+	var anonymous model.AnonymousID
+	err := dbFrom(ctx, r.db).Where("anonymous_id = ?", anonymousID).First(&anonymous).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &anonymous, nil
+}
+
+// MarkClaimed 用带条件的 UPDATE 标记认领，并发认领同一ID时只有一个能成功
+func (r *anonymousRepository) MarkClaimed(ctx context.Context, anonymousID, uid string, at time.Time) (bool, error) {
+	result := dbFrom(ctx, r.db).Model(&model.AnonymousID{}).
+		Where("anonymous_id = ? AND claimed_at IS NULL", anonymousID).
+		Updates(map[string]interface{}{"claimed_by": uid, "claimed_at": at})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"voicewriter/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type attemptRepository struct {
+	db *gorm.DB
+}
+
+// NewAttemptRepository 创建作答记录仓储实例
+func NewAttemptRepository(db *gorm.DB) AttemptRepository {
+	return &attemptRepository{db: db}
+}
+
+func (r *attemptRepository) Create(ctx context.Context, attempt *model.Attempt) error {
+	return dbFrom(ctx, r.db).Create(attempt).Error
+}
+
+func (r *attemptRepository) List(ctx context.Context, filter AttemptFilter) ([]*model.Attempt, error) {
+	query := dbFrom(ctx, r.db).Model(&model.Attempt{})
+	if filter.UserID != "" {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.SentenceID != 0 {
+		query = query.Where("sentence_id = ?", filter.SentenceID)
+	}
+	if filter.SceneID != 0 {
+		query = query.Where("scene_id = ?", filter.SceneID)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var attempts []*model.Attempt
+	err := query.Order("created_at DESC, id DESC").Find(&attempts).Error
+	if err != nil {
+		return nil, err
+	}
+	return attempts, nil
+}
+
+func (r *attemptRepository) ReassignUser(ctx context.Context, fromUserID, toUserID string) (int64, error) {
+	result := dbFrom(ctx, r.db).Model(&model.Attempt{}).
+		Where("user_id = ?", fromUserID).
+		Update("user_id", toUserID)
+	return result.RowsAffected, result.Error
+}
+
+func (r *attemptRepository) SummarizeActivity(ctx context.Context, userID string) ([]ActivityBucket, error) {
+	var buckets []ActivityBucket
+	err := dbFrom(ctx, r.db).Model(&model.Attempt{}).
+		Select(epochBucket(r.db, "created_at", ActivityBucketSeconds)+" AS bucket_start, "+
+			"COUNT(*) AS attempts, SUM(CASE WHEN graded THEN 1 ELSE 0 END) AS graded, "+
+			"COALESCE(SUM(CASE WHEN graded THEN score ELSE 0 END), 0) AS score_sum, COALESCE(SUM(duration_ms), 0) AS duration_ms").
+		Where("user_id = ?", userID).
+		Group("bucket_start").
+		Order("bucket_start").
+		Scan(&buckets).Error
+	if err != nil {
+		return nil, err
+	}
+	return buckets, nil
+}
+
+// epochBucket 生成把时间列截断到 seconds 整数倍的 Unix 秒的表达式，各数据库取 Unix 时间的函数不同
+func epochBucket(db *gorm.DB, column string, seconds int) string {
+	switch db.Dialector.Name() {
+	case "mysql":
+		return fmt.Sprintf("CAST(FLOOR(UNIX_TIMESTAMP(%s) / %d) * %d AS SIGNED)", column, seconds, seconds)
+	case "postgres":
+		return fmt.Sprintf("CAST(FLOOR(EXTRACT(EPOCH FROM %s) / %d) * %d AS BIGINT)", column, seconds, seconds)
+	default:
+		// SQLite 默认不带 FLOOR，整数相除即向下取整
+		return fmt.Sprintf("CAST(strftime('%%s', %s) AS INTEGER) / %d * %d", column, seconds, seconds)
+	}
+}
+
+func (r *attemptRepository) SummarizeByUser(ctx context.Context) ([]UserAttemptSummary, error) {
+	var summaries []UserAttemptSummary
+	err := dbFrom(ctx, r.db).Model(&model.Attempt{}).
+		Select("user_id, COUNT(*) AS attempts, SUM(CASE WHEN graded THEN 1 ELSE 0 END) AS graded, " +
+			"COALESCE(AVG(CASE WHEN graded THEN score END), 0) AS avg_score, COALESCE(SUM(duration_ms), 0) AS duration_ms").
+		Group("user_id").
+		Order("user_id").
+		Scan(&summaries).Error
+	if err != nil {
+		return nil, err
+	}
+	return summaries, nil
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+
+	"voicewriter/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// importBatchSize 批量插入句子时每批的条数
+const importBatchSize = 200
+
+type contentRepository struct {
+	db *gorm.DB
+}
+
+// NewContentRepository 创建批量内容仓储实例
+func NewContentRepository(db *gorm.DB) ContentRepository {
+	return &contentRepository{db: db}
+}
+
+func (r *contentRepository) ImportBatch(ctx context.Context, scenes []*model.Scene, sentences []*model.Sentence) error {
+	return dbFrom(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		for _, scene := range scenes {
+			if err := tx.Omit(clause.Associations).Create(scene).Error; err != nil {
+				return translateError(err)
+			}
+		}
+
+		for _, sentence := range sentences {
+			if sentence.Scene != nil {
+				sentence.SceneID = sentence.Scene.ID
+			}
+		}
+		if len(sentences) == 0 {
+			return nil
+		}
+		return translateError(tx.Omit(clause.Associations).CreateInBatches(sentences, importBatchSize).Error)
+	})
+}
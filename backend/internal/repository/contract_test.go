@@ -0,0 +1,657 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"voicewriter/internal/model"
+	"voicewriter/internal/repository"
+)
+
+// repositories 一组共享同一存储的仓储实现
+type repositories struct {
+	scenes    repository.SceneRepository
+	sentences repository.SentenceRepository
+	progress  repository.ProgressRepository
+	tx        repository.TxManager
+}
+
+// runContract 对一种仓储实现运行契约测试，newRepos 每次返回基于空存储的仓储
+func runContract(t *testing.T, newRepos func(t *testing.T) repositories) {
+	t.Run("Scene", func(t *testing.T) { testSceneContract(t, newRepos(t)) })
+	t.Run("SceneList", func(t *testing.T) { testSceneListContract(t, newRepos(t)) })
+	t.Run("Sentence", func(t *testing.T) { testSentenceContract(t, newRepos(t)) })
+	t.Run("SentenceList", func(t *testing.T) { testSentenceListContract(t, newRepos(t)) })
+	t.Run("Progress", func(t *testing.T) { testProgressContract(t, newRepos(t)) })
+	t.Run("ProgressRecordAttempt", func(t *testing.T) { testProgressRecordAttemptContract(t, newRepos(t)) })
+	t.Run("ProgressReassign", func(t *testing.T) { testProgressReassignContract(t, newRepos(t)) })
+	t.Run("ProgressSummary", func(t *testing.T) { testProgressSummaryContract(t, newRepos(t)) })
+	t.Run("ProgressCompletion", func(t *testing.T) { testProgressCompletionContract(t, newRepos(t)) })
+	t.Run("Tx", func(t *testing.T) { testTxContract(t, newRepos(t)) })
+}
+
+func mustCreateScene(t *testing.T, repos repositories, name, description string) *model.Scene {
+	t.Helper()
+	scene := &model.Scene{Name: name, Description: description}
+	if err := repos.scenes.Create(context.Background(), scene); err != nil {
+		t.Fatalf("create scene %q: %v", name, err)
+	}
+	return scene
+}
+
+func mustCreateSentence(t *testing.T, repos repositories, sentence *model.Sentence) *model.Sentence {
+	t.Helper()
+	if err := repos.sentences.Create(context.Background(), sentence); err != nil {
+		t.Fatalf("create sentence %q: %v", sentence.Content, err)
+	}
+	return sentence
+}
+
+func sentenceIDs(sentences []*model.Sentence) []uint {
+	ids := make([]uint, len(sentences))
+	for i, sentence := range sentences {
+		ids[i] = sentence.ID
+	}
+	return ids
+}
+
+func sceneIDs(scenes []*model.Scene) []uint {
+	ids := make([]uint, len(scenes))
+	for i, scene := range scenes {
+		ids[i] = scene.ID
+	}
+	return ids
+}
+
+func equalIDs(a, b []uint) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func testSceneContract(t *testing.T, repos repositories) {
+	ctx := context.Background()
+
+	scene := mustCreateScene(t, repos, "日常生活", "日常对话")
+	if scene.ID == 0 || scene.CreatedAt.IsZero() || scene.UpdatedAt.IsZero() {
+		t.Fatalf("create did not fill id and timestamps: %+v", scene)
+	}
+
+	got, err := repos.scenes.GetByID(ctx, scene.ID)
+	if err != nil {
+		t.Fatalf("get by id: %v", err)
+	}
+	if got.Name != "日常生活" || got.Description != "日常对话" {
+		t.Errorf("get by id = %+v", got)
+	}
+	if _, err := repos.scenes.GetByID(ctx, scene.ID+100); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("get missing scene: err = %v, want ErrNotFound", err)
+	}
+
+	if got, err := repos.scenes.GetByName(ctx, "日常生活"); err != nil || got.ID != scene.ID {
+		t.Errorf("get by name = %+v, %v", got, err)
+	}
+	if _, err := repos.scenes.GetByName(ctx, "不存在"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("get by missing name: err = %v, want ErrNotFound", err)
+	}
+
+	// 修改返回值不影响存储
+	got.Name = "已修改"
+	if again, _ := repos.scenes.GetByID(ctx, scene.ID); again.Name != "日常生活" {
+		t.Errorf("mutating a returned scene changed the stored one: %q", again.Name)
+	}
+
+	got.Description = "新的描述"
+	if err := repos.scenes.Update(ctx, got); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if updated, _ := repos.scenes.GetByID(ctx, scene.ID); updated.Name != "已修改" || updated.Description != "新的描述" {
+		t.Errorf("after update = %+v", updated)
+	}
+
+	other := mustCreateScene(t, repos, "工作职场", "")
+	if err := repos.scenes.Delete(ctx, scene.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := repos.scenes.GetByID(ctx, scene.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("get deleted scene: err = %v, want ErrNotFound", err)
+	}
+	if err := repos.scenes.Delete(ctx, scene.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("delete twice: err = %v, want ErrNotFound", err)
+	}
+	all, err := repos.scenes.GetAll(ctx)
+	if err != nil {
+		t.Fatalf("get all: %v", err)
+	}
+	if !equalIDs(sceneIDs(all), []uint{other.ID}) {
+		t.Errorf("get all = %v, want [%d]", sceneIDs(all), other.ID)
+	}
+}
+
+func testSceneListContract(t *testing.T, repos repositories) {
+	ctx := context.Background()
+
+	a := mustCreateScene(t, repos, "Airport", "check-in and boarding")
+	b := mustCreateScene(t, repos, "Bank", "open an ACCOUNT")
+	c := mustCreateScene(t, repos, "Cafe", "order coffee")
+	d := mustCreateScene(t, repos, "Doctor", "describe symptoms, 100% recovery")
+
+	scenes, total, err := repos.scenes.List(ctx, repository.SceneFilter{}, repository.PageRequest{})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if total != 4 || !equalIDs(sceneIDs(scenes), []uint{a.ID, b.ID, c.ID, d.ID}) {
+		t.Errorf("list all = %v (total %d)", sceneIDs(scenes), total)
+	}
+
+	// 名称或描述包含，不区分大小写
+	scenes, total, _ = repos.scenes.List(ctx, repository.SceneFilter{Query: "account"}, repository.PageRequest{})
+	if total != 1 || !equalIDs(sceneIDs(scenes), []uint{b.ID}) {
+		t.Errorf("query account = %v (total %d)", sceneIDs(scenes), total)
+	}
+	// 通配符按字面匹配
+	scenes, total, _ = repos.scenes.List(ctx, repository.SceneFilter{Query: "0%"}, repository.PageRequest{})
+	if total != 1 || !equalIDs(sceneIDs(scenes), []uint{d.ID}) {
+		t.Errorf("query 0%% = %v (total %d)", sceneIDs(scenes), total)
+	}
+
+	page := repository.PageRequest{SortBy: "name", Desc: true, Limit: 2}
+	scenes, total, _ = repos.scenes.List(ctx, repository.SceneFilter{}, page)
+	if total != 4 || !equalIDs(sceneIDs(scenes), []uint{d.ID, c.ID}) {
+		t.Fatalf("name desc first page = %v (total %d)", sceneIDs(scenes), total)
+	}
+	last := scenes[len(scenes)-1]
+	page.After = &repository.Cursor{Value: last.Name, ID: last.ID}
+	scenes, _, _ = repos.scenes.List(ctx, repository.SceneFilter{}, page)
+	if !equalIDs(sceneIDs(scenes), []uint{b.ID, a.ID}) {
+		t.Errorf("name desc after cursor = %v", sceneIDs(scenes))
+	}
+
+	scenes, _, _ = repos.scenes.List(ctx, repository.SceneFilter{}, repository.PageRequest{Offset: 3, Limit: 2})
+	if !equalIDs(sceneIDs(scenes), []uint{d.ID}) {
+		t.Errorf("offset 3 = %v", sceneIDs(scenes))
+	}
+}
+
+func testSentenceContract(t *testing.T, repos repositories) {
+	ctx := context.Background()
+	scene := mustCreateScene(t, repos, "日常生活", "")
+
+	err := repos.sentences.Create(ctx, &model.Sentence{SceneID: scene.ID + 100, Content: "orphan"})
+	if !errors.Is(err, repository.ErrForeignKey) {
+		t.Errorf("create with missing scene: err = %v, want ErrForeignKey", err)
+	}
+
+	sentence := mustCreateSentence(t, repos, &model.Sentence{SceneID: scene.ID, Content: "Hello", Translation: "你好"})
+	got, err := repos.sentences.GetByID(ctx, sentence.ID)
+	if err != nil {
+		t.Fatalf("get by id: %v", err)
+	}
+	if got.Content != "Hello" || got.Translation != "你好" || got.Language != "en" || got.Difficulty != "easy" {
+		t.Errorf("get by id = %+v, want column defaults en/easy", got)
+	}
+
+	second := mustCreateSentence(t, repos, &model.Sentence{SceneID: scene.ID, Language: "ja", Content: "こんにちは", Difficulty: "medium"})
+	other := mustCreateScene(t, repos, "旅游出行", "")
+	third := mustCreateSentence(t, repos, &model.Sentence{SceneID: other.ID, Content: "Where is the station?"})
+
+	bySceneID, err := repos.sentences.GetBySceneID(ctx, scene.ID)
+	if err != nil || !equalIDs(sentenceIDs(bySceneID), []uint{sentence.ID, second.ID}) {
+		t.Errorf("get by scene = %v, %v", sentenceIDs(bySceneID), err)
+	}
+	if count, err := repos.sentences.CountBySceneID(ctx, scene.ID); err != nil || count != 2 {
+		t.Errorf("count by scene = %d, %v", count, err)
+	}
+
+	byIDs, err := repos.sentences.GetByIDs(ctx, []uint{third.ID, sentence.ID, third.ID + 100})
+	if err != nil || len(byIDs) != 2 {
+		t.Errorf("get by ids = %v, %v", sentenceIDs(byIDs), err)
+	}
+	if empty, err := repos.sentences.GetByIDs(ctx, nil); err != nil || len(empty) != 0 {
+		t.Errorf("get by no ids = %v, %v", sentenceIDs(empty), err)
+	}
+
+	got.SceneID = other.ID
+	got.Content = "Hello!"
+	if err := repos.sentences.Update(ctx, got); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if updated, _ := repos.sentences.GetByID(ctx, sentence.ID); updated.Content != "Hello!" || updated.SceneID != other.ID {
+		t.Errorf("after update = %+v", updated)
+	}
+
+	if err := repos.sentences.Delete(ctx, second.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := repos.sentences.GetByID(ctx, second.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("get deleted sentence: err = %v, want ErrNotFound", err)
+	}
+	if err := repos.sentences.Delete(ctx, second.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("delete twice: err = %v, want ErrNotFound", err)
+	}
+	if count, _ := repos.sentences.CountBySceneID(ctx, scene.ID); count != 0 {
+		t.Errorf("count after move and delete = %d, want 0", count)
+	}
+	all, _ := repos.sentences.GetAll(ctx)
+	if !equalIDs(sentenceIDs(all), []uint{sentence.ID, third.ID}) {
+		t.Errorf("get all = %v", sentenceIDs(all))
+	}
+}
+
+func testSentenceListContract(t *testing.T, repos repositories) {
+	ctx := context.Background()
+	daily := mustCreateScene(t, repos, "日常生活", "")
+	travel := mustCreateScene(t, repos, "旅游出行", "")
+
+	s1 := mustCreateSentence(t, repos, &model.Sentence{SceneID: daily.ID, Content: "Good morning", Translation: "早上好", AudioURL: "/a/1"})
+	s2 := mustCreateSentence(t, repos, &model.Sentence{SceneID: daily.ID, Content: "Good night", Translation: "晚安", Difficulty: "medium"})
+	s3 := mustCreateSentence(t, repos, &model.Sentence{SceneID: travel.ID, Language: "ja", Content: "駅はどこですか", Translation: "车站在哪里"})
+	s4 := mustCreateSentence(t, repos, &model.Sentence{SceneID: travel.ID, Content: "Where is the STATION?", Translation: "车站在哪里？", AudioURL: "/a/4"})
+	deleted := mustCreateSentence(t, repos, &model.Sentence{SceneID: travel.ID, Content: "deleted station"})
+	if err := repos.sentences.Delete(ctx, deleted.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	yes, no := true, false
+	cases := []struct {
+		name   string
+		filter repository.SentenceFilter
+		want   []uint
+	}{
+		{"all", repository.SentenceFilter{}, []uint{s1.ID, s2.ID, s3.ID, s4.ID}},
+		{"scene", repository.SentenceFilter{SceneID: travel.ID}, []uint{s3.ID, s4.ID}},
+		{"difficulty", repository.SentenceFilter{Difficulty: "medium"}, []uint{s2.ID}},
+		{"language", repository.SentenceFilter{Language: "ja"}, []uint{s3.ID}},
+		{"query content ignores case", repository.SentenceFilter{Query: "station"}, []uint{s4.ID}},
+		{"query translation", repository.SentenceFilter{Query: "车站"}, []uint{s3.ID, s4.ID}},
+		{"has audio", repository.SentenceFilter{HasAudio: &yes}, []uint{s1.ID, s4.ID}},
+		{"no audio", repository.SentenceFilter{HasAudio: &no}, []uint{s2.ID, s3.ID}},
+		{"combined", repository.SentenceFilter{SceneID: daily.ID, Query: "good", HasAudio: &no}, []uint{s2.ID}},
+	}
+	for _, tc := range cases {
+		sentences, total, err := repos.sentences.List(ctx, tc.filter, repository.PageRequest{})
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if total != int64(len(tc.want)) || !equalIDs(sentenceIDs(sentences), tc.want) {
+			t.Errorf("%s: got %v (total %d), want %v", tc.name, sentenceIDs(sentences), total, tc.want)
+		}
+	}
+
+	sentences, total, _ := repos.sentences.List(ctx, repository.SentenceFilter{}, repository.PageRequest{Desc: true, Limit: 3, Offset: 1})
+	if total != 4 || !equalIDs(sentenceIDs(sentences), []uint{s3.ID, s2.ID, s1.ID}) {
+		t.Errorf("id desc offset 1 = %v (total %d)", sentenceIDs(sentences), total)
+	}
+
+	page := repository.PageRequest{Limit: 2, After: &repository.Cursor{ID: s2.ID}}
+	sentences, _, _ = repos.sentences.List(ctx, repository.SentenceFilter{}, page)
+	if !equalIDs(sentenceIDs(sentences), []uint{s3.ID, s4.ID}) {
+		t.Errorf("id cursor after %d = %v", s2.ID, sentenceIDs(sentences))
+	}
+
+	// 时间列游标：逐页取完，不丢失不重复
+	var seen []uint
+	page = repository.PageRequest{SortBy: "created_at", Desc: true, Limit: 3}
+	for i := 0; i < 5; i++ {
+		sentences, _, err := repos.sentences.List(ctx, repository.SentenceFilter{}, page)
+		if err != nil {
+			t.Fatalf("created_at page: %v", err)
+		}
+		seen = append(seen, sentenceIDs(sentences)...)
+		if len(sentences) < page.Limit {
+			break
+		}
+		last := sentences[len(sentences)-1]
+		page.After = &repository.Cursor{Value: last.CreatedAt, ID: last.ID}
+	}
+	if len(seen) != 4 {
+		t.Errorf("created_at pages = %v, want 4 distinct sentences", seen)
+	}
+	unique := make(map[uint]bool)
+	for _, id := range seen {
+		unique[id] = true
+	}
+	if len(unique) != len(seen) {
+		t.Errorf("created_at pages returned duplicates: %v", seen)
+	}
+}
+
+func testProgressContract(t *testing.T, repos repositories) {
+	ctx := context.Background()
+	scene := mustCreateScene(t, repos, "日常生活", "")
+	s1 := mustCreateSentence(t, repos, &model.Sentence{SceneID: scene.ID, Content: "one"})
+	s2 := mustCreateSentence(t, repos, &model.Sentence{SceneID: scene.ID, Content: "two"})
+
+	err := repos.progress.Create(ctx, &model.UserProgress{UserID: "u1", SentenceID: s2.ID + 100})
+	if !errors.Is(err, repository.ErrForeignKey) {
+		t.Errorf("create with missing sentence: err = %v, want ErrForeignKey", err)
+	}
+
+	now := time.Now().Truncate(time.Second)
+	p1 := &model.UserProgress{UserID: "u1", SentenceID: s1.ID, Attempts: 1, LastAttempt: now}
+	if err := repos.progress.Create(ctx, p1); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	p2 := &model.UserProgress{UserID: "u1", SentenceID: s2.ID, Attempts: 2, Completed: true, LastAttempt: now}
+	if err := repos.progress.Create(ctx, p2); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := repos.progress.Create(ctx, &model.UserProgress{UserID: "u2", SentenceID: s1.ID, LastAttempt: now}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	got, err := repos.progress.GetByUserAndSentence(ctx, "u1", s2.ID)
+	if err != nil {
+		t.Fatalf("get by user and sentence: %v", err)
+	}
+	if got.ID != p2.ID || got.Attempts != 2 || !got.Completed || !got.LastAttempt.Equal(now) {
+		t.Errorf("get by user and sentence = %+v", got)
+	}
+	if _, err := repos.progress.GetByUserAndSentence(ctx, "u2", s2.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("get missing progress: err = %v, want ErrNotFound", err)
+	}
+
+	got.Attempts = 3
+	if err := repos.progress.Update(ctx, got); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if updated, _ := repos.progress.GetByID(ctx, p2.ID); updated.Attempts != 3 {
+		t.Errorf("after update attempts = %d, want 3", updated.Attempts)
+	}
+
+	if err := repos.progress.Delete(ctx, p1.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := repos.progress.Delete(ctx, p1.ID); err != nil {
+		t.Errorf("delete twice: %v, want nil", err)
+	}
+	if _, err := repos.progress.GetByID(ctx, p1.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("get deleted progress: err = %v, want ErrNotFound", err)
+	}
+	list, err := repos.progress.GetByUserID(ctx, "u1")
+	if err != nil || len(list) != 1 || list[0].ID != p2.ID {
+		t.Errorf("get by user = %+v, %v", list, err)
+	}
+}
+
+func testProgressRecordAttemptContract(t *testing.T, repos repositories) {
+	ctx := context.Background()
+	scene := mustCreateScene(t, repos, "日常生活", "")
+	s1 := mustCreateSentence(t, repos, &model.Sentence{SceneID: scene.ID, Content: "one"})
+	s2 := mustCreateSentence(t, repos, &model.Sentence{SceneID: scene.ID, Content: "two"})
+
+	record := func(sentenceID uint, completed bool, at time.Time) {
+		t.Helper()
+		err := repos.progress.RecordAttempt(ctx, &model.UserProgress{
+			UserID: "u1", SentenceID: sentenceID, Completed: completed, Attempts: 1, LastAttempt: at,
+		})
+		if err != nil {
+			t.Fatalf("record attempt: %v", err)
+		}
+	}
+
+	first := time.Now().Truncate(time.Second)
+	record(s1.ID, false, first)
+	record(s1.ID, true, first.Add(time.Minute))
+	got, err := repos.progress.GetByUserAndSentence(ctx, "u1", s1.ID)
+	if err != nil {
+		t.Fatalf("get by user and sentence: %v", err)
+	}
+	if got.Attempts != 2 || !got.Completed || !got.LastAttempt.Equal(first.Add(time.Minute)) {
+		t.Errorf("after two attempts = %+v", got)
+	}
+
+	// 删除后再次练习从 1 重新计数
+	if err := repos.progress.Delete(ctx, got.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	record(s1.ID, false, first.Add(2*time.Minute))
+	if revived, err := repos.progress.GetByUserAndSentence(ctx, "u1", s1.ID); err != nil || revived.Attempts != 1 || revived.Completed {
+		t.Errorf("after delete and record = %+v, %v", revived, err)
+	}
+
+	const workers = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- repos.progress.RecordAttempt(ctx, &model.UserProgress{UserID: "u1", SentenceID: s2.ID, Attempts: 1, LastAttempt: first})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("concurrent record attempt: %v", err)
+		}
+	}
+	list, _ := repos.progress.GetByUserID(ctx, "u1")
+	if len(list) != 2 {
+		t.Fatalf("u1 has %d records, want 2", len(list))
+	}
+	if concurrent, _ := repos.progress.GetByUserAndSentence(ctx, "u1", s2.ID); concurrent.Attempts != workers {
+		t.Errorf("concurrent attempts = %d, want %d", concurrent.Attempts, workers)
+	}
+}
+
+func testProgressReassignContract(t *testing.T, repos repositories) {
+	ctx := context.Background()
+	scene := mustCreateScene(t, repos, "日常生活", "")
+	s1 := mustCreateSentence(t, repos, &model.Sentence{SceneID: scene.ID, Content: "one"})
+	s2 := mustCreateSentence(t, repos, &model.Sentence{SceneID: scene.ID, Content: "two"})
+
+	create := func(userID string, sentenceID uint, attempts int) {
+		t.Helper()
+		if err := repos.progress.Create(ctx, &model.UserProgress{UserID: userID, SentenceID: sentenceID, Attempts: attempts, LastAttempt: time.Now()}); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+	}
+	create("anonymous", s1.ID, 1)
+	create("anonymous", s2.ID, 1)
+	create("user", s2.ID, 5)
+	// 目标用户已删除的记录不阻止转移
+	create("user", s1.ID, 3)
+	if deleted, err := repos.progress.GetByUserAndSentence(ctx, "user", s1.ID); err != nil || repos.progress.Delete(ctx, deleted.ID) != nil {
+		t.Fatalf("delete target progress: %v", err)
+	}
+
+	moved, err := repos.progress.ReassignUser(ctx, "anonymous", "user")
+	if err != nil {
+		t.Fatalf("reassign: %v", err)
+	}
+	if moved != 1 {
+		t.Errorf("moved = %d, want 1", moved)
+	}
+	if left, _ := repos.progress.GetByUserID(ctx, "anonymous"); len(left) != 0 {
+		t.Errorf("anonymous still has %d records", len(left))
+	}
+	owned, _ := repos.progress.GetByUserID(ctx, "user")
+	if len(owned) != 2 {
+		t.Fatalf("user has %d records, want 2", len(owned))
+	}
+	// 冲突时保留目标用户原有的记录
+	if kept, _ := repos.progress.GetByUserAndSentence(ctx, "user", s2.ID); kept.Attempts != 5 {
+		t.Errorf("conflicting record attempts = %d, want target's 5", kept.Attempts)
+	}
+}
+
+func testProgressSummaryContract(t *testing.T, repos repositories) {
+	ctx := context.Background()
+	scene := mustCreateScene(t, repos, "日常生活", "")
+	s1 := mustCreateSentence(t, repos, &model.Sentence{SceneID: scene.ID, Content: "one"})
+	s2 := mustCreateSentence(t, repos, &model.Sentence{SceneID: scene.ID, Content: "two"})
+
+	for _, p := range []*model.UserProgress{
+		{UserID: "bob", SentenceID: s1.ID, Completed: true},
+		{UserID: "alice", SentenceID: s1.ID, Completed: true},
+		{UserID: "alice", SentenceID: s2.ID},
+		{UserID: "carol", SentenceID: s2.ID, Completed: true},
+	} {
+		p.LastAttempt = time.Now()
+		if err := repos.progress.Create(ctx, p); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+	}
+	// 已删除的记录不计入
+	carol, _ := repos.progress.GetByUserAndSentence(ctx, "carol", s2.ID)
+	if err := repos.progress.Delete(ctx, carol.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	summaries, err := repos.progress.SummarizeByUser(ctx)
+	if err != nil {
+		t.Fatalf("summarize: %v", err)
+	}
+	want := []repository.UserProgressSummary{
+		{UserID: "alice", Practiced: 2, Completed: 1},
+		{UserID: "bob", Practiced: 1, Completed: 1},
+	}
+	if len(summaries) != len(want) {
+		t.Fatalf("summaries = %+v, want %+v", summaries, want)
+	}
+	for i := range want {
+		if summaries[i] != want[i] {
+			t.Errorf("summaries[%d] = %+v, want %+v", i, summaries[i], want[i])
+		}
+	}
+}
+
+func testProgressCompletionContract(t *testing.T, repos repositories) {
+	ctx := context.Background()
+	daily := mustCreateScene(t, repos, "日常生活", "")
+	travel := mustCreateScene(t, repos, "旅行", "")
+	s1 := mustCreateSentence(t, repos, &model.Sentence{SceneID: daily.ID, Content: "one", Difficulty: "easy"})
+	s2 := mustCreateSentence(t, repos, &model.Sentence{SceneID: daily.ID, Content: "two", Difficulty: "easy"})
+	s3 := mustCreateSentence(t, repos, &model.Sentence{SceneID: daily.ID, Content: "three", Difficulty: "hard"})
+	s4 := mustCreateSentence(t, repos, &model.Sentence{SceneID: travel.ID, Content: "four", Difficulty: "easy"})
+	deleted := mustCreateSentence(t, repos, &model.Sentence{SceneID: travel.ID, Content: "five", Difficulty: "easy"})
+
+	for _, p := range []*model.UserProgress{
+		{UserID: "alice", SentenceID: s1.ID, Completed: true},
+		{UserID: "alice", SentenceID: s2.ID},
+		{UserID: "alice", SentenceID: s4.ID, Completed: true},
+		{UserID: "alice", SentenceID: deleted.ID, Completed: true},
+		{UserID: "bob", SentenceID: s3.ID, Completed: true},
+	} {
+		p.LastAttempt = time.Now()
+		if err := repos.progress.Create(ctx, p); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+	}
+	// 已删除的进度和句子都不计入
+	p2, _ := repos.progress.GetByUserAndSentence(ctx, "alice", s2.ID)
+	if err := repos.progress.Delete(ctx, p2.ID); err != nil {
+		t.Fatalf("delete progress: %v", err)
+	}
+	if err := repos.sentences.Delete(ctx, deleted.ID); err != nil {
+		t.Fatalf("delete sentence: %v", err)
+	}
+
+	groups, err := repos.progress.SummarizeCompletion(ctx, "alice")
+	if err != nil {
+		t.Fatalf("summarize: %v", err)
+	}
+	want := []repository.CompletionGroup{
+		{SceneID: daily.ID, Difficulty: "easy", Total: 2, Practiced: 1, Completed: 1},
+		{SceneID: daily.ID, Difficulty: "hard", Total: 1},
+		{SceneID: travel.ID, Difficulty: "easy", Total: 1, Practiced: 1, Completed: 1},
+	}
+	if len(groups) != len(want) {
+		t.Fatalf("groups = %+v, want %+v", groups, want)
+	}
+	for i := range want {
+		if groups[i] != want[i] {
+			t.Errorf("groups[%d] = %+v, want %+v", i, groups[i], want[i])
+		}
+	}
+}
+
+func testTxContract(t *testing.T, repos repositories) {
+	ctx := context.Background()
+	scene := mustCreateScene(t, repos, "日常生活", "")
+
+	// write 在同一事务内创建句子和进度，并确认事务内能读到自己的写入
+	write := func(ctx context.Context, content string) error {
+		sentence := &model.Sentence{SceneID: scene.ID, Content: content}
+		if err := repos.sentences.Create(ctx, sentence); err != nil {
+			return err
+		}
+		if err := repos.progress.RecordAttempt(ctx, &model.UserProgress{UserID: "u1", SentenceID: sentence.ID, Attempts: 1, LastAttempt: time.Now()}); err != nil {
+			return err
+		}
+		if _, err := repos.progress.GetByUserAndSentence(ctx, "u1", sentence.ID); err != nil {
+			return err
+		}
+		return nil
+	}
+	count := func() (int, int) {
+		t.Helper()
+		sentences, err := repos.sentences.GetBySceneID(ctx, scene.ID)
+		if err != nil {
+			t.Fatalf("get sentences: %v", err)
+		}
+		progress, err := repos.progress.GetByUserID(ctx, "u1")
+		if err != nil {
+			t.Fatalf("get progress: %v", err)
+		}
+		return len(sentences), len(progress)
+	}
+
+	if err := repos.tx.WithinTx(ctx, func(ctx context.Context) error { return write(ctx, "committed") }); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+	if sentences, progress := count(); sentences != 1 || progress != 1 {
+		t.Fatalf("after commit: %d sentences, %d progress, want 1 and 1", sentences, progress)
+	}
+
+	errAbort := errors.New("abort")
+	err := repos.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := write(ctx, "rolled back"); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Errorf("rollback: err = %v, want errAbort", err)
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("panic inside WithinTx was swallowed")
+			}
+		}()
+		repos.tx.WithinTx(ctx, func(ctx context.Context) error {
+			if err := write(ctx, "panicked"); err != nil {
+				return err
+			}
+			panic("boom")
+		})
+	}()
+
+	// 嵌套调用加入外层事务，内层的写入随外层一起回滚
+	err = repos.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := repos.tx.WithinTx(ctx, func(ctx context.Context) error { return write(ctx, "nested") }); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Errorf("nested rollback: err = %v, want errAbort", err)
+	}
+
+	if sentences, progress := count(); sentences != 1 || progress != 1 {
+		t.Errorf("after rollbacks: %d sentences, %d progress, want 1 and 1", sentences, progress)
+	}
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// translateError 将 GORM 约束错误转换为仓储层错误，其余错误原样返回
+func translateError(err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return ErrDuplicateKey
+	case errors.Is(err, gorm.ErrForeignKeyViolated):
+		return ErrForeignKey
+	default:
+		return err
+	}
+}
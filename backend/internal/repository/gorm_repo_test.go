@@ -0,0 +1,431 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"voicewriter/internal/config"
+	"voicewriter/internal/database"
+	"voicewriter/internal/model"
+	"voicewriter/internal/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestDB 创建执行过全部迁移的 SQLite 内存数据库
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := database.NewDatabase(&config.DatabaseConfig{Driver: database.DriverSQLite, Path: ":memory:"})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	db = db.Session(&gorm.Session{Logger: logger.Default.LogMode(logger.Silent)})
+
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		t.Fatalf("load migrations: %v", err)
+	}
+	if _, err := migrator.Up(context.Background(), 0); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+func TestGormRepositoryContract(t *testing.T) {
+	runContract(t, func(t *testing.T) repositories {
+		db := newTestDB(t)
+		return repositories{
+			scenes:    repository.NewSceneRepository(db),
+			sentences: repository.NewSentenceRepository(db),
+			progress:  repository.NewProgressRepository(db),
+			tx:        repository.NewTxManager(db),
+		}
+	})
+}
+
+func TestVocabularyRepository(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	scenes := repository.NewSceneRepository(db)
+	sentences := repository.NewSentenceRepository(db)
+	vocabulary := repository.NewVocabularyRepository(db)
+
+	scene := &model.Scene{Name: "daily"}
+	if err := scenes.Create(ctx, scene); err != nil {
+		t.Fatal(err)
+	}
+	s1 := &model.Sentence{SceneID: scene.ID, Language: "en", Content: "How are you?"}
+	s2 := &model.Sentence{SceneID: scene.ID, Language: "en", Content: "Are you ready?"}
+	for _, s := range []*model.Sentence{s1, s2} {
+		if err := sentences.Create(ctx, s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	earlier := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	later := earlier.Add(30 * time.Minute)
+	if err := vocabulary.RecordMisses(ctx, "u1", "en", s1.ID, []string{"how", "you"}, earlier); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	if err := vocabulary.RecordMisses(ctx, "u1", "en", s2.ID, []string{"you"}, later); err != nil {
+		t.Fatalf("record again: %v", err)
+	}
+
+	notArchived := false
+	entries, total, err := vocabulary.List(ctx, repository.VocabularyFilter{UserID: "u1", Archived: &notArchived},
+		repository.PageRequest{SortBy: "miss_count", Desc: true, Limit: 10})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if total != 2 || entries[0].Word != "you" || entries[0].MissCount != 2 || entries[0].SentenceID != s2.ID {
+		t.Fatalf("entries = %+v", entries)
+	}
+	if entries[0].Sentence == nil || entries[0].Sentence.Content != s2.Content || !entries[0].LastSeenAt.Equal(later) {
+		t.Errorf("weakest entry = %+v", entries[0])
+	}
+
+	// 归档后再次写错会取消归档
+	you := entries[0]
+	you.Archived = true
+	if err := vocabulary.Update(ctx, you); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if err := vocabulary.RecordMisses(ctx, "u1", "en", s1.ID, []string{"you"}, later); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := vocabulary.GetByID(ctx, you.ID); err != nil || got.Archived || got.MissCount != 3 {
+		t.Errorf("after another miss = %+v, %v", got, err)
+	}
+
+	// 转给已有同一个词的用户时合并次数
+	if err := vocabulary.RecordMisses(ctx, "u2", "en", s2.ID, []string{"you", "ready"}, earlier); err != nil {
+		t.Fatal(err)
+	}
+	moved, err := vocabulary.ReassignUser(ctx, "u1", "u2")
+	if err != nil || moved != 2 {
+		t.Fatalf("reassign = %d, %v", moved, err)
+	}
+	entries, total, err = vocabulary.List(ctx, repository.VocabularyFilter{UserID: "u2"}, repository.PageRequest{SortBy: "word"})
+	if err != nil || total != 3 {
+		t.Fatalf("u2 vocabulary = %+v, %v", entries, err)
+	}
+	for _, entry := range entries {
+		if entry.Word == "you" && (entry.MissCount != 4 || entry.SentenceID != s1.ID) {
+			t.Errorf("merged entry = %+v", entry)
+		}
+	}
+	if _, total, _ := vocabulary.List(ctx, repository.VocabularyFilter{UserID: "u1"}, repository.PageRequest{}); total != 0 {
+		t.Errorf("u1 still has %d entries", total)
+	}
+
+	// 转走的记录软删除后仍占用唯一索引，再次写错时按新记录恢复
+	if err := vocabulary.RecordMisses(ctx, "u1", "en", s1.ID, []string{"you"}, later); err != nil {
+		t.Fatalf("record after soft delete: %v", err)
+	}
+	entries, total, err = vocabulary.List(ctx, repository.VocabularyFilter{UserID: "u1"}, repository.PageRequest{})
+	if err != nil || total != 1 || entries[0].MissCount != 1 || entries[0].Starred {
+		t.Fatalf("revived entries = %+v, %v", entries, err)
+	}
+
+	// 目标用户已软删除的同一个词不阻止转移
+	if err := vocabulary.RecordMisses(ctx, "u3", "en", s1.ID, []string{"you"}, earlier); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Where("user_id = ?", "u3").Delete(&model.VocabularyEntry{}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if moved, err := vocabulary.ReassignUser(ctx, "u1", "u3"); err != nil || moved != 1 {
+		t.Fatalf("reassign onto soft-deleted = %d, %v", moved, err)
+	}
+	if entries, total, _ := vocabulary.List(ctx, repository.VocabularyFilter{UserID: "u3"}, repository.PageRequest{}); total != 1 || entries[0].MissCount != 1 {
+		t.Errorf("u3 vocabulary = %+v", entries)
+	}
+}
+
+func TestReviewRepositoryReassignUser(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	scenes := repository.NewSceneRepository(db)
+	sentences := repository.NewSentenceRepository(db)
+	reviews := repository.NewReviewRepository(db)
+
+	scene := &model.Scene{Name: "daily"}
+	if err := scenes.Create(ctx, scene); err != nil {
+		t.Fatal(err)
+	}
+	var ids []uint
+	for _, content := range []string{"One.", "Two.", "Three."} {
+		s := &model.Sentence{SceneID: scene.ID, Language: "en", Content: content}
+		if err := sentences.Create(ctx, s); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, s.ID)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	for _, state := range []*model.ReviewState{
+		{UserID: "anonymous", SentenceID: ids[0], Repetitions: 3, DueAt: now},
+		{UserID: "anonymous", SentenceID: ids[1], Repetitions: 3, DueAt: now},
+		{UserID: "user", SentenceID: ids[1], Repetitions: 1, DueAt: now},
+		{UserID: "user", SentenceID: ids[2], Repetitions: 1, DueAt: now},
+	} {
+		if err := reviews.Create(ctx, state); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 目标用户软删除的记录仍占用 (user_id, sentence_id) 唯一索引
+	if err := db.Where("user_id = ? AND sentence_id = ?", "user", ids[2]).Delete(&model.ReviewState{}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Model(&model.ReviewState{}).Where("user_id = ? AND sentence_id = ?", "anonymous", ids[1]).Update("sentence_id", ids[2]).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	moved, err := reviews.ReassignUser(ctx, "anonymous", "user")
+	if err != nil || moved != 2 {
+		t.Fatalf("reassign = %d, %v; want 2", moved, err)
+	}
+	for i, want := range []int{3, 1, 3} {
+		state, err := reviews.GetByUserAndSentence(ctx, "user", ids[i])
+		if err != nil || state.Repetitions != want {
+			t.Errorf("sentence %d state = %+v, %v; want %d repetitions", i, state, err, want)
+		}
+	}
+	var left int64
+	if err := db.Model(&model.ReviewState{}).Where("user_id = ?", "anonymous").Count(&left).Error; err != nil || left != 0 {
+		t.Errorf("anonymous still has %d review states, %v", left, err)
+	}
+}
+
+func TestSessionRepository(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	scenes := repository.NewSceneRepository(db)
+	sentences := repository.NewSentenceRepository(db)
+	sessions := repository.NewSessionRepository(db)
+
+	scene := &model.Scene{Name: "daily"}
+	if err := scenes.Create(ctx, scene); err != nil {
+		t.Fatal(err)
+	}
+	s1 := &model.Sentence{SceneID: scene.ID, Language: "en", Content: "How are you?"}
+	s2 := &model.Sentence{SceneID: scene.ID, Language: "en", Content: "Are you ready?"}
+	for _, s := range []*model.Sentence{s1, s2} {
+		if err := sentences.Create(ctx, s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 句子顺序与主键顺序相反，读取时按 position 排列
+	session := &model.PracticeSession{
+		UserID: "u1", Mode: model.SessionModeScene, SceneID: &scene.ID, Status: model.SessionStatusActive, StartedAt: time.Now(), Total: 2,
+		Items: []*model.PracticeSessionItem{{Position: 0, SentenceID: s2.ID}, {Position: 1, SentenceID: s1.ID}},
+	}
+	if err := sessions.Create(ctx, session); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	got, err := sessions.GetByID(ctx, session.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if len(got.Items) != 2 || got.Items[0].SentenceID != s2.ID || got.Items[0].Sentence == nil || got.Items[0].Sentence.Content != s2.Content {
+		t.Fatalf("items = %+v", got.Items)
+	}
+
+	now := time.Now()
+	item := got.Items[1]
+	item.Attempts, item.Score, item.AnsweredAt = 1, 80, &now
+	if err := sessions.UpdateItem(ctx, item); err != nil {
+		t.Fatalf("update item: %v", err)
+	}
+	if active, err := sessions.LockActive(ctx, session.ID); err != nil || !active {
+		t.Errorf("lock active session = %v, %v", active, err)
+	}
+
+	got.Status = model.SessionStatusFinished
+	got.FinishedAt = &now
+	if err := sessions.Update(ctx, got); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if active, err := sessions.LockActive(ctx, session.ID); err != nil || active {
+		t.Errorf("lock finished session = %v, %v, want false", active, err)
+	}
+	if got, _ := sessions.GetByID(ctx, session.ID); got.Items[1].Attempts != 1 || got.Items[1].AnsweredAt == nil || got.FinishedAt == nil {
+		t.Errorf("after updates = %+v, item = %+v", got, got.Items[1])
+	}
+
+	list, total, err := sessions.List(ctx, repository.SessionFilter{UserID: "u1", Status: model.SessionStatusFinished}, repository.PageRequest{Limit: 10})
+	if err != nil || total != 1 || len(list) != 1 || list[0].Items != nil {
+		t.Errorf("list finished = %+v, %d, %v", list, total, err)
+	}
+	if _, total, _ := sessions.List(ctx, repository.SessionFilter{UserID: "u1", Status: model.SessionStatusActive}, repository.PageRequest{}); total != 0 {
+		t.Errorf("active total = %d, want 0", total)
+	}
+	if _, err := sessions.GetByID(ctx, session.ID+1); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("get missing: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestAnonymousRepository(t *testing.T) {
+	ctx := context.Background()
+	anonymous := repository.NewAnonymousRepository(newTestDB(t))
+
+	if err := anonymous.Create(ctx, &model.AnonymousID{AnonymousID: "anon-1", SecretHash: "hash"}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := anonymous.Create(ctx, &model.AnonymousID{AnonymousID: "anon-1", SecretHash: "other"}); !errors.Is(err, repository.ErrDuplicateKey) {
+		t.Errorf("create duplicate: err = %v, want ErrDuplicateKey", err)
+	}
+	if _, err := anonymous.GetByAnonymousID(ctx, "anon-2"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("get missing: err = %v, want ErrNotFound", err)
+	}
+
+	// 只有第一次认领能成功
+	now := time.Now()
+	if claimed, err := anonymous.MarkClaimed(ctx, "anon-1", "u1", now); err != nil || !claimed {
+		t.Fatalf("first claim = %v, %v", claimed, err)
+	}
+	if claimed, err := anonymous.MarkClaimed(ctx, "anon-1", "u2", now); err != nil || claimed {
+		t.Errorf("second claim = %v, %v, want false", claimed, err)
+	}
+	got, err := anonymous.GetByAnonymousID(ctx, "anon-1")
+	if err != nil || got.ClaimedBy == nil || *got.ClaimedBy != "u1" || got.ClaimedAt == nil || got.SecretHash != "hash" {
+		t.Errorf("after claim = %+v, %v", got, err)
+	}
+}
+
+func TestAttemptActivity(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	scenes := repository.NewSceneRepository(db)
+	sentences := repository.NewSentenceRepository(db)
+	attempts := repository.NewAttemptRepository(db)
+
+	scene := &model.Scene{Name: "daily"}
+	if err := scenes.Create(ctx, scene); err != nil {
+		t.Fatal(err)
+	}
+	sentence := &model.Sentence{SceneID: scene.ID, Content: "How are you?"}
+	if err := sentences.Create(ctx, sentence); err != nil {
+		t.Fatal(err)
+	}
+
+	// 写入时带时区，汇总按 UTC 的 Unix 时间分段；未评分的作答只计次数和时长
+	shanghai := time.FixedZone("CST", 8*3600)
+	base := time.Date(2026, 3, 1, 8, 0, 0, 0, shanghai)
+	for _, a := range []struct {
+		userID string
+		at     time.Time
+		score  float64
+		graded bool
+	}{
+		{"u1", base.Add(time.Minute), 80, true},
+		{"u1", base.Add(10 * time.Minute), 0, false},
+		{"u1", base.Add(14 * time.Minute), 60, true},
+		{"u1", base.Add(15 * time.Minute), 100, true},
+		{"u1", base.Add(24 * time.Hour), 50, true},
+		{"u2", base, 10, true},
+		{"u3", base, 0, false},
+	} {
+		attempt := &model.Attempt{UserID: a.userID, SentenceID: sentence.ID, SceneID: scene.ID, Score: a.score, Graded: a.graded, DurationMs: 1000, CreatedAt: a.at}
+		if err := attempts.Create(ctx, attempt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	buckets, err := attempts.SummarizeActivity(ctx, "u1")
+	if err != nil {
+		t.Fatalf("summarize: %v", err)
+	}
+	start := base.Unix()
+	want := []repository.ActivityBucket{
+		{BucketStart: start, Attempts: 3, Graded: 2, ScoreSum: 140, DurationMs: 3000},
+		{BucketStart: start + repository.ActivityBucketSeconds, Attempts: 1, Graded: 1, ScoreSum: 100, DurationMs: 1000},
+		{BucketStart: start + 24*3600, Attempts: 1, Graded: 1, ScoreSum: 50, DurationMs: 1000},
+	}
+	if len(buckets) != len(want) {
+		t.Fatalf("buckets = %+v, want %+v", buckets, want)
+	}
+	for i := range want {
+		if buckets[i] != want[i] {
+			t.Errorf("buckets[%d] = %+v, want %+v", i, buckets[i], want[i])
+		}
+	}
+
+	summaries, err := attempts.SummarizeByUser(ctx)
+	if err != nil {
+		t.Fatalf("summarize by user: %v", err)
+	}
+	wantUsers := []repository.UserAttemptSummary{
+		{UserID: "u1", Attempts: 5, Graded: 4, AvgScore: 72.5, DurationMs: 5000},
+		{UserID: "u2", Attempts: 1, Graded: 1, AvgScore: 10, DurationMs: 1000},
+		{UserID: "u3", Attempts: 1, Graded: 0, AvgScore: 0, DurationMs: 1000},
+	}
+	if len(summaries) != len(wantUsers) {
+		t.Fatalf("summaries = %+v, want %+v", summaries, wantUsers)
+	}
+	for i := range wantUsers {
+		if summaries[i] != wantUsers[i] {
+			t.Errorf("summaries[%d] = %+v, want %+v", i, summaries[i], wantUsers[i])
+		}
+	}
+}
+
+func TestTranslationRepositorySoftDelete(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	scenes := repository.NewSceneRepository(db)
+	sentences := repository.NewSentenceRepository(db)
+	translations := repository.NewTranslationRepository(db)
+
+	scene := &model.Scene{Name: "daily"}
+	if err := scenes.Create(ctx, scene); err != nil {
+		t.Fatal(err)
+	}
+	sentence := &model.Sentence{SceneID: scene.ID, Content: "Good morning"}
+	if err := sentences.Create(ctx, sentence); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := translations.UpsertSentenceTranslation(ctx, &model.SentenceTranslation{SentenceID: sentence.ID, Locale: "ko", Text: "좋은 아침"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := translations.DeleteSentenceTranslation(ctx, sentence.ID, "ko"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := translations.DeleteSentenceTranslation(ctx, sentence.ID, "ko"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("delete again = %v, want ErrNotFound", err)
+	}
+	if got, err := translations.ListSentenceTranslations(ctx, []uint{sentence.ID}, nil); err != nil || len(got) != 0 {
+		t.Errorf("after delete = %+v, %v", got, err)
+	}
+
+	// 软删除的翻译仍占用唯一索引，重新写入时恢复并覆盖内容
+	if err := translations.UpsertSentenceTranslation(ctx, &model.SentenceTranslation{SentenceID: sentence.ID, Locale: "ko", Text: "안녕하세요"}); err != nil {
+		t.Fatalf("upsert after delete: %v", err)
+	}
+	if got, err := translations.ListSentenceTranslations(ctx, []uint{sentence.ID}, []string{"ko"}); err != nil || len(got) != 1 || got[0].Text != "안녕하세요" {
+		t.Errorf("revived sentence translation = %+v, %v", got, err)
+	}
+
+	if err := translations.UpsertSceneTranslation(ctx, &model.SceneTranslation{SceneID: scene.ID, Locale: "en", Name: "Daily"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := translations.DeleteSceneTranslation(ctx, scene.ID, "en"); err != nil {
+		t.Fatalf("delete scene translation: %v", err)
+	}
+	if err := translations.UpsertSceneTranslation(ctx, &model.SceneTranslation{SceneID: scene.ID, Locale: "en", Name: "Everyday"}); err != nil {
+		t.Fatalf("upsert scene translation after delete: %v", err)
+	}
+	if got, err := translations.ListSceneTranslations(ctx, []uint{scene.ID}, nil); err != nil || len(got) != 1 || got[0].Name != "Everyday" {
+		t.Errorf("revived scene translation = %+v, %v", got, err)
+	}
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"voicewriter/internal/model"
 )
@@ -12,27 +13,123 @@ var (
 	ErrNotFound = errors.New("record not found")
 	// ErrDuplicateKey 唯一键冲突错误
 	ErrDuplicateKey = errors.New("duplicate key")
+	// ErrForeignKey 外键约束错误（引用的记录不存在或仍被引用）
+	ErrForeignKey = errors.New("foreign key violation")
 )
 
+// TxManager 事务管理器：fn 收到的 ctx 携带事务，以该 ctx 调用的仓储方法都在同一事务内执行。
+// fn 返回错误或 panic 时回滚，否则提交；嵌套调用加入外层事务
+type TxManager interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// SceneFilter 场景查询条件，零值字段不参与过滤
+type SceneFilter struct {
+	Query string // 名称或描述包含的文本
+}
+
 // SceneRepository 场景仓储接口
 type SceneRepository interface {
 	Create(ctx context.Context, scene *model.Scene) error
 	GetByID(ctx context.Context, id uint) (*model.Scene, error)
+	GetByName(ctx context.Context, name string) (*model.Scene, error)
 	GetAll(ctx context.Context) ([]*model.Scene, error)
+	// List 按条件分页查询，同时返回满足条件的总数
+	List(ctx context.Context, filter SceneFilter, page PageRequest) ([]*model.Scene, int64, error)
 	Update(ctx context.Context, scene *model.Scene) error
 	Delete(ctx context.Context, id uint) error
 }
 
+// SentenceFilter 句子查询条件，零值字段不参与过滤
+type SentenceFilter struct {
+	SceneID    uint
+	Difficulty string
+	Language   string
+	Query      string // 原文或翻译包含的文本
+	HasAudio   *bool  // 是否设置了音频URL
+}
+
 // SentenceRepository 句子仓储接口
 type SentenceRepository interface {
 	Create(ctx context.Context, sentence *model.Sentence) error
 	GetByID(ctx context.Context, id uint) (*model.Sentence, error)
+	// GetByIDs 批量查询句子，不存在的ID忽略，返回顺序不保证
+	GetByIDs(ctx context.Context, ids []uint) ([]*model.Sentence, error)
 	GetAll(ctx context.Context) ([]*model.Sentence, error)
+	// List 按条件分页查询，同时返回满足条件的总数
+	List(ctx context.Context, filter SentenceFilter, page PageRequest) ([]*model.Sentence, int64, error)
 	GetBySceneID(ctx context.Context, sceneID uint) ([]*model.Sentence, error)
+	CountBySceneID(ctx context.Context, sceneID uint) (int64, error)
 	Update(ctx context.Context, sentence *model.Sentence) error
 	Delete(ctx context.Context, id uint) error
 }
 
+// SearchDocument 参与全文搜索的句子字段
+type SearchDocument struct {
+	ID           uint
+	SceneID      uint
+	Language     string
+	Content      string
+	Translation  string            // 句子自带的（中文）翻译
+	Translations map[string]string // 翻译表中其他语言的翻译，键为语言
+}
+
+// SearchQuery 全文搜索条件，SceneID 和 Language 为零值时不过滤
+type SearchQuery struct {
+	Text     string
+	SceneID  uint
+	Language string
+	Limit    int
+	Offset   int
+}
+
+// SearchHit 搜索命中的句子，Highlights 按字段名给出已转义并用 <em> 标记命中位置的文本，
+// 其他语言的翻译以 "translation.<语言>" 为键
+type SearchHit struct {
+	ID         uint
+	Score      float64
+	Highlights map[string]string
+}
+
+// SearchIndex 句子全文搜索索引接口
+type SearchIndex interface {
+	// Index 添加或替换句子索引
+	Index(ctx context.Context, docs ...SearchDocument) error
+	Delete(ctx context.Context, ids ...uint) error
+	// Search 查询同时命中所有词项的句子，按相关度降序分页返回，同时返回命中总数
+	Search(ctx context.Context, query SearchQuery) ([]SearchHit, int, error)
+}
+
+// TranslationRepository 句子和场景的多语言翻译仓储接口
+type TranslationRepository interface {
+	// ListSentenceTranslations 查询指定句子在指定语言下的翻译，locales 为空时返回全部语言
+	ListSentenceTranslations(ctx context.Context, sentenceIDs []uint, locales []string) ([]*model.SentenceTranslation, error)
+	// UpsertSentenceTranslation 按 (句子, 语言) 创建或覆盖翻译
+	UpsertSentenceTranslation(ctx context.Context, translation *model.SentenceTranslation) error
+	DeleteSentenceTranslation(ctx context.Context, sentenceID uint, locale string) error
+	// ListSceneTranslations 查询指定场景在指定语言下的名称和描述，locales 为空时返回全部语言
+	ListSceneTranslations(ctx context.Context, sceneIDs []uint, locales []string) ([]*model.SceneTranslation, error)
+	// UpsertSceneTranslation 按 (场景, 语言) 创建或覆盖名称和描述
+	UpsertSceneTranslation(ctx context.Context, translation *model.SceneTranslation) error
+	DeleteSceneTranslation(ctx context.Context, sceneID uint, locale string) error
+}
+
+// UserProgressSummary 单个用户的进度汇总
+type UserProgressSummary struct {
+	UserID    string
+	Practiced int64 // 练习过的句子数
+	Completed int64 // 已完成的句子数
+}
+
+// CompletionGroup 某个场景下某个难度的未删除句子数，以及用户练习过和已完成的句子数
+type CompletionGroup struct {
+	SceneID    uint
+	Difficulty string
+	Total      int64
+	Practiced  int64
+	Completed  int64
+}
+
 // ProgressRepository 用户进度仓储接口
 type ProgressRepository interface {
 	Create(ctx context.Context, progress *model.UserProgress) error
@@ -40,5 +137,133 @@ type ProgressRepository interface {
 	GetByUserID(ctx context.Context, userID string) ([]*model.UserProgress, error)
 	GetByUserAndSentence(ctx context.Context, userID string, sentenceID uint) (*model.UserProgress, error)
 	Update(ctx context.Context, progress *model.UserProgress) error
+	// RecordAttempt 原子地记录一次练习：记录不存在时按 progress 创建，已存在时作答次数加一并覆盖完成状态和练习时间，
+	// 已软删除的记录会恢复并从 1 重新计数
+	RecordAttempt(ctx context.Context, progress *model.UserProgress) error
 	Delete(ctx context.Context, id uint) error
+	// ReassignUser 将 fromUserID 的记录转给 toUserID，目标用户已有同一句子的记录时保留目标用户的
+	ReassignUser(ctx context.Context, fromUserID, toUserID string) (int64, error)
+	// SummarizeByUser 按用户汇总进度，用于统计全体用户的分布
+	SummarizeByUser(ctx context.Context) ([]UserProgressSummary, error)
+	// SummarizeCompletion 按 (场景, 难度) 汇总用户的完成情况，只统计未删除的句子
+	SummarizeCompletion(ctx context.Context, userID string) ([]CompletionGroup, error)
+}
+
+// AttemptFilter 作答记录查询条件，零值字段不参与过滤
+type AttemptFilter struct {
+	UserID     string
+	SentenceID uint
+	SceneID    uint
+	From       time.Time
+	To         time.Time
+	Limit      int
+}
+
+// UserAttemptSummary 单个用户的作答汇总，AvgScore 只计评过分的作答，没有时为 0
+type UserAttemptSummary struct {
+	UserID     string
+	Attempts   int64
+	Graded     int64
+	AvgScore   float64
+	DurationMs int64
+}
+
+// ActivityBucketSeconds 作答按时间段汇总的粒度。各时区与 UTC 的偏移都是 15 分钟的整数倍，
+// 同一时间段在任何时区都落在同一天，调用方可以按自己的时区把时间段归入日期
+const ActivityBucketSeconds = 15 * 60
+
+// ActivityBucket 一个时间段内的作答汇总，ScoreSum 只计评过分的作答
+type ActivityBucket struct {
+	BucketStart int64 // 时间段开始的 Unix 秒
+	Attempts    int64
+	Graded      int64
+	ScoreSum    float64
+	DurationMs  int64
+}
+
+// AttemptRepository 作答记录仓储接口
+type AttemptRepository interface {
+	Create(ctx context.Context, attempt *model.Attempt) error
+	List(ctx context.Context, filter AttemptFilter) ([]*model.Attempt, error)
+	ReassignUser(ctx context.Context, fromUserID, toUserID string) (int64, error)
+	// SummarizeByUser 按用户汇总作答次数、平均得分和练习时长
+	SummarizeByUser(ctx context.Context) ([]UserAttemptSummary, error)
+	// SummarizeActivity 按 ActivityBucketSeconds 长的时间段汇总用户的作答，按时间升序
+	SummarizeActivity(ctx context.Context, userID string) ([]ActivityBucket, error)
+}
+
+// ReviewRepository 间隔重复复习状态仓储接口
+type ReviewRepository interface {
+	Create(ctx context.Context, state *model.ReviewState) error
+	GetByUserAndSentence(ctx context.Context, userID string, sentenceID uint) (*model.ReviewState, error)
+	GetDue(ctx context.Context, userID string, now time.Time, limit int) ([]*model.ReviewState, error)
+	Update(ctx context.Context, state *model.ReviewState) error
+	ReassignUser(ctx context.Context, fromUserID, toUserID string) (int64, error)
+}
+
+// VocabularyFilter 生词查询条件，零值字段不参与过滤
+type VocabularyFilter struct {
+	UserID   string
+	Language string
+	Query    string // 词包含的文本
+	Starred  *bool
+	Archived *bool
+}
+
+// VocabularyRepository 生词本仓储接口
+type VocabularyRepository interface {
+	// RecordMisses 按 (用户, 语言, 词) 创建或累加出错次数，同时更新来源句子和出错时间并取消归档
+	// words 需已规范化并去重
+	RecordMisses(ctx context.Context, userID, language string, sentenceID uint, words []string, at time.Time) error
+	GetByID(ctx context.Context, id uint) (*model.VocabularyEntry, error)
+	// List 按条件分页查询并加载来源句子，同时返回满足条件的总数
+	List(ctx context.Context, filter VocabularyFilter, page PageRequest) ([]*model.VocabularyEntry, int64, error)
+	Update(ctx context.Context, entry *model.VocabularyEntry) error
+	// ReassignUser 将 fromUserID 的生词转给 toUserID，目标用户已有同一个词时合并出错次数
+	ReassignUser(ctx context.Context, fromUserID, toUserID string) (int64, error)
+}
+
+// SessionFilter 练习会话查询条件，零值字段不参与过滤
+type SessionFilter struct {
+	UserID string
+	Status string
+}
+
+// SessionRepository 练习会话仓储接口
+type SessionRepository interface {
+	// Create 创建会话及其句子
+	Create(ctx context.Context, session *model.PracticeSession) error
+	// GetByID 获取会话，句子按顺序加载并带上句子内容
+	GetByID(ctx context.Context, id uint) (*model.PracticeSession, error)
+	// List 按条件分页查询会话（不加载句子），同时返回满足条件的总数
+	List(ctx context.Context, filter SessionFilter, page PageRequest) ([]*model.PracticeSession, int64, error)
+	// LockActive 在事务中锁住进行中的会话，同一会话的作答和结束因此串行执行；会话已结束时返回 false
+	LockActive(ctx context.Context, id uint) (bool, error)
+	// Update 更新会话本身，不包含句子
+	Update(ctx context.Context, session *model.PracticeSession) error
+	UpdateItem(ctx context.Context, item *model.PracticeSessionItem) error
+}
+
+// UserRepository 用户仓储接口
+type UserRepository interface {
+	Create(ctx context.Context, user *model.User) error
+	GetByID(ctx context.Context, id uint) (*model.User, error)
+	GetByUID(ctx context.Context, uid string) (*model.User, error)
+	GetByUsername(ctx context.Context, username string) (*model.User, error)
+	Update(ctx context.Context, user *model.User) error
+}
+
+// AnonymousRepository 匿名用户ID仓储接口
+type AnonymousRepository interface {
+	Create(ctx context.Context, anonymous *model.AnonymousID) error
+	GetByAnonymousID(ctx context.Context, anonymousID string) (*model.AnonymousID, error)
+	// MarkClaimed 将尚未认领的匿名ID标记为被 uid 认领，已被认领时返回 false
+	MarkClaimed(ctx context.Context, anonymousID, uid string, at time.Time) (bool, error)
+}
+
+// ContentRepository 批量内容仓储接口
+type ContentRepository interface {
+	// ImportBatch 在同一事务中创建场景和句子，任一失败全部回滚
+	// 句子的 Scene 指向本批新建的场景时，在场景创建后回填 SceneID
+	ImportBatch(ctx context.Context, scenes []*model.Scene, sentences []*model.Sentence) error
 }
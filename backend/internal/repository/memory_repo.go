@@ -0,0 +1,758 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"voicewriter/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// MemoryStore 进程内数据存储，供内存仓储共享，行为与 GORM 实现保持一致：
+// 删除为软删除，外键引用已软删除的记录仍然有效，读写时复制对象，调用方修改返回值不影响存储
+type MemoryStore struct {
+	mu        sync.RWMutex
+	txMu      sync.Mutex // 串行执行事务
+	scenes    map[uint]*model.Scene
+	sentences map[uint]*model.Sentence
+	progress  map[uint]*model.UserProgress
+	lastID    struct{ scene, sentence, progress uint }
+}
+
+// NewMemoryStore 创建空的内存存储
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		scenes:    make(map[uint]*model.Scene),
+		sentences: make(map[uint]*model.Sentence),
+		progress:  make(map[uint]*model.UserProgress),
+	}
+}
+
+// memoryTx 上下文中标记内存事务的键，值为所属的存储
+type memoryTx struct{}
+
+type memoryTxManager struct {
+	store *MemoryStore
+}
+
+// NewMemoryTxManager 创建基于内存存储的事务管理器。事务之间串行执行，回滚时将存储整体恢复到事务开始时的快照，
+// 期间事务外的写入同样会被撤销，仅适用于测试
+func NewMemoryTxManager(store *MemoryStore) TxManager {
+	return &memoryTxManager{store: store}
+}
+
+func (m *memoryTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if store, ok := ctx.Value(memoryTx{}).(*MemoryStore); ok && store == m.store {
+		return fn(ctx)
+	}
+
+	s := m.store
+	s.txMu.Lock()
+	defer s.txMu.Unlock()
+
+	snapshot := s.snapshot()
+	committed := false
+	defer func() {
+		if !committed {
+			s.restore(snapshot)
+		}
+	}()
+	if err := fn(context.WithValue(ctx, memoryTx{}, s)); err != nil {
+		return err
+	}
+	committed = true
+	return nil
+}
+
+// memorySnapshot 存储的完整副本
+type memorySnapshot struct {
+	scenes    map[uint]*model.Scene
+	sentences map[uint]*model.Sentence
+	progress  map[uint]*model.UserProgress
+	lastID    struct{ scene, sentence, progress uint }
+}
+
+func (s *MemoryStore) snapshot() *memorySnapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return &memorySnapshot{
+		scenes:    copyMap(s.scenes, copyScene),
+		sentences: copyMap(s.sentences, copySentence),
+		progress:  copyMap(s.progress, copyProgress),
+		lastID:    s.lastID,
+	}
+}
+
+func (s *MemoryStore) restore(snapshot *memorySnapshot) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.scenes = snapshot.scenes
+	s.sentences = snapshot.sentences
+	s.progress = snapshot.progress
+	s.lastID = snapshot.lastID
+}
+
+func copyMap[T any](m map[uint]*T, copyValue func(*T) *T) map[uint]*T {
+	c := make(map[uint]*T, len(m))
+	for id, value := range m {
+		c[id] = copyValue(value)
+	}
+	return c
+}
+
+// nextID 分配自增主键；调用方指定主键时检查冲突并推进计数器
+func nextID(id uint, last *uint, exists bool) (uint, error) {
+	if id == 0 {
+		*last++
+		return *last, nil
+	}
+	if exists {
+		return 0, ErrDuplicateKey
+	}
+	*last = max(*last, id)
+	return id, nil
+}
+
+// softDeleted 记录是否已软删除
+func softDeleted(deletedAt gorm.DeletedAt) bool {
+	return deletedAt.Valid
+}
+
+// markDeleted 标记软删除
+func markDeleted(deletedAt *gorm.DeletedAt, now time.Time) {
+	*deletedAt = gorm.DeletedAt{Time: now, Valid: true}
+}
+
+// containsFold 不区分大小写的包含匹配，与 containsCondition 一致
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+// memoryPage 对已过滤的记录按 PageRequest 排序和分页，value 返回第 i 条记录在排序列上的值和主键
+func memoryPage(n int, value func(i int, column string) (interface{}, uint), page PageRequest) []int {
+	column := page.SortBy
+	if column == "" {
+		column = "id"
+	}
+
+	indexes := make([]int, n)
+	for i := range indexes {
+		indexes[i] = i
+	}
+	// compare 按 (排序列, id) 比较两条记录，结果已考虑升降序
+	compare := func(av interface{}, aid uint, bv interface{}, bid uint) int {
+		c := compareValues(av, bv)
+		if c == 0 {
+			c = compareValues(aid, bid)
+		}
+		if page.Desc {
+			c = -c
+		}
+		return c
+	}
+	sort.Slice(indexes, func(a, b int) bool {
+		av, aid := value(indexes[a], column)
+		bv, bid := value(indexes[b], column)
+		return compare(av, aid, bv, bid) < 0
+	})
+
+	if page.After != nil {
+		afterValue := page.After.Value
+		if column == "id" {
+			afterValue = page.After.ID
+		}
+		start := len(indexes)
+		for pos, i := range indexes {
+			v, id := value(i, column)
+			if compare(v, id, afterValue, page.After.ID) > 0 {
+				start = pos
+				break
+			}
+		}
+		indexes = indexes[start:]
+	} else if page.Offset > 0 {
+		indexes = indexes[min(page.Offset, len(indexes)):]
+	}
+
+	if page.Limit > 0 && len(indexes) > page.Limit {
+		indexes = indexes[:page.Limit]
+	}
+	return indexes
+}
+
+// compareValues 比较排序列的值，支持主键、时间和字符串
+func compareValues(a, b interface{}) int {
+	switch av := a.(type) {
+	case uint:
+		bv := b.(uint)
+		switch {
+		case av < bv:
+			return -1
+		case av > bv:
+			return 1
+		}
+		return 0
+	case time.Time:
+		return av.Compare(b.(time.Time))
+	case string:
+		return strings.Compare(av, b.(string))
+	}
+	return 0
+}
+
+type memorySceneRepository struct {
+	store *MemoryStore
+}
+
+// NewMemorySceneRepository 创建基于内存存储的场景仓储实例
+func NewMemorySceneRepository(store *MemoryStore) SceneRepository {
+	return &memorySceneRepository{store: store}
+}
+
+// copyScene 复制场景，不包含关联
+func copyScene(scene *model.Scene) *model.Scene {
+	c := *scene
+	c.Sentences = nil
+	return &c
+}
+
+func (r *memorySceneRepository) Create(ctx context.Context, scene *model.Scene) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, exists := s.scenes[scene.ID]
+	id, err := nextID(scene.ID, &s.lastID.scene, exists)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	scene.ID = id
+	if scene.Strictness == "" {
+		scene.Strictness = "normal"
+	}
+	if scene.CreatedAt.IsZero() {
+		scene.CreatedAt = now
+	}
+	if scene.UpdatedAt.IsZero() {
+		scene.UpdatedAt = now
+	}
+	s.scenes[id] = copyScene(scene)
+	return nil
+}
+
+func (r *memorySceneRepository) GetByID(ctx context.Context, id uint) (*model.Scene, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	scene, ok := s.scenes[id]
+	if !ok || softDeleted(scene.DeletedAt) {
+		return nil, ErrNotFound
+	}
+	return copyScene(scene), nil
+}
+
+func (r *memorySceneRepository) GetByName(ctx context.Context, name string) (*model.Scene, error) {
+	scenes, err := r.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, scene := range scenes {
+		if scene.Name == name {
+			return scene, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *memorySceneRepository) GetAll(ctx context.Context) ([]*model.Scene, error) {
+	return r.find(func(*model.Scene) bool { return true }), nil
+}
+
+func (r *memorySceneRepository) List(ctx context.Context, filter SceneFilter, page PageRequest) ([]*model.Scene, int64, error) {
+	matched := r.find(func(scene *model.Scene) bool {
+		return filter.Query == "" ||
+			containsFold(scene.Name, filter.Query) ||
+			containsFold(scene.Description, filter.Query)
+	})
+
+	indexes := memoryPage(len(matched), func(i int, column string) (interface{}, uint) {
+		return sceneColumn(matched[i], column), matched[i].ID
+	}, page)
+	scenes := make([]*model.Scene, len(indexes))
+	for i, index := range indexes {
+		scenes[i] = matched[index]
+	}
+	return scenes, int64(len(matched)), nil
+}
+
+// sceneColumn 返回场景在排序列上的值
+func sceneColumn(scene *model.Scene, column string) interface{} {
+	switch column {
+	case "name":
+		return scene.Name
+	case "created_at":
+		return scene.CreatedAt
+	case "updated_at":
+		return scene.UpdatedAt
+	default:
+		return scene.ID
+	}
+}
+
+// find 按主键顺序返回未删除且满足条件的场景副本
+func (r *memorySceneRepository) find(match func(*model.Scene) bool) []*model.Scene {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	scenes := []*model.Scene{}
+	for _, scene := range s.scenes {
+		if !softDeleted(scene.DeletedAt) && match(scene) {
+			scenes = append(scenes, copyScene(scene))
+		}
+	}
+	sort.Slice(scenes, func(i, j int) bool { return scenes[i].ID < scenes[j].ID })
+	return scenes
+}
+
+func (r *memorySceneRepository) Update(ctx context.Context, scene *model.Scene) error {
+	if scene.ID == 0 {
+		return r.Create(ctx, scene)
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	scene.UpdatedAt = time.Now()
+	s.lastID.scene = max(s.lastID.scene, scene.ID)
+	s.scenes[scene.ID] = copyScene(scene)
+	return nil
+}
+
+func (r *memorySceneRepository) Delete(ctx context.Context, id uint) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	scene, ok := s.scenes[id]
+	if !ok || softDeleted(scene.DeletedAt) {
+		return ErrNotFound
+	}
+	markDeleted(&scene.DeletedAt, time.Now())
+	return nil
+}
+
+type memorySentenceRepository struct {
+	store *MemoryStore
+}
+
+// NewMemorySentenceRepository 创建基于内存存储的句子仓储实例
+func NewMemorySentenceRepository(store *MemoryStore) SentenceRepository {
+	return &memorySentenceRepository{store: store}
+}
+
+// copySentence 复制句子，不包含关联
+func copySentence(sentence *model.Sentence) *model.Sentence {
+	c := *sentence
+	c.Scene = nil
+	return &c
+}
+
+func (r *memorySentenceRepository) Create(ctx context.Context, sentence *model.Sentence) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.scenes[sentence.SceneID]; !ok {
+		return ErrForeignKey
+	}
+	_, exists := s.sentences[sentence.ID]
+	id, err := nextID(sentence.ID, &s.lastID.sentence, exists)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	sentence.ID = id
+	if sentence.Language == "" {
+		sentence.Language = "en"
+	}
+	if sentence.Difficulty == "" {
+		sentence.Difficulty = "easy"
+	}
+	if sentence.CreatedAt.IsZero() {
+		sentence.CreatedAt = now
+	}
+	if sentence.UpdatedAt.IsZero() {
+		sentence.UpdatedAt = now
+	}
+	s.sentences[id] = copySentence(sentence)
+	return nil
+}
+
+func (r *memorySentenceRepository) GetByID(ctx context.Context, id uint) (*model.Sentence, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sentence, ok := s.sentences[id]
+	if !ok || softDeleted(sentence.DeletedAt) {
+		return nil, ErrNotFound
+	}
+	return copySentence(sentence), nil
+}
+
+func (r *memorySentenceRepository) GetByIDs(ctx context.Context, ids []uint) ([]*model.Sentence, error) {
+	wanted := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+	return r.find(func(sentence *model.Sentence) bool { return wanted[sentence.ID] }), nil
+}
+
+func (r *memorySentenceRepository) GetAll(ctx context.Context) ([]*model.Sentence, error) {
+	return r.find(func(*model.Sentence) bool { return true }), nil
+}
+
+func (r *memorySentenceRepository) List(ctx context.Context, filter SentenceFilter, page PageRequest) ([]*model.Sentence, int64, error) {
+	matched := r.find(func(sentence *model.Sentence) bool {
+		switch {
+		case filter.SceneID != 0 && sentence.SceneID != filter.SceneID:
+			return false
+		case filter.Difficulty != "" && sentence.Difficulty != filter.Difficulty:
+			return false
+		case filter.Language != "" && sentence.Language != filter.Language:
+			return false
+		case filter.Query != "" && !containsFold(sentence.Content, filter.Query) && !containsFold(sentence.Translation, filter.Query):
+			return false
+		case filter.HasAudio != nil && (sentence.AudioURL != "") != *filter.HasAudio:
+			return false
+		}
+		return true
+	})
+
+	indexes := memoryPage(len(matched), func(i int, column string) (interface{}, uint) {
+		return sentenceColumn(matched[i], column), matched[i].ID
+	}, page)
+	sentences := make([]*model.Sentence, len(indexes))
+	for i, index := range indexes {
+		sentences[i] = matched[index]
+	}
+	return sentences, int64(len(matched)), nil
+}
+
+// sentenceColumn 返回句子在排序列上的值
+func sentenceColumn(sentence *model.Sentence, column string) interface{} {
+	switch column {
+	case "created_at":
+		return sentence.CreatedAt
+	case "updated_at":
+		return sentence.UpdatedAt
+	default:
+		return sentence.ID
+	}
+}
+
+func (r *memorySentenceRepository) GetBySceneID(ctx context.Context, sceneID uint) ([]*model.Sentence, error) {
+	return r.find(func(sentence *model.Sentence) bool { return sentence.SceneID == sceneID }), nil
+}
+
+func (r *memorySentenceRepository) CountBySceneID(ctx context.Context, sceneID uint) (int64, error) {
+	sentences, err := r.GetBySceneID(ctx, sceneID)
+	return int64(len(sentences)), err
+}
+
+// find 按主键顺序返回未删除且满足条件的句子副本
+func (r *memorySentenceRepository) find(match func(*model.Sentence) bool) []*model.Sentence {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sentences := []*model.Sentence{}
+	for _, sentence := range s.sentences {
+		if !softDeleted(sentence.DeletedAt) && match(sentence) {
+			sentences = append(sentences, copySentence(sentence))
+		}
+	}
+	sort.Slice(sentences, func(i, j int) bool { return sentences[i].ID < sentences[j].ID })
+	return sentences
+}
+
+func (r *memorySentenceRepository) Update(ctx context.Context, sentence *model.Sentence) error {
+	if sentence.ID == 0 {
+		return r.Create(ctx, sentence)
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.scenes[sentence.SceneID]; !ok {
+		return ErrForeignKey
+	}
+	sentence.UpdatedAt = time.Now()
+	s.lastID.sentence = max(s.lastID.sentence, sentence.ID)
+	s.sentences[sentence.ID] = copySentence(sentence)
+	return nil
+}
+
+func (r *memorySentenceRepository) Delete(ctx context.Context, id uint) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sentence, ok := s.sentences[id]
+	if !ok || softDeleted(sentence.DeletedAt) {
+		return ErrNotFound
+	}
+	markDeleted(&sentence.DeletedAt, time.Now())
+	return nil
+}
+
+type memoryProgressRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryProgressRepository 创建基于内存存储的用户进度仓储实例
+func NewMemoryProgressRepository(store *MemoryStore) ProgressRepository {
+	return &memoryProgressRepository{store: store}
+}
+
+// copyProgress 复制进度，不包含关联
+func copyProgress(progress *model.UserProgress) *model.UserProgress {
+	c := *progress
+	c.Sentence = nil
+	return &c
+}
+
+func (r *memoryProgressRepository) Create(ctx context.Context, progress *model.UserProgress) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	return r.create(progress)
+}
+
+// create 写入新进度，调用方需持有写锁
+func (r *memoryProgressRepository) create(progress *model.UserProgress) error {
+	s := r.store
+	if _, ok := s.sentences[progress.SentenceID]; !ok {
+		return ErrForeignKey
+	}
+	_, exists := s.progress[progress.ID]
+	id, err := nextID(progress.ID, &s.lastID.progress, exists)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	progress.ID = id
+	if progress.CreatedAt.IsZero() {
+		progress.CreatedAt = now
+	}
+	if progress.UpdatedAt.IsZero() {
+		progress.UpdatedAt = now
+	}
+	s.progress[id] = copyProgress(progress)
+	return nil
+}
+
+func (r *memoryProgressRepository) GetByID(ctx context.Context, id uint) (*model.UserProgress, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	progress, ok := s.progress[id]
+	if !ok || softDeleted(progress.DeletedAt) {
+		return nil, ErrNotFound
+	}
+	return copyProgress(progress), nil
+}
+
+func (r *memoryProgressRepository) GetByUserID(ctx context.Context, userID string) ([]*model.UserProgress, error) {
+	return r.find(func(progress *model.UserProgress) bool { return progress.UserID == userID }), nil
+}
+
+func (r *memoryProgressRepository) GetByUserAndSentence(ctx context.Context, userID string, sentenceID uint) (*model.UserProgress, error) {
+	matched := r.find(func(progress *model.UserProgress) bool {
+		return progress.UserID == userID && progress.SentenceID == sentenceID
+	})
+	if len(matched) == 0 {
+		return nil, ErrNotFound
+	}
+	return matched[0], nil
+}
+
+// find 按主键顺序返回未删除且满足条件的进度副本
+func (r *memoryProgressRepository) find(match func(*model.UserProgress) bool) []*model.UserProgress {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := []*model.UserProgress{}
+	for _, progress := range s.progress {
+		if !softDeleted(progress.DeletedAt) && match(progress) {
+			result = append(result, copyProgress(progress))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
+
+func (r *memoryProgressRepository) Update(ctx context.Context, progress *model.UserProgress) error {
+	if progress.ID == 0 {
+		return r.Create(ctx, progress)
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.sentences[progress.SentenceID]; !ok {
+		return ErrForeignKey
+	}
+	progress.UpdatedAt = time.Now()
+	s.lastID.progress = max(s.lastID.progress, progress.ID)
+	s.progress[progress.ID] = copyProgress(progress)
+	return nil
+}
+
+func (r *memoryProgressRepository) RecordAttempt(ctx context.Context, progress *model.UserProgress) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// 与唯一索引一致，软删除的记录同样占用 (用户, 句子)
+	for _, existing := range s.progress {
+		if existing.UserID != progress.UserID || existing.SentenceID != progress.SentenceID {
+			continue
+		}
+		if softDeleted(existing.DeletedAt) {
+			existing.Attempts = 1
+			existing.DeletedAt = gorm.DeletedAt{}
+		} else {
+			existing.Attempts++
+		}
+		existing.Completed = progress.Completed
+		existing.LastAttempt = progress.LastAttempt
+		existing.UpdatedAt = time.Now()
+		return nil
+	}
+	return r.create(progress)
+}
+
+func (r *memoryProgressRepository) Delete(ctx context.Context, id uint) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if progress, ok := s.progress[id]; ok && !softDeleted(progress.DeletedAt) {
+		markDeleted(&progress.DeletedAt, time.Now())
+	}
+	return nil
+}
+
+func (r *memoryProgressRepository) ReassignUser(ctx context.Context, fromUserID, toUserID string) (int64, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	owned := make(map[uint]bool)
+	for id, progress := range s.progress {
+		if softDeleted(progress.DeletedAt) && progress.UserID == toUserID {
+			delete(s.progress, id)
+			continue
+		}
+		if progress.UserID == toUserID {
+			owned[progress.SentenceID] = true
+		}
+	}
+
+	var moved int64
+	now := time.Now()
+	for _, progress := range s.progress {
+		if softDeleted(progress.DeletedAt) || progress.UserID != fromUserID {
+			continue
+		}
+		if owned[progress.SentenceID] {
+			// 与目标用户冲突的记录不再保留
+			markDeleted(&progress.DeletedAt, now)
+			continue
+		}
+		progress.UserID = toUserID
+		progress.UpdatedAt = now
+		moved++
+	}
+	return moved, nil
+}
+
+func (r *memoryProgressRepository) SummarizeCompletion(ctx context.Context, userID string) ([]CompletionGroup, error) {
+	completed := make(map[uint]bool)
+	for _, progress := range r.find(func(progress *model.UserProgress) bool { return progress.UserID == userID }) {
+		completed[progress.SentenceID] = progress.Completed
+	}
+
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	type key struct {
+		sceneID    uint
+		difficulty string
+	}
+	byGroup := make(map[key]*CompletionGroup)
+	for _, sentence := range s.sentences {
+		if softDeleted(sentence.DeletedAt) {
+			continue
+		}
+		k := key{sentence.SceneID, sentence.Difficulty}
+		group, ok := byGroup[k]
+		if !ok {
+			group = &CompletionGroup{SceneID: k.sceneID, Difficulty: k.difficulty}
+			byGroup[k] = group
+		}
+		group.Total++
+		if done, practiced := completed[sentence.ID]; practiced {
+			group.Practiced++
+			if done {
+				group.Completed++
+			}
+		}
+	}
+
+	groups := make([]CompletionGroup, 0, len(byGroup))
+	for _, group := range byGroup {
+		groups = append(groups, *group)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].SceneID != groups[j].SceneID {
+			return groups[i].SceneID < groups[j].SceneID
+		}
+		return groups[i].Difficulty < groups[j].Difficulty
+	})
+	return groups, nil
+}
+
+func (r *memoryProgressRepository) SummarizeByUser(ctx context.Context) ([]UserProgressSummary, error) {
+	byUser := make(map[string]*UserProgressSummary)
+	for _, progress := range r.find(func(*model.UserProgress) bool { return true }) {
+		summary, ok := byUser[progress.UserID]
+		if !ok {
+			summary = &UserProgressSummary{UserID: progress.UserID}
+			byUser[progress.UserID] = summary
+		}
+		summary.Practiced++
+		if progress.Completed {
+			summary.Completed++
+		}
+	}
+
+	summaries := make([]UserProgressSummary, 0, len(byUser))
+	for _, summary := range byUser {
+		summaries = append(summaries, *summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].UserID < summaries[j].UserID })
+	return summaries, nil
+}
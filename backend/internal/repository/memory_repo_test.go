@@ -0,0 +1,19 @@
+package repository_test
+
+import (
+	"testing"
+
+	"voicewriter/internal/repository"
+)
+
+func TestMemoryRepositoryContract(t *testing.T) {
+	runContract(t, func(t *testing.T) repositories {
+		store := repository.NewMemoryStore()
+		return repositories{
+			scenes:    repository.NewMemorySceneRepository(store),
+			sentences: repository.NewMemorySentenceRepository(store),
+			progress:  repository.NewMemoryProgressRepository(store),
+			tx:        repository.NewMemoryTxManager(store),
+		}
+	})
+}
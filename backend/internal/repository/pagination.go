@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// PageRequest 分页和排序参数
+type PageRequest struct {
+	SortBy string // 排序列名，由调用方按白名单校验，为空时按 id 排序
+	Desc   bool
+	Limit  int
+	Offset int
+	After  *Cursor // 游标分页：设置后忽略 Offset，从该位置之后继续
+}
+
+// Cursor 上一页最后一条记录的排序列值和主键
+type Cursor struct {
+	Value interface{}
+	ID    uint
+}
+
+// applyPage 按排序列和主键排序，并附加游标条件、偏移量和条数限制
+// 以 (排序列, id) 作为游标，排序列存在重复值时翻页也不会丢失或重复
+func applyPage(query *gorm.DB, table string, page PageRequest) *gorm.DB {
+	column := table + ".id"
+	if page.SortBy != "" && page.SortBy != "id" {
+		column = table + "." + page.SortBy
+	}
+	idColumn := table + ".id"
+
+	direction, cmp := " ASC", " > "
+	if page.Desc {
+		direction, cmp = " DESC", " < "
+	}
+
+	if page.After != nil {
+		if column == idColumn {
+			query = query.Where(idColumn+cmp+"?", page.After.ID)
+		} else {
+			query = query.Where(
+				"("+column+cmp+"?) OR ("+column+" = ? AND "+idColumn+cmp+"?)",
+				page.After.Value, page.After.Value, page.After.ID,
+			)
+		}
+	} else if page.Offset > 0 {
+		query = query.Offset(page.Offset)
+	}
+
+	if column != idColumn {
+		query = query.Order(column + direction)
+	}
+	query = query.Order(idColumn + direction)
+
+	if page.Limit > 0 {
+		query = query.Limit(page.Limit)
+	}
+	return query
+}
+
+// likeEscaper 转义 LIKE 通配符，配合 ESCAPE '!' 使用；各数据库对反斜杠的处理不一致，因此不用反斜杠
+var likeEscaper = strings.NewReplacer(`!`, `!!`, `%`, `!%`, `_`, `!_`)
+
+// likePattern 返回包含匹配的 LIKE 模式
+func likePattern(s string) string {
+	return "%" + likeEscaper.Replace(s) + "%"
+}
+
+// containsCondition 生成多列任一包含匹配的条件，参数为每列一个 likePattern
+// MySQL 默认排序规则和 SQLite 的 LIKE 不区分大小写，PostgreSQL 需要使用 ILIKE 才能保持一致
+func containsCondition(db *gorm.DB, columns ...string) string {
+	operator := " LIKE "
+	if db.Dialector.Name() == "postgres" {
+		operator = " ILIKE "
+	}
+	conditions := make([]string, len(columns))
+	for i, column := range columns {
+		conditions[i] = column + operator + "? ESCAPE '!'"
+	}
+	return "(" + strings.Join(conditions, " OR ") + ")"
+}
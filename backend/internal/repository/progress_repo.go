@@ -7,6 +7,7 @@ import (
 	"voicewriter/internal/model"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type progressRepository struct {
@@ -19,12 +20,12 @@ func NewProgressRepository(db *gorm.DB) ProgressRepository {
 }
 
 func (r *progressRepository) Create(ctx context.Context, progress *model.UserProgress) error {
-	return r.db.WithContext(ctx).Create(progress).Error
+	return translateError(dbFrom(ctx, r.db).Create(progress).Error)
 }
 
 func (r *progressRepository) GetByID(ctx context.Context, id uint) (*model.UserProgress, error) {
 	var progress model.UserProgress
-	err := r.db.WithContext(ctx).First(&progress, id).Error
+	err := dbFrom(ctx, r.db).First(&progress, id).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrNotFound
@@ -36,7 +37,7 @@ func (r *progressRepository) GetByID(ctx context.Context, id uint) (*model.UserP
 
 func (r *progressRepository) GetByUserID(ctx context.Context, userID string) ([]*model.UserProgress, error) {
 	var progress []*model.UserProgress
-	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Find(&progress).Error
+	err := dbFrom(ctx, r.db).Where("user_id = ?", userID).Find(&progress).Error
 	if err != nil {
 		return nil, err
 	}
@@ -45,7 +46,7 @@ func (r *progressRepository) GetByUserID(ctx context.Context, userID string) ([]
 
 func (r *progressRepository) GetByUserAndSentence(ctx context.Context, userID string, sentenceID uint) (*model.UserProgress, error) {
 	var progress model.UserProgress
-	err := r.db.WithContext(ctx).
+	err := dbFrom(ctx, r.db).
 		Where("user_id = ? AND sentence_id = ?", userID, sentenceID).
 		First(&progress).Error
 	if err != nil {
@@ -58,9 +59,79 @@ func (r *progressRepository) GetByUserAndSentence(ctx context.Context, userID st
 }
 
 func (r *progressRepository) Update(ctx context.Context, progress *model.UserProgress) error {
-	return r.db.WithContext(ctx).Save(progress).Error
+	return translateError(dbFrom(ctx, r.db).Save(progress).Error)
+}
+
+func (r *progressRepository) RecordAttempt(ctx context.Context, progress *model.UserProgress) error {
+	// MySQL 按书写顺序求值 SET 子句，deleted_at 必须最后清空，否则 attempts 的判断会读到清空后的值
+	return translateError(dbFrom(ctx, r.db).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "sentence_id"}},
+		DoUpdates: append([]clause.Assignment{{
+			Column: clause.Column{Name: "attempts"},
+			Value:  gorm.Expr("CASE WHEN user_progress.deleted_at IS NULL THEN user_progress.attempts + 1 ELSE 1 END"),
+		}},
+			append(clause.AssignmentColumns([]string{"completed", "last_attempt", "updated_at"}),
+				clause.Assignment{Column: clause.Column{Name: "deleted_at"}, Value: nil})...),
+	}).Create(progress).Error)
 }
 
 func (r *progressRepository) Delete(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Delete(&model.UserProgress{}, id).Error
+	return dbFrom(ctx, r.db).Delete(&model.UserProgress{}, id).Error
+}
+
+func (r *progressRepository) ReassignUser(ctx context.Context, fromUserID, toUserID string) (int64, error) {
+	var moved int64
+	err := dbFrom(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		// 目标用户已软删除的记录仍占用唯一索引，先彻底清除
+		if err := tx.Unscoped().Where("user_id = ? AND deleted_at IS NOT NULL", toUserID).Delete(&model.UserProgress{}).Error; err != nil {
+			return err
+		}
+		// MySQL 不允许 UPDATE 的子查询引用同一张表，先查出目标用户已有的句子
+		var owned []uint
+		if err := tx.Model(&model.UserProgress{}).Where("user_id = ?", toUserID).Pluck("sentence_id", &owned).Error; err != nil {
+			return err
+		}
+		query := tx.Model(&model.UserProgress{}).Where("user_id = ?", fromUserID)
+		if len(owned) > 0 {
+			query = query.Where("sentence_id NOT IN ?", owned)
+		}
+		result := query.Update("user_id", toUserID)
+		if result.Error != nil {
+			return result.Error
+		}
+		moved = result.RowsAffected
+		// 与目标用户冲突的剩余记录不再保留
+		return tx.Where("user_id = ?", fromUserID).Delete(&model.UserProgress{}).Error
+	})
+	return moved, err
+}
+
+func (r *progressRepository) SummarizeCompletion(ctx context.Context, userID string) ([]CompletionGroup, error) {
+	// (用户, 句子) 上有唯一索引，每个句子最多连接到一条进度
+	var groups []CompletionGroup
+	err := dbFrom(ctx, r.db).Table("sentences").
+		Select("sentences.scene_id, sentences.difficulty, COUNT(*) AS total, COUNT(user_progress.id) AS practiced, "+
+			"COALESCE(SUM(CASE WHEN user_progress.completed THEN 1 ELSE 0 END), 0) AS completed").
+		Joins("LEFT JOIN user_progress ON user_progress.sentence_id = sentences.id AND user_progress.user_id = ? AND user_progress.deleted_at IS NULL", userID).
+		Where("sentences.deleted_at IS NULL").
+		Group("sentences.scene_id, sentences.difficulty").
+		Order("sentences.scene_id, sentences.difficulty").
+		Scan(&groups).Error
+	if err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+func (r *progressRepository) SummarizeByUser(ctx context.Context) ([]UserProgressSummary, error) {
+	var summaries []UserProgressSummary
+	err := dbFrom(ctx, r.db).Model(&model.UserProgress{}).
+		Select("user_id, COUNT(*) AS practiced, SUM(CASE WHEN completed THEN 1 ELSE 0 END) AS completed").
+		Group("user_id").
+		Order("user_id").
+		Scan(&summaries).Error
+	if err != nil {
+		return nil, err
+	}
+	return summaries, nil
 }
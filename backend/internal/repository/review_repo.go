@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"voicewriter/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type reviewRepository struct {
+	db *gorm.DB
+}
+
+// NewReviewRepository 创建复习状态仓储实例
+func NewReviewRepository(db *gorm.DB) ReviewRepository {
+	return &reviewRepository{db: db}
+}
+
+func (r *reviewRepository) Create(ctx context.Context, state *model.ReviewState) error {
+	return translateError(dbFrom(ctx, r.db).Create(state).Error)
+}
+
+func (r *reviewRepository) GetByUserAndSentence(ctx context.Context, userID string, sentenceID uint) (*model.ReviewState, error) {
+	var state model.ReviewState
+	err := dbFrom(ctx, r.db).
+		Where("user_id = ? AND sentence_id = ?", userID, sentenceID).
+		First(&state).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &state, nil
+}
+
+func (r *reviewRepository) GetDue(ctx context.Context, userID string, now time.Time, limit int) ([]*model.ReviewState, error) {
+	var states []*model.ReviewState
+	err := dbFrom(ctx, r.db).
+		InnerJoins("Sentence").
+		Where("review_states.user_id = ? AND review_states.due_at <= ?", userID, now).
+		Order("review_states.due_at ASC, review_states.id ASC").
+		Limit(limit).
+		Find(&states).Error
+	if err != nil {
+		return nil, err
+	}
+	return states, nil
+}
+
+func (r *reviewRepository) Update(ctx context.Context, state *model.ReviewState) error {
+	return dbFrom(ctx, r.db).Save(state).Error
+}
+
+func (r *reviewRepository) ReassignUser(ctx context.Context, fromUserID, toUserID string) (int64, error) {
+	var moved int64
+	err := dbFrom(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		// 目标用户已软删除的记录仍占用唯一索引，先彻底清除
+		if err := tx.Unscoped().Where("user_id = ? AND deleted_at IS NOT NULL", toUserID).Delete(&model.ReviewState{}).Error; err != nil {
+			return err
+		}
+		// MySQL 不允许 UPDATE 的子查询引用同一张表，先查出目标用户已有的句子
+		var owned []uint
+		if err := tx.Model(&model.ReviewState{}).Where("user_id = ?", toUserID).Pluck("sentence_id", &owned).Error; err != nil {
+			return err
+		}
+		query := tx.Model(&model.ReviewState{}).Where("user_id = ?", fromUserID)
+		if len(owned) > 0 {
+			query = query.Where("sentence_id NOT IN ?", owned)
+		}
+		result := query.Update("user_id", toUserID)
+		if result.Error != nil {
+			return result.Error
+		}
+		moved = result.RowsAffected
+		// 与目标用户冲突的剩余记录不再保留
+		return tx.Where("user_id = ?", fromUserID).Delete(&model.ReviewState{}).Error
+	})
+	return moved, err
+}
@@ -19,12 +19,24 @@ func NewSceneRepository(db *gorm.DB) SceneRepository {
 }
 
 func (r *sceneRepository) Create(ctx context.Context, scene *model.Scene) error {
-	return r.db.WithContext(ctx).Create(scene).Error
+	return translateError(dbFrom(ctx, r.db).Create(scene).Error)
 }
 
 func (r *sceneRepository) GetByID(ctx context.Context, id uint) (*model.Scene, error) {
 	var scene model.Scene
-	err := r.db.WithContext(ctx).First(&scene, id).Error
+	err := dbFrom(ctx, r.db).First(&scene, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &scene, nil
+}
+
+func (r *sceneRepository) GetByName(ctx context.Context, name string) (*model.Scene, error) {
+	var scene model.Scene
+	err := dbFrom(ctx, r.db).Where("name = ?", name).First(&scene).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrNotFound
@@ -36,17 +48,43 @@ func (r *sceneRepository) GetByID(ctx context.Context, id uint) (*model.Scene, e
 
 func (r *sceneRepository) GetAll(ctx context.Context) ([]*model.Scene, error) {
 	var scenes []*model.Scene
-	err := r.db.WithContext(ctx).Find(&scenes).Error
+	err := dbFrom(ctx, r.db).Find(&scenes).Error
 	if err != nil {
 		return nil, err
 	}
 	return scenes, nil
 }
 
+func (r *sceneRepository) List(ctx context.Context, filter SceneFilter, page PageRequest) ([]*model.Scene, int64, error) {
+	query := dbFrom(ctx, r.db).Model(&model.Scene{})
+	if filter.Query != "" {
+		pattern := likePattern(filter.Query)
+		query = query.Where(containsCondition(r.db, "scenes.name", "scenes.description"), pattern, pattern)
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var scenes []*model.Scene
+	if err := applyPage(query.Session(&gorm.Session{}), "scenes", page).Find(&scenes).Error; err != nil {
+		return nil, 0, err
+	}
+	return scenes, total, nil
+}
+
 func (r *sceneRepository) Update(ctx context.Context, scene *model.Scene) error {
-	return r.db.WithContext(ctx).Save(scene).Error
+	return translateError(dbFrom(ctx, r.db).Save(scene).Error)
 }
 
 func (r *sceneRepository) Delete(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Delete(&model.Scene{}, id).Error
+	result := dbFrom(ctx, r.db).Delete(&model.Scene{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
 }
@@ -0,0 +1,359 @@
+package repository
+
+import (
+	"context"
+	"html"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// BM25 参数
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// 索引字段及其权重，原文命中比翻译命中排名更靠前；翻译表中各语言的翻译合为一个字段计分
+const (
+	fieldContent = iota
+	fieldTranslation
+	fieldTranslations
+	fieldCount
+)
+
+var (
+	fieldNames   = [fieldCount]string{"content", "translation", "translation"}
+	fieldWeights = [fieldCount]float64{1.0, 0.8, 0.8}
+)
+
+// fieldText 字段中的一段文本，key 为高亮结果中的键
+type fieldText struct {
+	key, text string
+}
+
+// fieldTexts 按字段列出句子的文本，各语言的翻译按语言排序
+func fieldTexts(doc SearchDocument) [fieldCount][]fieldText {
+	var texts [fieldCount][]fieldText
+	texts[fieldContent] = []fieldText{{fieldNames[fieldContent], doc.Content}}
+	texts[fieldTranslation] = []fieldText{{fieldNames[fieldTranslation], doc.Translation}}
+	locales := make([]string, 0, len(doc.Translations))
+	for locale := range doc.Translations {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	for _, locale := range locales {
+		texts[fieldTranslations] = append(texts[fieldTranslations], fieldText{fieldNames[fieldTranslations] + "." + locale, doc.Translations[locale]})
+	}
+	return texts
+}
+
+type indexedDoc struct {
+	doc     SearchDocument
+	lengths [fieldCount]int
+	terms   []string
+}
+
+type memorySearchIndex struct {
+	mu       sync.RWMutex
+	docs     map[uint]*indexedDoc
+	postings map[string]map[uint]*[fieldCount]int // 词项 -> 句子ID -> 各字段词频
+	totalLen [fieldCount]int
+}
+
+// NewMemorySearchIndex 创建进程内倒排索引，不依赖外部搜索引擎
+// 英文等按词切分，中日韩文本按单字和二元组切分；索引不持久化，启动时需要重建
+func NewMemorySearchIndex() SearchIndex {
+	return &memorySearchIndex{
+		docs:     make(map[uint]*indexedDoc),
+		postings: make(map[string]map[uint]*[fieldCount]int),
+	}
+}
+
+func (idx *memorySearchIndex) Index(ctx context.Context, docs ...SearchDocument) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	for _, doc := range docs {
+		idx.remove(doc.ID)
+
+		entry := &indexedDoc{doc: doc}
+		seen := make(map[string]bool)
+		for field, texts := range fieldTexts(doc) {
+			var tokens []searchToken
+			for _, text := range texts {
+				tokens = append(tokens, tokenizeForIndex(text.text)...)
+			}
+			entry.lengths[field] = len(tokens)
+			idx.totalLen[field] += len(tokens)
+			for _, t := range tokens {
+				docsForTerm := idx.postings[t.term]
+				if docsForTerm == nil {
+					docsForTerm = make(map[uint]*[fieldCount]int)
+					idx.postings[t.term] = docsForTerm
+				}
+				freq := docsForTerm[doc.ID]
+				if freq == nil {
+					freq = new([fieldCount]int)
+					docsForTerm[doc.ID] = freq
+				}
+				freq[field]++
+				if !seen[t.term] {
+					seen[t.term] = true
+					entry.terms = append(entry.terms, t.term)
+				}
+			}
+		}
+		idx.docs[doc.ID] = entry
+	}
+	return nil
+}
+
+func (idx *memorySearchIndex) Delete(ctx context.Context, ids ...uint) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	for _, id := range ids {
+		idx.remove(id)
+	}
+	return nil
+}
+
+// remove 从倒排表中移除句子，调用方需持有写锁
+func (idx *memorySearchIndex) remove(id uint) {
+	entry, ok := idx.docs[id]
+	if !ok {
+		return
+	}
+	for _, term := range entry.terms {
+		delete(idx.postings[term], id)
+		if len(idx.postings[term]) == 0 {
+			delete(idx.postings, term)
+		}
+	}
+	for field := range entry.lengths {
+		idx.totalLen[field] -= entry.lengths[field]
+	}
+	delete(idx.docs, id)
+}
+
+func (idx *memorySearchIndex) Search(ctx context.Context, query SearchQuery) ([]SearchHit, int, error) {
+	terms := queryTerms(query.Text)
+	if len(terms) == 0 {
+		return []SearchHit{}, 0, nil
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	// 所有词项都必须命中，从文档最少的词项开始求交集
+	sort.Slice(terms, func(i, j int) bool {
+		return len(idx.postings[terms[i]]) < len(idx.postings[terms[j]])
+	})
+	var candidates []uint
+	for id := range idx.postings[terms[0]] {
+		candidates = append(candidates, id)
+	}
+
+	n := float64(len(idx.docs))
+	var avgLen [fieldCount]float64
+	for field := range avgLen {
+		if n > 0 {
+			avgLen[field] = math.Max(float64(idx.totalLen[field])/n, 1)
+		}
+	}
+
+	hits := make([]SearchHit, 0, len(candidates))
+	for _, id := range candidates {
+		entry := idx.docs[id]
+		if query.SceneID != 0 && entry.doc.SceneID != query.SceneID {
+			continue
+		}
+		if query.Language != "" && entry.doc.Language != query.Language {
+			continue
+		}
+
+		score, matched := 0.0, true
+		for _, term := range terms {
+			freq, ok := idx.postings[term][id]
+			if !ok {
+				matched = false
+				break
+			}
+			df := float64(len(idx.postings[term]))
+			idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+			for field, tf := range freq {
+				if tf == 0 {
+					continue
+				}
+				norm := 1 - bm25B + bm25B*float64(entry.lengths[field])/avgLen[field]
+				score += fieldWeights[field] * idf * float64(tf) * (bm25K1 + 1) / (float64(tf) + bm25K1*norm)
+			}
+		}
+		if matched {
+			hits = append(hits, SearchHit{ID: id, Score: score})
+		}
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID < hits[j].ID
+	})
+
+	total := len(hits)
+	start := min(max(query.Offset, 0), total)
+	end := total
+	if query.Limit > 0 {
+		end = min(start+query.Limit, total)
+	}
+	page := hits[start:end]
+
+	termSet := make(map[string]bool, len(terms))
+	for _, term := range terms {
+		termSet[term] = true
+	}
+	for i := range page {
+		doc := idx.docs[page[i].ID].doc
+		page[i].Highlights = make(map[string]string)
+		for _, texts := range fieldTexts(doc) {
+			for _, text := range texts {
+				if highlighted, ok := highlight(text.text, termSet); ok {
+					page[i].Highlights[text.key] = highlighted
+				}
+			}
+		}
+	}
+	return page, total, nil
+}
+
+// searchToken 切分出的词项及其在原文中的字节区间
+type searchToken struct {
+	term       string
+	start, end int
+}
+
+// tokenizeForIndex 切分建立索引的文本：中日韩字符同时产生单字和二元组，便于单字和多字查询
+func tokenizeForIndex(text string) []searchToken {
+	return tokenize(text, true)
+}
+
+// queryTerms 切分查询文本并去重：中日韩连续文本长度大于 1 时只用二元组匹配
+func queryTerms(text string) []string {
+	seen := make(map[string]bool)
+	var terms []string
+	for _, t := range tokenize(text, false) {
+		if !seen[t.term] {
+			seen[t.term] = true
+			terms = append(terms, t.term)
+		}
+	}
+	return terms
+}
+
+func tokenize(text string, withUnigrams bool) []searchToken {
+	var tokens []searchToken
+	var word strings.Builder
+	wordStart := -1
+
+	flushWord := func(end int) {
+		if wordStart >= 0 {
+			if term := strings.Trim(word.String(), "'"); term != "" {
+				tokens = append(tokens, searchToken{term: term, start: wordStart, end: end})
+			}
+		}
+		word.Reset()
+		wordStart = -1
+	}
+
+	type cjkRune struct {
+		r          rune
+		start, end int
+	}
+	var run []cjkRune
+	flushRun := func() {
+		if len(run) == 1 || withUnigrams {
+			for _, c := range run {
+				tokens = append(tokens, searchToken{term: string(c.r), start: c.start, end: c.end})
+			}
+		}
+		for i := 0; i+1 < len(run); i++ {
+			tokens = append(tokens, searchToken{
+				term:  string([]rune{run[i].r, run[i+1].r}),
+				start: run[i].start,
+				end:   run[i+1].end,
+			})
+		}
+		run = run[:0]
+	}
+
+	for i, r := range text {
+		size := utf8.RuneLen(r)
+		switch {
+		case isCJKRune(r):
+			flushWord(i)
+			run = append(run, cjkRune{r: unicode.ToLower(r), start: i, end: i + size})
+		case unicode.IsLetter(r) || unicode.IsNumber(r):
+			flushRun()
+			if wordStart < 0 {
+				wordStart = i
+			}
+			word.WriteRune(unicode.ToLower(r))
+		case (r == '\'' || r == '’') && wordStart >= 0:
+			word.WriteRune('\'')
+		default:
+			flushWord(i)
+			flushRun()
+		}
+	}
+	flushWord(len(text))
+	flushRun()
+	return tokens
+}
+
+func isCJKRune(r rune) bool {
+	return unicode.Is(unicode.Han, r) ||
+		unicode.Is(unicode.Hiragana, r) ||
+		unicode.Is(unicode.Katakana, r) ||
+		unicode.Is(unicode.Hangul, r)
+}
+
+// highlight 用 <em> 标出命中的词项，相邻或重叠的命中区间合并；其余文本做 HTML 转义
+func highlight(text string, terms map[string]bool) (string, bool) {
+	var spans [][2]int
+	for _, t := range tokenizeForIndex(text) {
+		if terms[t.term] {
+			spans = append(spans, [2]int{t.start, t.end})
+		}
+	}
+	if len(spans) == 0 {
+		return "", false
+	}
+
+	sort.Slice(spans, func(i, j int) bool { return spans[i][0] < spans[j][0] })
+	merged := spans[:1]
+	for _, span := range spans[1:] {
+		last := &merged[len(merged)-1]
+		if span[0] <= last[1] {
+			last[1] = max(last[1], span[1])
+			continue
+		}
+		merged = append(merged, span)
+	}
+
+	var b strings.Builder
+	pos := 0
+	for _, span := range merged {
+		b.WriteString(html.EscapeString(text[pos:span[0]]))
+		b.WriteString("<em>")
+		b.WriteString(html.EscapeString(text[span[0]:span[1]]))
+		b.WriteString("</em>")
+		pos = span[1]
+	}
+	b.WriteString(html.EscapeString(text[pos:]))
+	return b.String(), true
+}
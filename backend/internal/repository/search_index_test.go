@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func terms(tokens []searchToken) []string {
+	out := make([]string, len(tokens))
+	for i, t := range tokens {
+		out[i] = t.term
+	}
+	return out
+}
+
+func TestTokenize(t *testing.T) {
+	cases := []struct {
+		name  string
+		text  string
+		index []string
+		query []string
+	}{
+		{"words are lowercased", "Hello, World!", []string{"hello", "world"}, []string{"hello", "world"}},
+		{"apostrophes stay inside words", "I'm 'fine' don’t", []string{"i'm", "fine", "don't"}, []string{"i'm", "fine", "don't"}},
+		{"cjk run gives unigrams and bigrams", "你好吗", []string{"你", "好", "吗", "你好", "好吗"}, []string{"你好", "好吗"}},
+		{"single cjk rune", "好", []string{"好"}, []string{"好"}},
+		{"mixed scripts", "Tokyo东京ホテル", []string{"tokyo", "东", "京", "ホ", "テ", "ル", "东京", "京ホ", "ホテ", "テル"}, []string{"tokyo", "东京", "京ホ", "ホテ", "テル"}},
+		{"hangul", "안녕 하세요", []string{"안", "녕", "안녕", "하", "세", "요", "하세", "세요"}, []string{"안녕", "하세", "세요"}},
+		{"punctuation splits cjk runs", "你好，世界", []string{"你", "好", "你好", "世", "界", "世界"}, []string{"你好", "世界"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := terms(tokenizeForIndex(tc.text)); !reflect.DeepEqual(got, tc.index) {
+				t.Errorf("index tokens = %q, want %q", got, tc.index)
+			}
+			if got := queryTerms(tc.text); !reflect.DeepEqual(got, tc.query) {
+				t.Errorf("query terms = %q, want %q", got, tc.query)
+			}
+		})
+	}
+
+	// 词项区间指向原文，多字节字符按字节计
+	tokens := tokenizeForIndex("说Hi")
+	if tokens[0].start != 0 || tokens[0].end != 3 || tokens[1].start != 3 || tokens[1].end != 5 {
+		t.Errorf("token spans = %+v", tokens)
+	}
+	if got := queryTerms("the cat THE Cat"); !reflect.DeepEqual(got, []string{"the", "cat"}) {
+		t.Errorf("duplicate query terms = %q", got)
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	cases := []struct {
+		name  string
+		text  string
+		terms []string
+		want  string
+	}{
+		{"separate words", "how are you", []string{"how", "you"}, "<em>how</em> are <em>you</em>"},
+		{"overlapping bigrams merge", "我们去学校吧", []string{"去学", "学校"}, "我们<em>去学校</em>吧"},
+		{"adjacent unigrams merge", "你好", []string{"你", "好"}, "<em>你好</em>"},
+		{"case is kept", "Hello hello", []string{"hello"}, "<em>Hello</em> <em>hello</em>"},
+		{"html is escaped", "<b>tom & jerry</b>", []string{"tom"}, "&lt;b&gt;<em>tom</em> &amp; jerry&lt;/b&gt;"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			set := make(map[string]bool)
+			for _, term := range tc.terms {
+				set[term] = true
+			}
+			got, ok := highlight(tc.text, set)
+			if !ok || got != tc.want {
+				t.Errorf("highlight = %q, %v; want %q", got, ok, tc.want)
+			}
+		})
+	}
+	if _, ok := highlight("nothing here", map[string]bool{"missing": true}); ok {
+		t.Error("highlight without matches: want false")
+	}
+}
+
+func hitIDs(hits []SearchHit) []uint {
+	ids := make([]uint, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.ID
+	}
+	return ids
+}
+
+func TestMemorySearchIndex(t *testing.T) {
+	ctx := context.Background()
+	idx := NewMemorySearchIndex()
+	docs := []SearchDocument{
+		{ID: 1, SceneID: 1, Language: "en", Content: "Where is the train station?", Translation: "火车站在哪里？"},
+		{ID: 2, SceneID: 1, Language: "en", Content: "The train is late.", Translation: "火车晚点了。"},
+		{ID: 3, SceneID: 2, Language: "en", Content: "Where is the hotel?", Translation: "酒店在哪里？"},
+		{ID: 4, SceneID: 2, Language: "ja", Content: "駅はどこですか", Translation: "车站在哪里？"},
+	}
+	if err := idx.Index(ctx, docs...); err != nil {
+		t.Fatal(err)
+	}
+
+	search := func(query SearchQuery) ([]uint, int) {
+		t.Helper()
+		hits, total, err := idx.Search(ctx, query)
+		if err != nil {
+			t.Fatalf("Search(%+v): %v", query, err)
+		}
+		return hitIDs(hits), total
+	}
+
+	cases := []struct {
+		name  string
+		query SearchQuery
+		want  []uint
+	}{
+		{"all terms must match", SearchQuery{Text: "where train"}, []uint{1}},
+		{"one term", SearchQuery{Text: "train"}, []uint{1, 2}},
+		{"unknown term empties the result", SearchQuery{Text: "train airport"}, []uint{}},
+		{"cjk bigrams", SearchQuery{Text: "火车"}, []uint{1, 2}},
+		{"cjk phrase across bigrams", SearchQuery{Text: "在哪里"}, []uint{1, 3, 4}},
+		{"scene filter", SearchQuery{Text: "where", SceneID: 2}, []uint{3}},
+		{"language filter", SearchQuery{Text: "在哪里", Language: "ja"}, []uint{4}},
+		{"scene and language filters", SearchQuery{Text: "在哪里", SceneID: 2, Language: "en"}, []uint{3}},
+		{"blank query", SearchQuery{Text: " ?! "}, []uint{}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, total := search(tc.query)
+			if total != len(tc.want) || !sameIDs(got, tc.want) {
+				t.Errorf("hits = %v (total %d), want %v", got, total, tc.want)
+			}
+		})
+	}
+
+	// 火车站的两个二元组只在句子 1 中同时出现
+	if got, _ := search(SearchQuery{Text: "火车站"}); len(got) != 1 || got[0] != 1 {
+		t.Errorf("火车站 = %v, want [1]", got)
+	}
+
+	got, total := search(SearchQuery{Text: "where", Limit: 1, Offset: 1})
+	if total != 2 || len(got) != 1 {
+		t.Errorf("page = %v (total %d), want 1 hit of 2", got, total)
+	}
+
+	hits, _, _ := idx.Search(ctx, SearchQuery{Text: "火车站"})
+	if h := hits[0].Highlights; h["translation"] != "<em>火车站</em>在哪里？" || h["content"] != "" {
+		t.Errorf("highlights = %v", h)
+	}
+}
+
+func sameIDs(got, want []uint) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	seen := make(map[uint]bool, len(got))
+	for _, id := range got {
+		seen[id] = true
+	}
+	for _, id := range want {
+		if !seen[id] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestMemorySearchIndexTranslations(t *testing.T) {
+	ctx := context.Background()
+	idx := NewMemorySearchIndex()
+	doc := SearchDocument{
+		ID: 1, SceneID: 1, Language: "en", Content: "Good morning", Translation: "早上好",
+		Translations: map[string]string{"ko": "좋은 아침", "es": "Buenos días"},
+	}
+	if err := idx.Index(ctx, doc); err != nil {
+		t.Fatal(err)
+	}
+
+	hits, total, err := idx.Search(ctx, SearchQuery{Text: "아침"})
+	if err != nil || total != 1 {
+		t.Fatalf("search translation = %v, %d, %v", hitIDs(hits), total, err)
+	}
+	if got := hits[0].Highlights["translation.ko"]; got != "좋은 <em>아침</em>" {
+		t.Errorf("translation.ko highlight = %q", got)
+	}
+	if _, ok := hits[0].Highlights["translation.es"]; ok {
+		t.Errorf("unmatched translation highlighted: %v", hits[0].Highlights)
+	}
+
+	// 重新索引替换旧的翻译词项
+	doc.Translations = map[string]string{"ko": "안녕하세요"}
+	if err := idx.Index(ctx, doc); err != nil {
+		t.Fatal(err)
+	}
+	if _, total, _ := idx.Search(ctx, SearchQuery{Text: "아침"}); total != 0 {
+		t.Errorf("old translation still matches after reindex: total %d", total)
+	}
+	if _, total, _ := idx.Search(ctx, SearchQuery{Text: "buenos"}); total != 0 {
+		t.Errorf("removed locale still matches after reindex: total %d", total)
+	}
+	if _, total, _ := idx.Search(ctx, SearchQuery{Text: "안녕"}); total != 1 {
+		t.Errorf("new translation total = %d, want 1", total)
+	}
+
+	if err := idx.Delete(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, total, _ := idx.Search(ctx, SearchQuery{Text: "morning"}); total != 0 {
+		t.Errorf("deleted sentence still matches: total %d", total)
+	}
+}
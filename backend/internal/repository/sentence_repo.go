@@ -19,12 +19,12 @@ func NewSentenceRepository(db *gorm.DB) SentenceRepository {
 }
 
 func (r *sentenceRepository) Create(ctx context.Context, sentence *model.Sentence) error {
-	return r.db.WithContext(ctx).Create(sentence).Error
+	return translateError(dbFrom(ctx, r.db).Create(sentence).Error)
 }
 
 func (r *sentenceRepository) GetByID(ctx context.Context, id uint) (*model.Sentence, error) {
 	var sentence model.Sentence
-	err := r.db.WithContext(ctx).First(&sentence, id).Error
+	err := dbFrom(ctx, r.db).First(&sentence, id).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrNotFound
@@ -34,28 +34,88 @@ func (r *sentenceRepository) GetByID(ctx context.Context, id uint) (*model.Sente
 	return &sentence, nil
 }
 
+func (r *sentenceRepository) GetByIDs(ctx context.Context, ids []uint) ([]*model.Sentence, error) {
+	var sentences []*model.Sentence
+	if len(ids) == 0 {
+		return sentences, nil
+	}
+	err := dbFrom(ctx, r.db).Where("id IN ?", ids).Find(&sentences).Error
+	if err != nil {
+		return nil, err
+	}
+	return sentences, nil
+}
+
 func (r *sentenceRepository) GetAll(ctx context.Context) ([]*model.Sentence, error) {
 	var sentences []*model.Sentence
-	err := r.db.WithContext(ctx).Find(&sentences).Error
+	err := dbFrom(ctx, r.db).Find(&sentences).Error
 	if err != nil {
 		return nil, err
 	}
 	return sentences, nil
 }
 
+func (r *sentenceRepository) List(ctx context.Context, filter SentenceFilter, page PageRequest) ([]*model.Sentence, int64, error) {
+	query := dbFrom(ctx, r.db).Model(&model.Sentence{})
+	if filter.SceneID != 0 {
+		query = query.Where("sentences.scene_id = ?", filter.SceneID)
+	}
+	if filter.Difficulty != "" {
+		query = query.Where("sentences.difficulty = ?", filter.Difficulty)
+	}
+	if filter.Language != "" {
+		query = query.Where("sentences.language = ?", filter.Language)
+	}
+	if filter.Query != "" {
+		pattern := likePattern(filter.Query)
+		query = query.Where(containsCondition(r.db, "sentences.content", "sentences.translation"), pattern, pattern)
+	}
+	if filter.HasAudio != nil {
+		if *filter.HasAudio {
+			query = query.Where("sentences.audio_url IS NOT NULL AND sentences.audio_url <> ''")
+		} else {
+			query = query.Where("(sentences.audio_url IS NULL OR sentences.audio_url = '')")
+		}
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var sentences []*model.Sentence
+	if err := applyPage(query.Session(&gorm.Session{}), "sentences", page).Find(&sentences).Error; err != nil {
+		return nil, 0, err
+	}
+	return sentences, total, nil
+}
+
 func (r *sentenceRepository) GetBySceneID(ctx context.Context, sceneID uint) ([]*model.Sentence, error) {
 	var sentences []*model.Sentence
-	err := r.db.WithContext(ctx).Where("scene_id = ?", sceneID).Find(&sentences).Error
+	err := dbFrom(ctx, r.db).Where("scene_id = ?", sceneID).Find(&sentences).Error
 	if err != nil {
 		return nil, err
 	}
 	return sentences, nil
 }
 
+func (r *sentenceRepository) CountBySceneID(ctx context.Context, sceneID uint) (int64, error) {
+	var count int64
+	err := dbFrom(ctx, r.db).Model(&model.Sentence{}).Where("scene_id = ?", sceneID).Count(&count).Error
+	return count, err
+}
+
 func (r *sentenceRepository) Update(ctx context.Context, sentence *model.Sentence) error {
-	return r.db.WithContext(ctx).Save(sentence).Error
+	return translateError(dbFrom(ctx, r.db).Save(sentence).Error)
 }
 
 func (r *sentenceRepository) Delete(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Delete(&model.Sentence{}, id).Error
+	result := dbFrom(ctx, r.db).Delete(&model.Sentence{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
 }
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"voicewriter/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+// NewSessionRepository 创建练习会话仓储实例
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &sessionRepository{db: db}
+}
+
+func (r *sessionRepository) Create(ctx context.Context, session *model.PracticeSession) error {
+	// 只写入会话和句子的作答记录，不回写关联的句子内容
+	return translateError(dbFrom(ctx, r.db).Omit("Items.Sentence").Create(session).Error)
+}
+
+func (r *sessionRepository) GetByID(ctx context.Context, id uint) (*model.PracticeSession, error) {
+	var session model.PracticeSession
+	err := dbFrom(ctx, r.db).
+		Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		Preload("Items.Sentence").
+		First(&session, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *sessionRepository) List(ctx context.Context, filter SessionFilter, page PageRequest) ([]*model.PracticeSession, int64, error) {
+	query := dbFrom(ctx, r.db).Model(&model.PracticeSession{})
+	if filter.UserID != "" {
+		query = query.Where("practice_sessions.user_id = ?", filter.UserID)
+	}
+	if filter.Status != "" {
+		query = query.Where("practice_sessions.status = ?", filter.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var sessions []*model.PracticeSession
+	if err := applyPage(query, "practice_sessions", page).Find(&sessions).Error; err != nil {
+		return nil, 0, err
+	}
+	return sessions, total, nil
+}
+
+func (r *sessionRepository) LockActive(ctx context.Context, id uint) (bool, error) {
+	// 用带条件的 UPDATE 取得行锁，各数据库通用，不依赖 SELECT ... FOR UPDATE
+	result := dbFrom(ctx, r.db).Model(&model.PracticeSession{}).
+		Where("id = ? AND status = ?", id, model.SessionStatusActive).
+		UpdateColumn("updated_at", time.Now())
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *sessionRepository) Update(ctx context.Context, session *model.PracticeSession) error {
+	return translateError(dbFrom(ctx, r.db).Omit(clause.Associations).Save(session).Error)
+}
+
+func (r *sessionRepository) UpdateItem(ctx context.Context, item *model.PracticeSessionItem) error {
+	return translateError(dbFrom(ctx, r.db).Omit(clause.Associations).Save(item).Error)
+}
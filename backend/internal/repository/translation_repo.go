@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+
+	"voicewriter/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type translationRepository struct {
+	db *gorm.DB
+}
+
+// NewTranslationRepository 创建多语言翻译仓储实例
+func NewTranslationRepository(db *gorm.DB) TranslationRepository {
+	return &translationRepository{db: db}
+}
+
+func (r *translationRepository) ListSentenceTranslations(ctx context.Context, sentenceIDs []uint, locales []string) ([]*model.SentenceTranslation, error) {
+	var translations []*model.SentenceTranslation
+	if len(sentenceIDs) == 0 {
+		return translations, nil
+	}
+
+	query := dbFrom(ctx, r.db).Where("sentence_id IN ?", sentenceIDs)
+	if len(locales) > 0 {
+		query = query.Where("locale IN ?", locales)
+	}
+	if err := query.Order("sentence_id, locale").Find(&translations).Error; err != nil {
+		return nil, err
+	}
+	return translations, nil
+}
+
+func (r *translationRepository) UpsertSentenceTranslation(ctx context.Context, translation *model.SentenceTranslation) error {
+	// 已软删除的翻译仍占用唯一索引，覆盖内容的同时恢复
+	err := dbFrom(ctx, r.db).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "sentence_id"}, {Name: "locale"}},
+		DoUpdates: append(clause.AssignmentColumns([]string{"text", "updated_at"}),
+			clause.Assignment{Column: clause.Column{Name: "deleted_at"}, Value: nil}),
+	}).Create(translation).Error
+	return translateError(err)
+}
+
+func (r *translationRepository) DeleteSentenceTranslation(ctx context.Context, sentenceID uint, locale string) error {
+	result := dbFrom(ctx, r.db).
+		Where("sentence_id = ? AND locale = ?", sentenceID, locale).
+		Delete(&model.SentenceTranslation{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *translationRepository) ListSceneTranslations(ctx context.Context, sceneIDs []uint, locales []string) ([]*model.SceneTranslation, error) {
+	var translations []*model.SceneTranslation
+	if len(sceneIDs) == 0 {
+		return translations, nil
+	}
+
+	query := dbFrom(ctx, r.db).Where("scene_id IN ?", sceneIDs)
+	if len(locales) > 0 {
+		query = query.Where("locale IN ?", locales)
+	}
+	if err := query.Order("scene_id, locale").Find(&translations).Error; err != nil {
+		return nil, err
+	}
+	return translations, nil
+}
+
+func (r *translationRepository) UpsertSceneTranslation(ctx context.Context, translation *model.SceneTranslation) error {
+	err := dbFrom(ctx, r.db).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "scene_id"}, {Name: "locale"}},
+		DoUpdates: append(clause.AssignmentColumns([]string{"name", "description", "updated_at"}),
+			clause.Assignment{Column: clause.Column{Name: "deleted_at"}, Value: nil}),
+	}).Create(translation).Error
+	return translateError(err)
+}
+
+func (r *translationRepository) DeleteSceneTranslation(ctx context.Context, sceneID uint, locale string) error {
+	result := dbFrom(ctx, r.db).
+		Where("scene_id = ? AND locale = ?", sceneID, locale).
+		Delete(&model.SceneTranslation{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
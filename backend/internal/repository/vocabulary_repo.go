@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"voicewriter/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type vocabularyRepository struct {
+	db *gorm.DB
+}
+
+// NewVocabularyRepository 创建生词本仓储实例
+func NewVocabularyRepository(db *gorm.DB) VocabularyRepository {
+	return &vocabularyRepository{db: db}
+}
+
+func (r *vocabularyRepository) RecordMisses(ctx context.Context, userID, language string, sentenceID uint, words []string, at time.Time) error {
+	if len(words) == 0 {
+		return nil
+	}
+	entries := make([]*model.VocabularyEntry, len(words))
+	for i, word := range words {
+		entries[i] = &model.VocabularyEntry{
+			UserID:     userID,
+			Language:   language,
+			Word:       word,
+			SentenceID: sentenceID,
+			MissCount:  1,
+			LastSeenAt: at,
+		}
+	}
+	// PostgreSQL 的 ON CONFLICT 中未限定表名的列与 excluded 有歧义，因此累加时带上表名
+	// 已软删除的词按新记录重新计数；MySQL 按书写顺序求值 SET 子句，deleted_at 必须最后清空
+	err := dbFrom(ctx, r.db).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "language"}, {Name: "word"}},
+		DoUpdates: append(clause.AssignmentColumns([]string{"sentence_id", "last_seen_at", "updated_at"}),
+			clause.Assignment{Column: clause.Column{Name: "miss_count"}, Value: gorm.Expr("CASE WHEN vocabulary.deleted_at IS NULL THEN vocabulary.miss_count + 1 ELSE 1 END")},
+			clause.Assignment{Column: clause.Column{Name: "starred"}, Value: gorm.Expr("CASE WHEN vocabulary.deleted_at IS NULL THEN vocabulary.starred ELSE ? END", false)},
+			clause.Assignment{Column: clause.Column{Name: "archived"}, Value: false},
+			clause.Assignment{Column: clause.Column{Name: "deleted_at"}, Value: nil},
+		),
+	}).Create(&entries).Error
+	return translateError(err)
+}
+
+func (r *vocabularyRepository) GetByID(ctx context.Context, id uint) (*model.VocabularyEntry, error) {
+	var entry model.VocabularyEntry
+	err := dbFrom(ctx, r.db).First(&entry, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *vocabularyRepository) List(ctx context.Context, filter VocabularyFilter, page PageRequest) ([]*model.VocabularyEntry, int64, error) {
+	query := dbFrom(ctx, r.db).Model(&model.VocabularyEntry{})
+	if filter.UserID != "" {
+		query = query.Where("vocabulary.user_id = ?", filter.UserID)
+	}
+	if filter.Language != "" {
+		query = query.Where("vocabulary.language = ?", filter.Language)
+	}
+	if filter.Query != "" {
+		query = query.Where(containsCondition(r.db, "vocabulary.word"), likePattern(filter.Query))
+	}
+	if filter.Starred != nil {
+		query = query.Where("vocabulary.starred = ?", *filter.Starred)
+	}
+	if filter.Archived != nil {
+		query = query.Where("vocabulary.archived = ?", *filter.Archived)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var entries []*model.VocabularyEntry
+	if err := applyPage(query, "vocabulary", page).Preload("Sentence").Find(&entries).Error; err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+func (r *vocabularyRepository) Update(ctx context.Context, entry *model.VocabularyEntry) error {
+	return translateError(dbFrom(ctx, r.db).Omit(clause.Associations).Save(entry).Error)
+}
+
+func (r *vocabularyRepository) ReassignUser(ctx context.Context, fromUserID, toUserID string) (int64, error) {
+	var moved int64
+	err := dbFrom(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		// 目标用户已软删除的记录仍占用唯一索引，先彻底清除
+		if err := tx.Unscoped().Where("user_id = ? AND deleted_at IS NOT NULL", toUserID).Delete(&model.VocabularyEntry{}).Error; err != nil {
+			return err
+		}
+		var owned []*model.VocabularyEntry
+		if err := tx.Where("user_id = ?", toUserID).Find(&owned).Error; err != nil {
+			return err
+		}
+		byWord := make(map[[2]string]*model.VocabularyEntry, len(owned))
+		for _, entry := range owned {
+			byWord[[2]string{entry.Language, entry.Word}] = entry
+		}
+
+		var entries []*model.VocabularyEntry
+		if err := tx.Where("user_id = ?", fromUserID).Find(&entries).Error; err != nil {
+			return err
+		}
+		var ids []uint
+		for _, entry := range entries {
+			target, ok := byWord[[2]string{entry.Language, entry.Word}]
+			if !ok {
+				ids = append(ids, entry.ID)
+				continue
+			}
+			// 同一个词合并到目标用户的记录，来源句子取最近出错的一次
+			target.MissCount += entry.MissCount
+			target.Starred = target.Starred || entry.Starred
+			if entry.LastSeenAt.After(target.LastSeenAt) {
+				target.LastSeenAt = entry.LastSeenAt
+				target.SentenceID = entry.SentenceID
+			}
+			if err := tx.Save(target).Error; err != nil {
+				return err
+			}
+			moved++
+		}
+		if len(ids) > 0 {
+			result := tx.Model(&model.VocabularyEntry{}).Where("id IN ?", ids).Update("user_id", toUserID)
+			if result.Error != nil {
+				return result.Error
+			}
+			moved += result.RowsAffected
+		}
+		return tx.Where("user_id = ?", fromUserID).Delete(&model.VocabularyEntry{}).Error
+	})
+	return moved, err
+}
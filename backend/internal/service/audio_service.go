@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"voicewriter/internal/config"
+	"voicewriter/internal/metrics"
+	"voicewriter/internal/repository"
+	"voicewriter/internal/tts"
+	"voicewriter/internal/worker"
+	apperrors "voicewriter/pkg/errors"
+)
+
+// AudioFile 可供下载的句子音频，调用方负责关闭 File
+type AudioFile struct {
+	*tts.CachedAudio
+	Name string
+	ETag string
+}
+
+// AudioService 句子音频服务：优先读缓存，未命中时调用合成器生成并写入缓存
+// 合成在后台任务组中进行，客户端断开或服务退出时已开始的合成仍会完成并写入缓存
+type AudioService struct {
+	sentenceRepo repository.SentenceRepository
+	synthesizer  tts.Synthesizer
+	cache        *tts.FileCache
+	workers      *worker.Group
+	cfg          config.TTSConfig
+
+	cacheLookups *metrics.CounterVec
+}
+
+// NewAudioService 创建句子音频服务实例
+func NewAudioService(
+	sentenceRepo repository.SentenceRepository,
+	synthesizer tts.Synthesizer,
+	cache *tts.FileCache,
+	workers *worker.Group,
+	cfg config.TTSConfig,
+) *AudioService {
+	return &AudioService{
+		sentenceRepo: sentenceRepo,
+		synthesizer:  synthesizer,
+		cache:        cache,
+		workers:      workers,
+		cfg:          cfg,
+		cacheLookups: metrics.NewCounterVec("tts_cache_lookups_total", "Audio cache lookups by result (hit or miss).", "result"),
+	}
+}
+
+// RegisterMetrics 注册音频缓存命中次数和命中率指标
+func (s *AudioService) RegisterMetrics(registry *metrics.Registry) {
+	registry.Register(s.cacheLookups, metrics.CollectorFunc(func() []*metrics.Family {
+		hits, misses := s.cacheLookups.Value("hit"), s.cacheLookups.Value("miss")
+		ratio := 0.0
+		if hits+misses > 0 {
+			ratio = hits / (hits + misses)
+		}
+		return []*metrics.Family{
+			metrics.Gauge(metrics.Namespace+"_tts_cache_hit_ratio", "Ratio of audio requests served from the cache since start.", ratio),
+		}
+	}))
+}
+
+// GetSentenceAudio 获取句子音频
+func (s *AudioService) GetSentenceAudio(ctx context.Context, sentenceID uint) (*AudioFile, error) {
+	if sentenceID == 0 {
+		return nil, invalidf("invalid sentence id")
+	}
+
+	sentence, err := s.sentenceRepo.GetByID(ctx, sentenceID)
+	if err != nil {
+		return nil, notFound(err, "sentence")
+	}
+
+	// 按句子自身的语言合成，旧数据未设置语言时使用配置的默认语言
+	language := sentence.Language
+	if language == "" {
+		language = s.cfg.Language
+	}
+	req := &tts.Request{
+		Text:     sentence.Content,
+		Language: language,
+		Voice:    s.cfg.Voice,
+		Speed:    s.cfg.Speed,
+	}
+	key := tts.Key(sentence.ID, s.synthesizer.Name(), req)
+
+	cached, err := s.cache.Open(key)
+	if errors.Is(err, tts.ErrCacheMiss) {
+		s.cacheLookups.Inc("miss")
+		cached, err = s.generate(ctx, key, req)
+	} else if err == nil {
+		s.cacheLookups.Inc("hit")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &AudioFile{
+		CachedAudio: cached,
+		Name:        key,
+		ETag:        `"` + key + `"`,
+	}, nil
+}
+
+// generate 在后台合成音频并写入缓存，请求取消时不再等待，但合成会继续完成
+func (s *AudioService) generate(ctx context.Context, key string, req *tts.Request) (*tts.CachedAudio, error) {
+	done := make(chan error, 1)
+	err := s.workers.Go("synthesize audio "+key, func(ctx context.Context) error {
+		audio, err := s.synthesizer.Synthesize(ctx, req)
+		if err != nil {
+			err = fmt.Errorf("failed to synthesize audio: %w", err)
+		} else {
+			err = s.cache.Put(key, audio)
+		}
+		done <- err
+		return err
+	})
+	if errors.Is(err, worker.ErrClosed) {
+		return nil, apperrors.Wrap(apperrors.KindUnavailable, err, "server is shutting down")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return nil, err
+		}
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return s.cache.Open(key)
+}
@@ -0,0 +1,411 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"voicewriter/internal/auth"
+	"voicewriter/internal/model"
+	"voicewriter/internal/repository"
+	apperrors "voicewriter/pkg/errors"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	// ErrInvalidCredentials 用户名或密码错误
+	ErrInvalidCredentials = apperrors.New(apperrors.KindUnauthorized, "invalid username or password")
+	// ErrUsernameTaken 用户名已被注册
+	ErrUsernameTaken = apperrors.New(apperrors.KindConflict, "username already taken")
+	// ErrInvalidRegistration 注册信息不符合要求
+	ErrInvalidRegistration = apperrors.New(apperrors.KindValidation, "invalid registration")
+	// ErrClaimNotAllowed 匿名ID不是服务端签发的，或认领密钥不正确
+	ErrClaimNotAllowed = apperrors.New(apperrors.KindForbidden, "anonymous id cannot be claimed")
+	// ErrAlreadyClaimed 匿名ID已被认领过
+	ErrAlreadyClaimed = apperrors.New(apperrors.KindConflict, "anonymous id already claimed")
+	// ErrIssuedAnonymous 服务端签发的匿名ID只能由持有者凭认领密钥认领，不能按旧ID迁移
+	ErrIssuedAnonymous = apperrors.New(apperrors.KindConflict, "anonymous id was issued by the server and must be claimed with its claim secret")
+)
+
+// usernamePattern 用户名只允许字母、数字和下划线
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9_]{3,50}$`)
+
+// minPasswordLength 密码最短长度
+const minPasswordLength = 8
+
+// claimSecretBytes 认领密钥的随机字节数
+const claimSecretBytes = 32
+
+// RegisterRequest 注册请求
+type RegisterRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// LoginRequest 登录请求
+type LoginRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// RefreshRequest 刷新令牌请求
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// ClaimRequest 认领匿名进度请求，认领密钥为签发匿名ID时一同返回的 claim_secret
+type ClaimRequest struct {
+	AnonymousID string `json:"anonymous_id" binding:"required"`
+	ClaimSecret string `json:"claim_secret" binding:"required"`
+}
+
+// LegacyClaimRequest 管理员迁移旧匿名ID请求：将客户端自行生成、没有认领密钥的匿名ID下的数据转入指定用户
+type LegacyClaimRequest struct {
+	AnonymousID string `json:"anonymous_id" binding:"required"`
+	UserUID     string `json:"user_uid" binding:"required"`
+}
+
+// AnonymousCredentials 签发给未登录客户端的匿名ID、认领密钥和匿名身份的令牌，密钥只在签发时返回一次
+type AnonymousCredentials struct {
+	AnonymousID string
+	ClaimSecret string
+	Tokens      *auth.TokenPair
+}
+
+// AuthResult 注册/登录结果，匿名身份刷新令牌时 User 为 nil
+type AuthResult struct {
+	User   *model.User     `json:"user"`
+	Tokens *auth.TokenPair `json:"tokens"`
+}
+
+// ClaimResult 认领匿名进度结果，各字段为转入的记录数
+type ClaimResult struct {
+	Progress   int64
+	Attempts   int64
+	Reviews    int64
+	Vocabulary int64
+}
+
+// AuthService 用户认证服务
+type AuthService struct {
+	userRepo       repository.UserRepository
+	anonymousRepo  repository.AnonymousRepository
+	progressRepo   repository.ProgressRepository
+	attemptRepo    repository.AttemptRepository
+	reviewRepo     repository.ReviewRepository
+	vocabularyRepo repository.VocabularyRepository
+	txManager      repository.TxManager
+	tokens         *auth.TokenManager
+}
+
+// NewAuthService 创建用户认证服务实例
+func NewAuthService(
+	userRepo repository.UserRepository,
+	anonymousRepo repository.AnonymousRepository,
+	progressRepo repository.ProgressRepository,
+	attemptRepo repository.AttemptRepository,
+	reviewRepo repository.ReviewRepository,
+	vocabularyRepo repository.VocabularyRepository,
+	txManager repository.TxManager,
+	tokens *auth.TokenManager,
+) *AuthService {
+	return &AuthService{
+		userRepo:       userRepo,
+		anonymousRepo:  anonymousRepo,
+		progressRepo:   progressRepo,
+		attemptRepo:    attemptRepo,
+		reviewRepo:     reviewRepo,
+		vocabularyRepo: vocabularyRepo,
+		txManager:      txManager,
+		tokens:         tokens,
+	}
+}
+
+// Register 注册新用户并签发令牌
+func (s *AuthService) Register(ctx context.Context, req *RegisterRequest) (*AuthResult, error) {
+	username := strings.TrimSpace(req.Username)
+	if err := validateCredentials(username, req.Password); err != nil {
+		return nil, err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	user := &model.User{
+		UID:          uuid.NewString(),
+		Username:     username,
+		PasswordHash: string(hash),
+		Role:         model.RoleUser,
+	}
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		if errors.Is(err, repository.ErrDuplicateKey) {
+			return nil, ErrUsernameTaken
+		}
+		return nil, err
+	}
+
+	return s.issue(user)
+}
+
+// Login 校验用户名密码并签发令牌
+func (s *AuthService) Login(ctx context.Context, req *LoginRequest) (*AuthResult, error) {
+	user, err := s.userRepo.GetByUsername(ctx, strings.TrimSpace(req.Username))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	return s.issue(user)
+}
+
+// Refresh 用刷新令牌换取新令牌，重新读取用户以反映角色变化；匿名身份在匿名ID被认领后不能再刷新
+func (s *AuthService) Refresh(ctx context.Context, req *RefreshRequest) (*AuthResult, error) {
+	claims, err := s.tokens.Parse(req.RefreshToken, auth.TokenTypeRefresh)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Role == model.RoleAnonymous {
+		anonymous, err := s.anonymousRepo.GetByAnonymousID(ctx, claims.Subject)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil, auth.ErrInvalidToken
+			}
+			return nil, err
+		}
+		if anonymous.ClaimedAt != nil {
+			return nil, auth.ErrInvalidToken
+		}
+		tokens, err := s.issueAnonymous(anonymous.AnonymousID)
+		if err != nil {
+			return nil, err
+		}
+		return &AuthResult{Tokens: tokens}, nil
+	}
+
+	user, err := s.userRepo.GetByUID(ctx, claims.Subject)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, auth.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	return s.issue(user)
+}
+
+// EnsureAdmin 确保配置中的初始管理员账户存在：不存在时创建，已存在时提升为管理员
+func (s *AuthService) EnsureAdmin(ctx context.Context, username, password string) error {
+	user, err := s.userRepo.GetByUsername(ctx, username)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+
+	if user != nil {
+		if user.Role == model.RoleAdmin {
+			return nil
+		}
+		user.Role = model.RoleAdmin
+		return s.userRepo.Update(ctx, user)
+	}
+
+	if err := validateCredentials(username, password); err != nil {
+		return err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+	return s.userRepo.Create(ctx, &model.User{
+		UID:          uuid.NewString(),
+		Username:     username,
+		PasswordHash: string(hash),
+		Role:         model.RoleAdmin,
+	})
+}
+
+// GetUser 根据用户标识获取用户
+func (s *AuthService) GetUser(ctx context.Context, uid string) (*model.User, error) {
+	if uid == "" {
+		return nil, invalidf("user id is required")
+	}
+	user, err := s.userRepo.GetByUID(ctx, uid)
+	if err != nil {
+		return nil, notFound(err, "user")
+	}
+	return user, nil
+}
+
+// IssueAnonymous 为未登录客户端签发匿名ID、认领密钥和匿名身份的令牌，数据库只保存密钥的哈希
+// 匿名令牌可以访问进度、复习、生词本和练习会话接口，数据记在匿名ID下，注册后凭密钥认领
+func (s *AuthService) IssueAnonymous(ctx context.Context) (*AnonymousCredentials, error) {
+	secret := make([]byte, claimSecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate claim secret: %w", err)
+	}
+	credentials := &AnonymousCredentials{
+		AnonymousID: "anon-" + uuid.NewString(),
+		ClaimSecret: base64.RawURLEncoding.EncodeToString(secret),
+	}
+	if err := s.anonymousRepo.Create(ctx, &model.AnonymousID{
+		AnonymousID: credentials.AnonymousID,
+		SecretHash:  hashClaimSecret(credentials.ClaimSecret),
+	}); err != nil {
+		return nil, err
+	}
+	tokens, err := s.issueAnonymous(credentials.AnonymousID)
+	if err != nil {
+		return nil, err
+	}
+	credentials.Tokens = tokens
+	return credentials, nil
+}
+
+// ClaimAnonymous 凭认领密钥将匿名用户ID下的进度、作答记录、复习计划和生词本转入当前账户，每个匿名ID只能认领一次
+func (s *AuthService) ClaimAnonymous(ctx context.Context, uid string, req *ClaimRequest) (*ClaimResult, error) {
+	anonymousID := strings.TrimSpace(req.AnonymousID)
+	if anonymousID == "" || req.ClaimSecret == "" {
+		return nil, invalidf("anonymous id and claim secret are required")
+	}
+
+	// 只有服务端签发、且持有对应密钥的匿名ID可以认领；不区分"不存在"和"密钥错误"，避免探测
+	anonymous, err := s.anonymousRepo.GetByAnonymousID(ctx, anonymousID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrClaimNotAllowed
+		}
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(hashClaimSecret(req.ClaimSecret)), []byte(anonymous.SecretHash)) != 1 {
+		return nil, ErrClaimNotAllowed
+	}
+
+	return s.claim(ctx, anonymousID, uid, func(ctx context.Context) (bool, error) {
+		return s.anonymousRepo.MarkClaimed(ctx, anonymousID, uid, time.Now())
+	})
+}
+
+// ClaimLegacyAnonymous 管理员将服务端签发匿名ID之前、客户端自行生成的匿名ID下的数据转入指定用户
+// 迁移后写入一条已认领的记录，同一ID不能再次迁移；服务端签发的ID只能由持有者凭密钥认领
+func (s *AuthService) ClaimLegacyAnonymous(ctx context.Context, req *LegacyClaimRequest) (*ClaimResult, error) {
+	anonymousID := strings.TrimSpace(req.AnonymousID)
+	uid := strings.TrimSpace(req.UserUID)
+	if anonymousID == "" || uid == "" {
+		return nil, invalidf("anonymous id and user uid are required")
+	}
+	if _, err := s.userRepo.GetByUID(ctx, uid); err != nil {
+		return nil, notFound(err, "user")
+	}
+	// 注册用户的数据不能当作匿名数据转走
+	if _, err := s.userRepo.GetByUID(ctx, anonymousID); err == nil {
+		return nil, invalidf("%s is a registered user", anonymousID)
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+
+	anonymous, err := s.anonymousRepo.GetByAnonymousID(ctx, anonymousID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+	if anonymous != nil {
+		if anonymous.ClaimedAt != nil {
+			return nil, ErrAlreadyClaimed
+		}
+		return nil, ErrIssuedAnonymous
+	}
+
+	return s.claim(ctx, anonymousID, uid, func(ctx context.Context) (bool, error) {
+		now := time.Now()
+		err := s.anonymousRepo.Create(ctx, &model.AnonymousID{AnonymousID: anonymousID, ClaimedBy: &uid, ClaimedAt: &now})
+		if errors.Is(err, repository.ErrDuplicateKey) {
+			return false, nil
+		}
+		return err == nil, err
+	})
+}
+
+// claim 在同一事务内标记认领并转移各表的数据，避免只认领了一部分；mark 返回 false 表示已被认领，
+// 并发认领同一ID时只有一个能标记成功
+func (s *AuthService) claim(ctx context.Context, anonymousID, uid string, mark func(ctx context.Context) (bool, error)) (*ClaimResult, error) {
+	var result ClaimResult
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		claimed, err := mark(ctx)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			return ErrAlreadyClaimed
+		}
+		if result.Progress, err = s.progressRepo.ReassignUser(ctx, anonymousID, uid); err != nil {
+			return err
+		}
+		if result.Attempts, err = s.attemptRepo.ReassignUser(ctx, anonymousID, uid); err != nil {
+			return err
+		}
+		if result.Reviews, err = s.reviewRepo.ReassignUser(ctx, anonymousID, uid); err != nil {
+			return err
+		}
+		result.Vocabulary, err = s.vocabularyRepo.ReassignUser(ctx, anonymousID, uid)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// hashClaimSecret 认领密钥是高熵随机值，用 SHA-256 保存即可，不需要 bcrypt
+func hashClaimSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// issueAnonymous 为匿名ID签发令牌，身份中没有用户记录的 ID 和用户名
+func (s *AuthService) issueAnonymous(anonymousID string) (*auth.TokenPair, error) {
+	return s.tokens.Issue(&auth.Identity{UID: anonymousID, Role: model.RoleAnonymous})
+}
+
+func (s *AuthService) issue(user *model.User) (*AuthResult, error) {
+	tokens, err := s.tokens.Issue(&auth.Identity{
+		UserID:   user.ID,
+		UID:      user.UID,
+		Username: user.Username,
+		Role:     user.Role,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &AuthResult{User: user, Tokens: tokens}, nil
+}
+
+func validateCredentials(username, password string) error {
+	if !usernamePattern.MatchString(username) {
+		return fmt.Errorf("%w: username must be 3-50 letters, digits or underscores", ErrInvalidRegistration)
+	}
+	if len(password) < minPasswordLength {
+		return fmt.Errorf("%w: password must be at least %d characters", ErrInvalidRegistration, minPasswordLength)
+	}
+	if len(password) > 72 {
+		// bcrypt 只使用前 72 字节
+		return fmt.Errorf("%w: password must be at most 72 bytes", ErrInvalidRegistration)
+	}
+	return nil
+}
@@ -0,0 +1,315 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"voicewriter/internal/content"
+	"voicewriter/internal/i18n"
+	"voicewriter/internal/model"
+	"voicewriter/internal/repository"
+	apperrors "voicewriter/pkg/errors"
+)
+
+var (
+	// ErrInvalidImportFile 导入文件无法按指定格式解析
+	ErrInvalidImportFile = apperrors.New(apperrors.KindValidation, "invalid import file")
+	// ErrImportInvalid 导入数据中存在不合法的行，整批未写入；Import 返回的是包装它的 *ImportInvalidError
+	ErrImportInvalid = apperrors.New(apperrors.KindValidation, "import contains invalid rows")
+)
+
+// ImportInvalidError 导入数据中存在不合法的行，Report 中列出每一行的错误
+type ImportInvalidError struct {
+	Report *ImportReport
+}
+
+func (e *ImportInvalidError) Error() string {
+	return ErrImportInvalid.Error()
+}
+
+func (e *ImportInvalidError) Unwrap() error {
+	return ErrImportInvalid
+}
+
+// 与 model.Scene / model.Sentence 列定义一致的长度限制
+const (
+	maxSceneNameLength = 100
+	maxAudioURLLength  = 255
+)
+
+// validDifficulties 句子难度取值
+var validDifficulties = map[string]bool{"easy": true, "medium": true, "hard": true}
+
+// ImportOptions 导入选项
+type ImportOptions struct {
+	Format       string // 为空时按 Filename 的扩展名识别
+	Filename     string
+	DryRun       bool
+	DefaultScene string // 行内未指定场景时使用的场景名
+}
+
+// ImportError 导入时某一行的校验错误
+type ImportError struct {
+	Line    int
+	Message string
+}
+
+// ImportReport 导入结果报告；DryRun 时只校验不写入
+type ImportReport struct {
+	Format        string
+	DryRun        bool
+	Total         int
+	Created       int
+	Skipped       int // 与已有句子或本批前面的行重复
+	ScenesCreated []string
+	Errors        []ImportError
+}
+
+// ContentService 内容批量导入导出服务
+type ContentService struct {
+	contentRepo  repository.ContentRepository
+	sceneRepo    repository.SceneRepository
+	sentenceRepo repository.SentenceRepository
+	searchIndex  repository.SearchIndex
+}
+
+// NewContentService 创建内容导入导出服务实例
+func NewContentService(
+	contentRepo repository.ContentRepository,
+	sceneRepo repository.SceneRepository,
+	sentenceRepo repository.SentenceRepository,
+	searchIndex repository.SearchIndex,
+) *ContentService {
+	return &ContentService{
+		contentRepo:  contentRepo,
+		sceneRepo:    sceneRepo,
+		sentenceRepo: sentenceRepo,
+		searchIndex:  searchIndex,
+	}
+}
+
+// Import 解析并校验整份数据，全部行合法时在同一事务中写入
+// 缺失的场景按名称自动创建，同一场景下内容相同的句子跳过；存在错误行时返回 *ImportInvalidError
+func (s *ContentService) Import(ctx context.Context, data []byte, opts *ImportOptions) (*ImportReport, error) {
+	format := opts.Format
+	if format == "" {
+		format = content.DetectFormat(opts.Filename)
+	}
+	if !content.Supported(format) {
+		return nil, invalidf("unsupported format %q, expected one of %v", format, content.Formats())
+	}
+	records, err := content.Decode(format, data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImportFile, err)
+	}
+
+	report := &ImportReport{
+		Format:        format,
+		DryRun:        opts.DryRun,
+		Total:         len(records),
+		ScenesCreated: []string{},
+		Errors:        []ImportError{},
+	}
+	plan := newImportPlan(s)
+	defaultScene := strings.TrimSpace(opts.DefaultScene)
+
+	for _, record := range records {
+		sentence, err := buildSentence(record, defaultScene)
+		if err != nil {
+			report.Errors = append(report.Errors, ImportError{Line: record.Line, Message: err.Error()})
+			continue
+		}
+
+		sceneName := sentence.Scene.Name
+		added, err := plan.add(ctx, sceneName, sentence)
+		if err != nil {
+			return nil, err
+		}
+		if !added {
+			report.Skipped++
+		}
+	}
+
+	for _, scene := range plan.newScenes {
+		report.ScenesCreated = append(report.ScenesCreated, scene.Name)
+	}
+	report.Created = len(plan.sentences)
+
+	if len(report.Errors) > 0 {
+		return nil, &ImportInvalidError{Report: report}
+	}
+	if opts.DryRun {
+		return report, nil
+	}
+	if err := s.contentRepo.ImportBatch(ctx, plan.newScenes, plan.sentences); err != nil {
+		return nil, err
+	}
+	// 导入的都是新句子，翻译表中还没有它们的翻译
+	if err := s.searchIndex.Index(ctx, searchDocuments(plan.sentences, nil)...); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// Export 将句子按指定格式写出，sceneID 为 0 时导出全部场景
+func (s *ContentService) Export(ctx context.Context, w io.Writer, format string, sceneID uint) error {
+	if !content.Supported(format) {
+		return invalidf("unsupported format %q, expected one of %v", format, content.Formats())
+	}
+
+	scenes, err := s.sceneRepo.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	sceneNames := make(map[uint]string, len(scenes))
+	for _, scene := range scenes {
+		sceneNames[scene.ID] = scene.Name
+	}
+
+	var sentences []*model.Sentence
+	if sceneID != 0 {
+		if _, ok := sceneNames[sceneID]; !ok {
+			return notFound(repository.ErrNotFound, "scene")
+		}
+		sentences, err = s.sentenceRepo.GetBySceneID(ctx, sceneID)
+	} else {
+		sentences, err = s.sentenceRepo.GetAll(ctx)
+	}
+	if err != nil {
+		return err
+	}
+
+	sort.SliceStable(sentences, func(i, j int) bool {
+		if sentences[i].SceneID != sentences[j].SceneID {
+			return sentences[i].SceneID < sentences[j].SceneID
+		}
+		return sentences[i].ID < sentences[j].ID
+	})
+
+	records := make([]content.Record, 0, len(sentences))
+	for _, sentence := range sentences {
+		records = append(records, content.Record{
+			Scene:       sceneNames[sentence.SceneID],
+			Language:    sentence.Language,
+			Content:     sentence.Content,
+			Translation: sentence.Translation,
+			Difficulty:  sentence.Difficulty,
+			AudioURL:    sentence.AudioURL,
+		})
+	}
+	return content.Encode(format, w, records)
+}
+
+// buildSentence 按 model.Sentence 的规则校验一行记录，Scene 只填名称
+func buildSentence(record content.Record, defaultScene string) (*model.Sentence, error) {
+	sceneName := strings.TrimSpace(record.Scene)
+	if sceneName == "" {
+		sceneName = defaultScene
+	}
+	if sceneName == "" {
+		return nil, invalidf("scene is required")
+	}
+	if utf8.RuneCountInString(sceneName) > maxSceneNameLength {
+		return nil, fmt.Errorf("scene name must be at most %d characters", maxSceneNameLength)
+	}
+
+	language := strings.TrimSpace(record.Language)
+	if language == "" {
+		language = i18n.DefaultTargetLanguage
+	}
+	language, err := i18n.Normalize(language)
+	if err != nil {
+		return nil, err
+	}
+
+	sentence := &model.Sentence{
+		Language:    language,
+		Content:     strings.TrimSpace(record.Content),
+		Translation: strings.TrimSpace(record.Translation),
+		AudioURL:    strings.TrimSpace(record.AudioURL),
+		Difficulty:  strings.ToLower(strings.TrimSpace(record.Difficulty)),
+		Scene:       &model.Scene{Name: sceneName},
+	}
+	if sentence.Content == "" {
+		return nil, invalidf("sentence content is required")
+	}
+	if len(sentence.AudioURL) > maxAudioURLLength {
+		return nil, fmt.Errorf("audio url must be at most %d characters", maxAudioURLLength)
+	}
+	if sentence.Difficulty == "" {
+		sentence.Difficulty = "easy"
+	}
+	if !validDifficulties[sentence.Difficulty] {
+		return nil, fmt.Errorf("invalid difficulty %q, must be easy, medium or hard", record.Difficulty)
+	}
+	return sentence, nil
+}
+
+// importPlan 记录待创建的场景和句子，并按场景缓存已有句子用于去重
+type importPlan struct {
+	service   *ContentService
+	scenes    map[string]*model.Scene
+	seen      map[string]map[string]bool
+	newScenes []*model.Scene
+	sentences []*model.Sentence
+}
+
+func newImportPlan(s *ContentService) *importPlan {
+	return &importPlan{
+		service: s,
+		scenes:  make(map[string]*model.Scene),
+		seen:    make(map[string]map[string]bool),
+	}
+}
+
+// add 将句子加入计划，返回 false 表示与已有句子重复
+func (p *importPlan) add(ctx context.Context, sceneName string, sentence *model.Sentence) (bool, error) {
+	scene, err := p.scene(ctx, sceneName)
+	if err != nil {
+		return false, err
+	}
+
+	if p.seen[sceneName][sentence.Content] {
+		return false, nil
+	}
+	p.seen[sceneName][sentence.Content] = true
+
+	sentence.SceneID = scene.ID
+	sentence.Scene = scene
+	p.sentences = append(p.sentences, sentence)
+	return true, nil
+}
+
+// scene 按名称查找场景，不存在时登记为待创建
+func (p *importPlan) scene(ctx context.Context, name string) (*model.Scene, error) {
+	if scene, ok := p.scenes[name]; ok {
+		return scene, nil
+	}
+
+	scene, err := p.service.sceneRepo.GetByName(ctx, name)
+	switch {
+	case err == nil:
+		existing, err := p.service.sentenceRepo.GetBySceneID(ctx, scene.ID)
+		if err != nil {
+			return nil, err
+		}
+		p.seen[name] = make(map[string]bool, len(existing))
+		for _, sentence := range existing {
+			p.seen[name][strings.TrimSpace(sentence.Content)] = true
+		}
+	case errors.Is(err, repository.ErrNotFound):
+		scene = &model.Scene{Name: name}
+		p.newScenes = append(p.newScenes, scene)
+		p.seen[name] = make(map[string]bool)
+	default:
+		return nil, err
+	}
+
+	p.scenes[name] = scene
+	return scene, nil
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"strings"
+
+	"voicewriter/internal/auth"
+	"voicewriter/internal/i18n"
+	"voicewriter/internal/repository"
+	apperrors "voicewriter/pkg/errors"
+)
+
+// invalidf 返回参数校验错误
+func invalidf(format string, args ...interface{}) error {
+	return apperrors.Newf(apperrors.KindValidation, format, args...)
+}
+
+// notFound 将仓储层的 ErrNotFound 转换为带资源名称的 NotFound 错误，其他错误原样返回
+func notFound(err error, resource string) error {
+	if errors.Is(err, repository.ErrNotFound) {
+		return apperrors.Wrap(apperrors.KindNotFound, err, resource+" not found")
+	}
+	return err
+}
+
+// KindOf 返回错误的类别：业务错误取其 Kind，仓储层和认证错误按含义归类，连接失败和超时归为不可用
+func KindOf(err error) apperrors.Kind {
+	if e, ok := apperrors.As(err); ok {
+		return e.Kind
+	}
+	switch {
+	case err == nil:
+		return apperrors.KindInternal
+	case errors.Is(err, repository.ErrNotFound):
+		return apperrors.KindNotFound
+	case errors.Is(err, repository.ErrDuplicateKey), errors.Is(err, repository.ErrForeignKey):
+		return apperrors.KindConflict
+	case errors.Is(err, i18n.ErrInvalidLocale):
+		return apperrors.KindValidation
+	case errors.Is(err, auth.ErrInvalidToken):
+		return apperrors.KindUnauthorized
+	case isUnavailable(err):
+		return apperrors.KindUnavailable
+	default:
+		return apperrors.KindInternal
+	}
+}
+
+// PublicMessage 返回可以展示给客户端的错误信息，内部错误和不可用错误不暴露原因
+func PublicMessage(err error) string {
+	switch KindOf(err) {
+	case apperrors.KindInternal:
+		return "internal server error"
+	case apperrors.KindUnavailable:
+		return "service temporarily unavailable"
+	}
+	// 带内部原因的业务错误只返回 Message，其余错误的文本本身就是给客户端的说明
+	if e, ok := apperrors.As(err); ok && e.Err != nil {
+		return e.Message
+	}
+	return err.Error()
+}
+
+// isUnavailable 判断错误是否由数据库连接失败或超时引起
+func isUnavailable(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	// database/sql 未导出连接池已关闭的错误
+	return strings.Contains(err.Error(), "sql: database is closed")
+}
@@ -0,0 +1,287 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"unicode"
+
+	"voicewriter/internal/model"
+	"voicewriter/internal/repository"
+	"voicewriter/internal/textnorm"
+)
+
+// TokenStatus 逐词比对状态
+type TokenStatus string
+
+const (
+	// TokenMatch 与原句一致
+	TokenMatch TokenStatus = "match"
+	// TokenMissing 原句中有但用户漏写
+	TokenMissing TokenStatus = "missing"
+	// TokenExtra 用户多写的词
+	TokenExtra TokenStatus = "extra"
+	// TokenMisspelled 位置对应但拼写错误
+	TokenMisspelled TokenStatus = "misspelled"
+)
+
+// misspellThreshold 两个词的相似度不低于该值时视为拼写错误，否则视为漏写+多写
+const misspellThreshold = 0.5
+
+// TokenDiff 单个词的比对结果
+type TokenDiff struct {
+	Status   TokenStatus
+	Expected string
+	Actual   string
+}
+
+// CheckAnswerRequest 听写答案检查请求
+type CheckAnswerRequest struct {
+	Answer       string `json:"answer" binding:"required,max=5000"`
+	DurationMs   int    `json:"duration_ms" binding:"min=0"`
+	AudioReplays int    `json:"audio_replays" binding:"min=0"`
+}
+
+// CheckResult 听写答案检查结果
+type CheckResult struct {
+	SentenceID uint
+	Answer     string
+	Corrected  string
+	Tokens     []TokenDiff
+	Accuracy   float64 // 0-100
+	Correct    bool
+}
+
+// GradingService 听写评分服务
+type GradingService struct {
+	sentenceRepo repository.SentenceRepository
+	sceneRepo    repository.SceneRepository
+}
+
+// NewGradingService 创建听写评分服务实例
+func NewGradingService(sentenceRepo repository.SentenceRepository, sceneRepo repository.SceneRepository) *GradingService {
+	return &GradingService{
+		sentenceRepo: sentenceRepo,
+		sceneRepo:    sceneRepo,
+	}
+}
+
+// CheckAnswer 将用户输入与句子原文逐词比对并打分
+func (s *GradingService) CheckAnswer(ctx context.Context, sentenceID uint, answer string) (*CheckResult, error) {
+	if sentenceID == 0 {
+		return nil, invalidf("invalid sentence id")
+	}
+	if strings.TrimSpace(answer) == "" {
+		return nil, invalidf("answer is required")
+	}
+
+	sentence, err := s.sentenceRepo.GetByID(ctx, sentenceID)
+	if err != nil {
+		return nil, notFound(err, "sentence")
+	}
+
+	normalizer, err := sentenceNormalizer(ctx, s.sceneRepo, sentence)
+	if err != nil {
+		return nil, err
+	}
+	tokens, accuracy := Grade(normalizer, sentence.Content, answer)
+	return &CheckResult{
+		SentenceID: sentence.ID,
+		Answer:     answer,
+		Corrected:  sentence.Content,
+		Tokens:     tokens,
+		Accuracy:   accuracy,
+		Correct:    isAllMatched(tokens),
+	}, nil
+}
+
+// Grade 用规范化流程处理原句和用户输入后做词级对齐，返回对齐结果和 0-100 的准确率
+func Grade(normalizer *textnorm.Normalizer, expected, actual string) ([]TokenDiff, float64) {
+	want := tokenize(normalizer, expected)
+	got := tokenize(normalizer, actual)
+	diffs := align(want, got)
+	return diffs, accuracy(diffs)
+}
+
+// sentenceNormalizer 按句子语言和所属场景的严格程度创建规范化流程，场景不存在时使用默认严格程度
+func sentenceNormalizer(ctx context.Context, sceneRepo repository.SceneRepository, sentence *model.Sentence) (*textnorm.Normalizer, error) {
+	scene, err := sceneRepo.GetByID(ctx, sentence.SceneID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+	strictness := textnorm.Normal
+	if scene != nil {
+		strictness = textnorm.Strictness(scene.Strictness)
+	}
+	return textnorm.New(sentence.Language, strictness), nil
+}
+
+// token 分词结果，raw 用于展示，norm 用于比较
+type token struct {
+	raw  string
+	norm string
+}
+
+// tokenize 整句规范化后按空白分词；中日韩文字没有空格分隔，按单字切分。
+// 缩写和数字展开为多个词时，各个词以展开后的形式展示
+func tokenize(normalizer *textnorm.Normalizer, text string) []token {
+	var tokens []token
+	for _, field := range strings.Fields(normalizer.Text(text)) {
+		var buf []rune
+		flush := func() {
+			if len(buf) == 0 {
+				return
+			}
+			raw := string(buf)
+			words := normalizer.Words(raw)
+			if len(words) == 1 {
+				tokens = append(tokens, token{raw: raw, norm: words[0]})
+			} else {
+				for _, word := range words {
+					tokens = append(tokens, token{raw: word, norm: word})
+				}
+			}
+			buf = buf[:0]
+		}
+		for _, r := range field {
+			if isCJK(r) {
+				flush()
+				buf = append(buf, r)
+				flush()
+				continue
+			}
+			buf = append(buf, r)
+		}
+		flush()
+	}
+	return tokens
+}
+
+// normalizeToken 生词本和错词统计使用的词形：按默认严格程度规范化，与场景设置无关，不展开缩写和数字
+func normalizeToken(language, raw string) string {
+	return textnorm.New(language, textnorm.Normal).Fold(raw)
+}
+
+func isCJK(r rune) bool {
+	return unicode.Is(unicode.Han, r) ||
+		unicode.Is(unicode.Hiragana, r) ||
+		unicode.Is(unicode.Katakana, r) ||
+		unicode.Is(unicode.Hangul, r)
+}
+
+// align 用编辑距离动态规划对齐两个词序列
+func align(want, got []token) []TokenDiff {
+	n, m := len(want), len(got)
+
+	// cost[i][j] 表示 want[i:] 与 got[j:] 的最小对齐代价
+	cost := make([][]int, n+1)
+	for i := range cost {
+		cost[i] = make([]int, m+1)
+	}
+	for i := n; i >= 0; i-- {
+		for j := m; j >= 0; j-- {
+			switch {
+			case i == n:
+				cost[i][j] = m - j
+			case j == m:
+				cost[i][j] = n - i
+			default:
+				best := cost[i+1][j+1] + substitutionCost(want[i], got[j])
+				best = min(best, cost[i+1][j]+1)
+				best = min(best, cost[i][j+1]+1)
+				cost[i][j] = best
+			}
+		}
+	}
+
+	diffs := make([]TokenDiff, 0, max(n, m))
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && substitutionCost(want[i], got[j]) < 2 &&
+			cost[i][j] == cost[i+1][j+1]+substitutionCost(want[i], got[j]):
+			status := TokenMisspelled
+			if want[i].norm == got[j].norm {
+				status = TokenMatch
+			}
+			diffs = append(diffs, TokenDiff{Status: status, Expected: want[i].raw, Actual: got[j].raw})
+			i++
+			j++
+		case i < n && cost[i][j] == cost[i+1][j]+1:
+			diffs = append(diffs, TokenDiff{Status: TokenMissing, Expected: want[i].raw})
+			i++
+		default:
+			diffs = append(diffs, TokenDiff{Status: TokenExtra, Actual: got[j].raw})
+			j++
+		}
+	}
+	return diffs
+}
+
+// substitutionCost 相同为 0；相似记为拼写错误代价 1；差异过大时代价 2，等价于漏写+多写
+func substitutionCost(a, b token) int {
+	if a.norm == b.norm {
+		return 0
+	}
+	if similarity(a.norm, b.norm) >= misspellThreshold {
+		return 1
+	}
+	return 2
+}
+
+// similarity 基于字符编辑距离的相似度，取值 0-1
+func similarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			sub := prev[j-1]
+			if a[i-1] != b[j-1] {
+				sub++
+			}
+			curr[j] = min(sub, prev[j]+1, curr[j-1]+1)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// accuracy 拼写错误计半分，多写的词计入分母
+func accuracy(diffs []TokenDiff) float64 {
+	if len(diffs) == 0 {
+		return 0
+	}
+	var score float64
+	for _, d := range diffs {
+		switch d.Status {
+		case TokenMatch:
+			score += 1
+		case TokenMisspelled:
+			score += 0.5
+		}
+	}
+	return math.Round(score/float64(len(diffs))*10000) / 100
+}
+
+func isAllMatched(diffs []TokenDiff) bool {
+	for _, d := range diffs {
+		if d.Status != TokenMatch {
+			return false
+		}
+	}
+	return len(diffs) > 0
+}
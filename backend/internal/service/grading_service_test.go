@@ -0,0 +1,132 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"voicewriter/internal/textnorm"
+)
+
+func TestGrade(t *testing.T) {
+	normalizer := textnorm.New("en", textnorm.Normal)
+	cases := []struct {
+		name     string
+		expected string
+		actual   string
+		statuses []TokenStatus
+		accuracy float64
+	}{
+		{
+			name:     "exact",
+			expected: "How are you?",
+			actual:   "how are you",
+			statuses: []TokenStatus{TokenMatch, TokenMatch, TokenMatch},
+			accuracy: 100,
+		},
+		{
+			name:     "deletion",
+			expected: "how are you",
+			actual:   "how you",
+			statuses: []TokenStatus{TokenMatch, TokenMissing, TokenMatch},
+			accuracy: 66.67,
+		},
+		{
+			name:     "insertion",
+			expected: "how are you",
+			actual:   "how are you today",
+			statuses: []TokenStatus{TokenMatch, TokenMatch, TokenMatch, TokenExtra},
+			accuracy: 75,
+		},
+		{
+			name:     "misspelling",
+			expected: "how are you",
+			actual:   "how arr you",
+			statuses: []TokenStatus{TokenMatch, TokenMisspelled, TokenMatch},
+			accuracy: 83.33,
+		},
+		{
+			// 差异过大的替换按漏写加多写处理
+			name:     "unrelated substitution",
+			expected: "how are you",
+			actual:   "how banana you",
+			statuses: []TokenStatus{TokenMatch, TokenMissing, TokenExtra, TokenMatch},
+			accuracy: 50,
+		},
+		{
+			name:     "empty answer",
+			expected: "how are you",
+			actual:   "",
+			statuses: []TokenStatus{TokenMissing, TokenMissing, TokenMissing},
+			accuracy: 0,
+		},
+		{
+			name:     "empty sentence",
+			expected: "",
+			actual:   "hello",
+			statuses: []TokenStatus{TokenExtra},
+			accuracy: 0,
+		},
+		{
+			name:     "both empty",
+			expected: "?!",
+			actual:   "",
+			statuses: []TokenStatus{},
+			accuracy: 0,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			diffs, accuracy := Grade(normalizer, tc.expected, tc.actual)
+			statuses := make([]TokenStatus, 0, len(diffs))
+			for _, d := range diffs {
+				statuses = append(statuses, d.Status)
+			}
+			if !reflect.DeepEqual(statuses, tc.statuses) {
+				t.Errorf("statuses = %v, want %v", statuses, tc.statuses)
+			}
+			if accuracy != tc.accuracy {
+				t.Errorf("accuracy = %v, want %v", accuracy, tc.accuracy)
+			}
+			if isAllMatched(diffs) != (tc.accuracy == 100) {
+				t.Errorf("isAllMatched = %v with accuracy %v", isAllMatched(diffs), accuracy)
+			}
+		})
+	}
+}
+
+func TestSubstitutionCost(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"you", "you", 0},
+		{"receive", "recieve", 1},
+		{"are", "arr", 1},
+		{"are", "banana", 2},
+		{"a", "b", 2},
+	}
+	for _, tc := range cases {
+		if got := substitutionCost(token{norm: tc.a}, token{norm: tc.b}); got != tc.want {
+			t.Errorf("substitutionCost(%q, %q) = %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
+
+func TestAccuracy(t *testing.T) {
+	cases := []struct {
+		name  string
+		diffs []TokenDiff
+		want  float64
+	}{
+		{"empty", nil, 0},
+		{"all match", []TokenDiff{{Status: TokenMatch}, {Status: TokenMatch}}, 100},
+		{"misspelling counts half", []TokenDiff{{Status: TokenMatch}, {Status: TokenMisspelled}}, 75},
+		{"extra words in denominator", []TokenDiff{{Status: TokenMatch}, {Status: TokenExtra}, {Status: TokenExtra}}, 33.33},
+		{"all missing", []TokenDiff{{Status: TokenMissing}}, 0},
+	}
+	for _, tc := range cases {
+		if got := accuracy(tc.diffs); got != tc.want {
+			t.Errorf("%s: accuracy = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
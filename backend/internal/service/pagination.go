@@ -0,0 +1,190 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"voicewriter/internal/repository"
+	apperrors "voicewriter/pkg/errors"
+)
+
+var (
+	// ErrInvalidSort 排序字段不支持
+	ErrInvalidSort = apperrors.New(apperrors.KindValidation, "invalid sort")
+	// ErrInvalidCursor 游标无法解析或与当前排序不匹配
+	ErrInvalidCursor = apperrors.New(apperrors.KindValidation, "invalid cursor")
+	// ErrInvalidFilter 列表过滤条件不合法
+	ErrInvalidFilter = apperrors.New(apperrors.KindValidation, "invalid filter")
+)
+
+const (
+	// defaultPageLimit 列表默认每页条数
+	defaultPageLimit = 20
+	// maxPageLimit 列表每页最多条数
+	maxPageLimit = 100
+)
+
+// ListQuery 列表的分页和排序参数
+// Sort 为字段名，前缀 "-" 表示降序；设置 Cursor 时按游标翻页并忽略 Offset
+type ListQuery struct {
+	Sort   string
+	Limit  int
+	Offset int
+	Cursor string
+}
+
+// PageInfo 分页结果信息
+type PageInfo struct {
+	Total      int64
+	Limit      int
+	Offset     int
+	NextCursor string
+	HasMore    bool
+}
+
+type sortKind int
+
+const (
+	sortKindID sortKind = iota
+	sortKindTime
+	sortKindString
+	sortKindInt
+)
+
+// sortField 可排序字段对应的列和值类型
+type sortField struct {
+	column string
+	kind   sortKind
+}
+
+// cursorPayload 游标内容，编码为 base64url(JSON)
+type cursorPayload struct {
+	Sort  string      `json:"s"`
+	Value interface{} `json:"v,omitempty"`
+	ID    uint        `json:"id"`
+}
+
+// pager 一次列表查询的分页状态
+type pager struct {
+	query   ListQuery
+	sort    string
+	field   sortField
+	request repository.PageRequest
+}
+
+// newPager 校验排序字段、条数和游标，生成仓储层分页参数
+// 仓储层多查一条用于判断是否还有下一页
+func newPager(query ListQuery, fields map[string]sortField, defaultSort string) (*pager, error) {
+	sort := strings.TrimSpace(query.Sort)
+	if sort == "" {
+		sort = defaultSort
+	}
+	name := strings.TrimPrefix(sort, "-")
+	field, ok := fields[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidSort, name)
+	}
+
+	if query.Limit <= 0 {
+		query.Limit = defaultPageLimit
+	}
+	if query.Limit > maxPageLimit {
+		query.Limit = maxPageLimit
+	}
+	if query.Offset < 0 {
+		query.Offset = 0
+	}
+
+	p := &pager{
+		query: query,
+		sort:  sort,
+		field: field,
+		request: repository.PageRequest{
+			SortBy: field.column,
+			Desc:   strings.HasPrefix(sort, "-"),
+			Limit:  query.Limit + 1,
+			Offset: query.Offset,
+		},
+	}
+	if query.Cursor != "" {
+		after, err := p.decodeCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		p.request.After = after
+		p.query.Offset = 0
+	}
+	return p, nil
+}
+
+// finish 根据查询结果条数生成分页信息，返回去掉多查一条后的条数
+// lastValue 为本页最后一条记录在排序字段上的值
+func (p *pager) finish(total int64, count int, lastValue func(i int) (interface{}, uint)) (int, *PageInfo) {
+	info := &PageInfo{
+		Total:  total,
+		Limit:  p.query.Limit,
+		Offset: p.query.Offset,
+	}
+	if count > p.query.Limit {
+		count = p.query.Limit
+		info.HasMore = true
+		value, id := lastValue(count - 1)
+		info.NextCursor = p.encodeCursor(value, id)
+	}
+	return count, info
+}
+
+func (p *pager) encodeCursor(value interface{}, id uint) string {
+	payload := cursorPayload{Sort: p.sort, ID: id}
+	switch p.field.kind {
+	case sortKindTime:
+		payload.Value = value.(time.Time).Format(time.RFC3339Nano)
+	case sortKindString, sortKindInt:
+		payload.Value = value
+	}
+	data, _ := json.Marshal(payload)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func (p *pager) decodeCursor(cursor string) (*repository.Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var payload cursorPayload
+	if err := json.Unmarshal(data, &payload); err != nil || payload.ID == 0 {
+		return nil, ErrInvalidCursor
+	}
+	if payload.Sort != p.sort {
+		return nil, fmt.Errorf("%w: cursor was issued for sort %q", ErrInvalidCursor, payload.Sort)
+	}
+
+	after := &repository.Cursor{ID: payload.ID}
+	switch p.field.kind {
+	case sortKindTime:
+		s, _ := payload.Value.(string)
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		after.Value = t
+	case sortKindString:
+		s, ok := payload.Value.(string)
+		if !ok {
+			return nil, ErrInvalidCursor
+		}
+		after.Value = s
+	case sortKindInt:
+		// JSON 数字解码为 float64，只接受整数值
+		f, ok := payload.Value.(float64)
+		if !ok || f != math.Trunc(f) {
+			return nil, ErrInvalidCursor
+		}
+		after.Value = int64(f)
+	}
+	return after, nil
+}
@@ -3,73 +3,257 @@ package service
 import (
 	"context"
 	"errors"
+	"strconv"
+	"strings"
 	"time"
 
+	"voicewriter/internal/metrics"
 	"voicewriter/internal/model"
 	"voicewriter/internal/repository"
 )
 
+const (
+	// defaultAttemptLimit 作答记录默认返回条数
+	defaultAttemptLimit = 100
+	// maxAttemptLimit 作答记录单次最多返回条数
+	maxAttemptLimit = 500
+)
+
+// AttemptDetail 单次作答详情，随进度一起提交
+type AttemptDetail struct {
+	SubmittedText string `json:"submitted_text" binding:"max=5000"`
+	DurationMs    int    `json:"duration_ms" binding:"min=0"`
+	AudioReplays  int    `json:"audio_replays" binding:"min=0"`
+}
+
+// SaveProgressRequest 保存进度请求，只包含客户端可以决定的字段
+// 用户由令牌确定，作答次数和时间由服务端维护
+type SaveProgressRequest struct {
+	SentenceID uint `json:"sentence_id" binding:"required"`
+	Completed  bool `json:"completed"`
+	AttemptDetail
+}
+
+// AttemptQuery 作答记录查询条件
+type AttemptQuery struct {
+	UserID     string
+	SentenceID uint
+	SceneID    uint
+	From       time.Time
+	To         time.Time
+	Limit      int
+}
+
 // ProgressService 用户进度服务
 type ProgressService struct {
-	progressRepo repository.ProgressRepository
+	progressRepo   repository.ProgressRepository
+	attemptRepo    repository.AttemptRepository
+	sentenceRepo   repository.SentenceRepository
+	sceneRepo      repository.SceneRepository
+	reviewRepo     repository.ReviewRepository
+	vocabularyRepo repository.VocabularyRepository
+	txManager      repository.TxManager
+
+	attempts *metrics.CounterVec
+	scores   *metrics.HistogramVec
 }
 
+// scoreBuckets 作答得分直方图的分桶
+var scoreBuckets = []float64{20, 40, 60, 80, 90, 100}
+
 // NewProgressService 创建用户进度服务实例
-func NewProgressService(progressRepo repository.ProgressRepository) *ProgressService {
+func NewProgressService(
+	progressRepo repository.ProgressRepository,
+	attemptRepo repository.AttemptRepository,
+	sentenceRepo repository.SentenceRepository,
+	sceneRepo repository.SceneRepository,
+	reviewRepo repository.ReviewRepository,
+	vocabularyRepo repository.VocabularyRepository,
+	txManager repository.TxManager,
+) *ProgressService {
 	return &ProgressService{
-		progressRepo: progressRepo,
+		progressRepo:   progressRepo,
+		attemptRepo:    attemptRepo,
+		sentenceRepo:   sentenceRepo,
+		sceneRepo:      sceneRepo,
+		reviewRepo:     reviewRepo,
+		vocabularyRepo: vocabularyRepo,
+		txManager:      txManager,
+		attempts:       metrics.NewCounterVec("attempts_total", "Saved dictation attempts by whether the server graded them and whether they completed the sentence.", "graded", "completed"),
+		scores:         metrics.NewHistogramVec("attempt_score", "Scores of server-graded dictation attempts (0-100).", scoreBuckets),
 	}
 }
 
+// RegisterMetrics 注册作答次数和评分分布指标
+func (s *ProgressService) RegisterMetrics(registry *metrics.Registry) {
+	registry.Register(s.attempts, s.scores)
+}
+
 // GetUserProgress 获取用户进度
 func (s *ProgressService) GetUserProgress(ctx context.Context, userID string) ([]*model.UserProgress, error) {
 	if userID == "" {
-		return nil, errors.New("user id is required")
+		return nil, invalidf("user id is required")
 	}
 	return s.progressRepo.GetByUserID(ctx, userID)
 }
 
-// SaveProgress 保存用户进度
-func (s *ProgressService) SaveProgress(ctx context.Context, progress *model.UserProgress) error {
-	if progress.UserID == "" {
-		return errors.New("user id is required")
+// SaveProgress 保存用户进度，追加一条作答记录
+// 完成状态和得分只由服务端评分决定：提交了作答文本时评分，漏写和拼错的词记入生词本并更新复习计划；
+// 未提交作答文本的记录（如跳过）不计分、不影响复习计划，也不能标记为完成
+func (s *ProgressService) SaveProgress(ctx context.Context, userID string, req *SaveProgressRequest) error {
+	if userID == "" {
+		return invalidf("user id is required")
+	}
+	if req.SentenceID == 0 {
+		return invalidf("sentence id is required")
+	}
+	detail := &req.AttemptDetail
+	if detail.DurationMs < 0 || detail.AudioReplays < 0 {
+		return invalidf("duration and audio replays must not be negative")
+	}
+	graded := strings.TrimSpace(detail.SubmittedText) != ""
+	if req.Completed && !graded {
+		return invalidf("submitted text is required to complete a sentence")
+	}
+
+	sentence, err := s.sentenceRepo.GetByID(ctx, req.SentenceID)
+	if err != nil {
+		return err
 	}
-	if progress.SentenceID == 0 {
-		return errors.New("sentence id is required")
+
+	var tokens []TokenDiff
+	var accuracy float64
+	if graded {
+		normalizer, err := sentenceNormalizer(ctx, s.sceneRepo, sentence)
+		if err != nil {
+			return err
+		}
+		tokens, accuracy = Grade(normalizer, sentence.Content, detail.SubmittedText)
 	}
+	return s.recordAttempt(ctx, userID, sentence, detail, graded, tokens, accuracy)
+}
 
-	// 检查是否已存在
-	existing, err := s.progressRepo.GetByUserAndSentence(ctx, progress.UserID, progress.SentenceID)
+// recordAttempt 按已算好的评分写入进度、作答记录、生词本和复习计划；graded 为 false 时 tokens 和 accuracy 不使用。
+// 练习会话提交答案时已经评过分，直接复用评分结果而不是再评一次
+func (s *ProgressService) recordAttempt(ctx context.Context, userID string, sentence *model.Sentence, detail *AttemptDetail, graded bool, tokens []TokenDiff, accuracy float64) error {
+	now := time.Now()
+	progress := &model.UserProgress{
+		UserID:      userID,
+		SentenceID:  sentence.ID,
+		Attempts:    1,
+		LastAttempt: now,
+	}
+	attempt := &model.Attempt{
+		UserID:        progress.UserID,
+		SentenceID:    sentence.ID,
+		SceneID:       sentence.SceneID,
+		SubmittedText: detail.SubmittedText,
+		DurationMs:    detail.DurationMs,
+		AudioReplays:  detail.AudioReplays,
+		CreatedAt:     now,
+	}
+	var missed []string
+	if graded {
+		attempt.Graded = true
+		progress.Completed = isAllMatched(tokens)
+		attempt.Score = accuracy
+		missed = missedTokens(sentence.Language, tokens)
+	}
+	attempt.Completed = progress.Completed
+
+	// 进度、作答记录、生词本和复习状态在同一事务内写入，任一步失败全部回滚。
+	// 进度的 upsert 最先执行并锁住 (用户, 句子) 的进度行，同一句子的并发提交在此串行，后续的读改写不会互相覆盖
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.progressRepo.RecordAttempt(ctx, progress); err != nil {
+			return err
+		}
+		if err := s.attemptRepo.Create(ctx, attempt); err != nil {
+			return err
+		}
+		if !graded {
+			return nil
+		}
+		if err := s.vocabularyRepo.RecordMisses(ctx, progress.UserID, sentence.Language, sentence.ID, missed, now); err != nil {
+			return err
+		}
+		return s.updateReviewState(ctx, attempt)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.attempts.Inc(strconv.FormatBool(graded), strconv.FormatBool(attempt.Completed))
+	if graded {
+		s.scores.Observe(attempt.Score)
+	}
+	return nil
+}
+
+// updateReviewState 根据本次作答得分调整该句子的下次复习时间，句子未到期时不做改动
+func (s *ProgressService) updateReviewState(ctx context.Context, attempt *model.Attempt) error {
+	state, err := s.reviewRepo.GetByUserAndSentence(ctx, attempt.UserID, attempt.SentenceID)
 	if err != nil && !errors.Is(err, repository.ErrNotFound) {
 		return err
 	}
 
-	// 如果已存在，更新记录
-	if existing != nil {
-		existing.Completed = progress.Completed
-		existing.Attempts++
-		existing.LastAttempt = time.Now()
-		return s.progressRepo.Update(ctx, existing)
+	if state != nil {
+		if !scheduleReview(state, attempt.Score, attempt.CreatedAt) {
+			return nil
+		}
+		return s.reviewRepo.Update(ctx, state)
 	}
 
-	// 否则创建新记录
-	progress.Attempts = 1
-	progress.LastAttempt = time.Now()
-	return s.progressRepo.Create(ctx, progress)
+	state = &model.ReviewState{
+		UserID:     attempt.UserID,
+		SentenceID: attempt.SentenceID,
+	}
+	scheduleReview(state, attempt.Score, attempt.CreatedAt)
+	return s.reviewRepo.Create(ctx, state)
+}
+
+// ListAttempts 查询用户的作答记录，按时间倒序
+func (s *ProgressService) ListAttempts(ctx context.Context, query *AttemptQuery) ([]*model.Attempt, error) {
+	if query.UserID == "" {
+		return nil, invalidf("user id is required")
+	}
+	if !query.From.IsZero() && !query.To.IsZero() && query.To.Before(query.From) {
+		return nil, invalidf("invalid date range")
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultAttemptLimit
+	}
+	if limit > maxAttemptLimit {
+		limit = maxAttemptLimit
+	}
+
+	return s.attemptRepo.List(ctx, repository.AttemptFilter{
+		UserID:     query.UserID,
+		SentenceID: query.SentenceID,
+		SceneID:    query.SceneID,
+		From:       query.From,
+		To:         query.To,
+		Limit:      limit,
+	})
 }
 
 // GetProgressByID 根据ID获取进度
 func (s *ProgressService) GetProgressByID(ctx context.Context, id uint) (*model.UserProgress, error) {
 	if id == 0 {
-		return nil, errors.New("invalid progress id")
+		return nil, invalidf("invalid progress id")
+	}
+	progress, err := s.progressRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, notFound(err, "progress")
 	}
-	return s.progressRepo.GetByID(ctx, id)
+	return progress, nil
 }
 
 // DeleteProgress 删除进度
 func (s *ProgressService) DeleteProgress(ctx context.Context, id uint) error {
 	if id == 0 {
-		return errors.New("invalid progress id")
+		return invalidf("invalid progress id")
 	}
 	return s.progressRepo.Delete(ctx, id)
 }
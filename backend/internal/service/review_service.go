@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"voicewriter/internal/model"
+	"voicewriter/internal/repository"
+)
+
+const (
+	// defaultReviewLimit 复习队列默认返回条数
+	defaultReviewLimit = 20
+	// maxReviewLimit 复习队列单次最多返回条数
+	maxReviewLimit = 100
+
+	// SM-2 参数
+	initialEaseFactor = 2.5
+	minEaseFactor     = 1.3
+	// passingQuality 评分达到该等级视为记住
+	passingQuality = 3
+	// relearnDelay 答错后重新出现的等待时间
+	relearnDelay = 10 * time.Minute
+)
+
+// ReviewService 间隔重复复习服务
+type ReviewService struct {
+	reviewRepo repository.ReviewRepository
+}
+
+// NewReviewService 创建复习服务实例
+func NewReviewService(reviewRepo repository.ReviewRepository) *ReviewService {
+	return &ReviewService{
+		reviewRepo: reviewRepo,
+	}
+}
+
+// GetDueReviews 获取用户当前到期的复习句子，跨所有场景按到期时间排序
+func (s *ReviewService) GetDueReviews(ctx context.Context, userID string, limit int) ([]*model.ReviewState, error) {
+	if userID == "" {
+		return nil, invalidf("user id is required")
+	}
+	if limit <= 0 {
+		limit = defaultReviewLimit
+	}
+	if limit > maxReviewLimit {
+		limit = maxReviewLimit
+	}
+	return s.reviewRepo.GetDue(ctx, userID, time.Now(), limit)
+}
+
+// scoreToQuality 将 0-100 的得分映射为 SM-2 的 0-5 评分等级
+func scoreToQuality(score float64) int {
+	switch {
+	case score >= 100:
+		return 5
+	case score >= 90:
+		return 4
+	case score >= 75:
+		return 3
+	case score >= 50:
+		return 2
+	case score >= 25:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// scheduleReview 按 SM-2 算法根据本次得分更新复习状态，返回是否有改动。
+// 只有到期的作答才算一次复习；未到期时的练习不推进间隔，也不计入遗忘，复习计划保持不变
+func scheduleReview(state *model.ReviewState, score float64, now time.Time) bool {
+	if !state.DueAt.IsZero() && now.Before(state.DueAt) {
+		return false
+	}
+	if state.EaseFactor == 0 {
+		state.EaseFactor = initialEaseFactor
+	}
+
+	quality := scoreToQuality(score)
+	if quality >= passingQuality {
+		switch state.Repetitions {
+		case 0:
+			state.IntervalDays = 1
+		case 1:
+			state.IntervalDays = 6
+		default:
+			state.IntervalDays = int(math.Round(float64(state.IntervalDays) * state.EaseFactor))
+		}
+		state.Repetitions++
+		state.DueAt = now.AddDate(0, 0, state.IntervalDays)
+	} else {
+		if state.Repetitions > 0 {
+			state.Lapses++
+		}
+		state.Repetitions = 0
+		state.IntervalDays = 0
+		state.DueAt = now.Add(relearnDelay)
+	}
+
+	q := float64(5 - quality)
+	state.EaseFactor = math.Max(minEaseFactor, state.EaseFactor+0.1-q*(0.08+q*0.02))
+	state.LastReviewedAt = now
+	return true
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"voicewriter/internal/model"
+)
+
+func TestScoreToQuality(t *testing.T) {
+	cases := []struct {
+		score float64
+		want  int
+	}{
+		{100, 5},
+		{99.99, 4},
+		{90, 4},
+		{89.9, 3},
+		{75, 3},
+		{74.9, 2},
+		{50, 2},
+		{49.9, 1},
+		{25, 1},
+		{24.9, 0},
+		{0, 0},
+	}
+	for _, tc := range cases {
+		if got := scoreToQuality(tc.score); got != tc.want {
+			t.Errorf("scoreToQuality(%v) = %d, want %d", tc.score, got, tc.want)
+		}
+	}
+}
+
+func TestScheduleReview(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name    string
+		state   model.ReviewState
+		score   float64
+		changed bool
+		want    model.ReviewState
+	}{
+		{
+			name:    "first review passes",
+			state:   model.ReviewState{},
+			score:   100,
+			changed: true,
+			want:    model.ReviewState{EaseFactor: 2.6, IntervalDays: 1, Repetitions: 1, DueAt: now.AddDate(0, 0, 1)},
+		},
+		{
+			name:    "first review fails without a lapse",
+			state:   model.ReviewState{},
+			score:   40,
+			changed: true,
+			want:    model.ReviewState{EaseFactor: 1.96, Repetitions: 0, DueAt: now.Add(relearnDelay)},
+		},
+		{
+			name:    "second review",
+			state:   model.ReviewState{EaseFactor: 2.5, IntervalDays: 1, Repetitions: 1, DueAt: now},
+			score:   90,
+			changed: true,
+			want:    model.ReviewState{EaseFactor: 2.5, IntervalDays: 6, Repetitions: 2, DueAt: now.AddDate(0, 0, 6)},
+		},
+		{
+			name:    "later review multiplies by ease factor",
+			state:   model.ReviewState{EaseFactor: 2.5, IntervalDays: 6, Repetitions: 2, DueAt: now.Add(-time.Hour)},
+			score:   80,
+			changed: true,
+			want:    model.ReviewState{EaseFactor: 2.36, IntervalDays: 15, Repetitions: 3, DueAt: now.AddDate(0, 0, 15)},
+		},
+		{
+			name:    "relearn after failure",
+			state:   model.ReviewState{EaseFactor: 2.5, IntervalDays: 15, Repetitions: 3, Lapses: 1, DueAt: now.Add(-time.Hour)},
+			score:   10,
+			changed: true,
+			want:    model.ReviewState{EaseFactor: 1.7, IntervalDays: 0, Repetitions: 0, Lapses: 2, DueAt: now.Add(relearnDelay)},
+		},
+		{
+			name:    "ease factor floor",
+			state:   model.ReviewState{EaseFactor: 1.4, DueAt: now},
+			score:   0,
+			changed: true,
+			want:    model.ReviewState{EaseFactor: minEaseFactor, DueAt: now.Add(relearnDelay)},
+		},
+		{
+			name:    "practice before due leaves the schedule alone",
+			state:   model.ReviewState{EaseFactor: 2.5, IntervalDays: 1, Repetitions: 1, DueAt: now.Add(time.Hour)},
+			score:   100,
+			changed: false,
+			want:    model.ReviewState{EaseFactor: 2.5, IntervalDays: 1, Repetitions: 1, DueAt: now.Add(time.Hour)},
+		},
+		{
+			name:    "failure before due is not a lapse",
+			state:   model.ReviewState{EaseFactor: 2.5, IntervalDays: 6, Repetitions: 2, DueAt: now.AddDate(0, 0, 3)},
+			score:   0,
+			changed: false,
+			want:    model.ReviewState{EaseFactor: 2.5, IntervalDays: 6, Repetitions: 2, DueAt: now.AddDate(0, 0, 3)},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			state := tc.state
+			if changed := scheduleReview(&state, tc.score, now); changed != tc.changed {
+				t.Errorf("changed = %v, want %v", changed, tc.changed)
+			}
+			if math.Abs(state.EaseFactor-tc.want.EaseFactor) > 1e-9 {
+				t.Errorf("ease factor = %v, want %v", state.EaseFactor, tc.want.EaseFactor)
+			}
+			if state.IntervalDays != tc.want.IntervalDays || state.Repetitions != tc.want.Repetitions || state.Lapses != tc.want.Lapses {
+				t.Errorf("interval/repetitions/lapses = %d/%d/%d, want %d/%d/%d",
+					state.IntervalDays, state.Repetitions, state.Lapses,
+					tc.want.IntervalDays, tc.want.Repetitions, tc.want.Lapses)
+			}
+			if !state.DueAt.Equal(tc.want.DueAt) {
+				t.Errorf("due at = %v, want %v", state.DueAt, tc.want.DueAt)
+			}
+			if tc.changed != state.LastReviewedAt.Equal(now) {
+				t.Errorf("last reviewed at = %v, changed = %v", state.LastReviewedAt, tc.changed)
+			}
+		})
+	}
+}
+
+// TestScheduleReviewRepeatedPractice 一分钟内反复练习同一句，只有第一次推进复习计划
+func TestScheduleReviewRepeatedPractice(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	var state model.ReviewState
+	for i := 0; i < 3; i++ {
+		scheduleReview(&state, 100, now.Add(time.Duration(i)*20*time.Second))
+	}
+	if state.Repetitions != 1 || state.IntervalDays != 1 {
+		t.Errorf("after 3 quick attempts repetitions = %d, interval = %d; want 1, 1", state.Repetitions, state.IntervalDays)
+	}
+}
@@ -3,20 +3,61 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 
 	"voicewriter/internal/model"
 	"voicewriter/internal/repository"
+	"voicewriter/internal/textnorm"
+	apperrors "voicewriter/pkg/errors"
 )
 
+// ErrSceneInUse 场景下仍有句子，不能删除
+var ErrSceneInUse = apperrors.New(apperrors.KindConflict, "scene still has sentences")
+
+// CreateSceneRequest 创建场景请求
+type CreateSceneRequest struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Description string `json:"description"`
+	Icon        string `json:"icon" binding:"max=50"`
+	Strictness  string `json:"strictness"` // 评分严格程度：strict, normal, lenient，默认 normal
+}
+
+// UpdateSceneRequest 整体更新场景请求
+type UpdateSceneRequest = CreateSceneRequest
+
+// PatchSceneRequest 部分更新场景请求，未提供的字段保持不变
+type PatchSceneRequest struct {
+	Name        *string `json:"name" binding:"omitempty,min=1,max=100"`
+	Description *string `json:"description"`
+	Icon        *string `json:"icon" binding:"omitempty,max=50"`
+	Strictness  *string `json:"strictness"`
+}
+
+// SceneQuery 场景列表查询条件
+type SceneQuery struct {
+	ListQuery
+	Query string
+}
+
+// sceneSortFields 场景列表可排序字段
+var sceneSortFields = map[string]sortField{
+	"id":         {column: "id", kind: sortKindID},
+	"name":       {column: "name", kind: sortKindString},
+	"created_at": {column: "created_at", kind: sortKindTime},
+}
+
 // SceneService 场景服务
 type SceneService struct {
-	sceneRepo repository.SceneRepository
+	sceneRepo    repository.SceneRepository
+	sentenceRepo repository.SentenceRepository
 }
 
 // NewSceneService 创建场景服务实例
-func NewSceneService(sceneRepo repository.SceneRepository) *SceneService {
+func NewSceneService(sceneRepo repository.SceneRepository, sentenceRepo repository.SentenceRepository) *SceneService {
 	return &SceneService{
-		sceneRepo: sceneRepo,
+		sceneRepo:    sceneRepo,
+		sentenceRepo: sentenceRepo,
 	}
 }
 
@@ -25,37 +66,146 @@ func (s *SceneService) GetAllScenes(ctx context.Context) ([]*model.Scene, error)
 	return s.sceneRepo.GetAll(ctx)
 }
 
+// ListScenes 按条件分页查询场景，默认按ID升序
+func (s *SceneService) ListScenes(ctx context.Context, query *SceneQuery) ([]*model.Scene, *PageInfo, error) {
+	p, err := newPager(query.ListQuery, sceneSortFields, "id")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	filter := repository.SceneFilter{Query: strings.TrimSpace(query.Query)}
+	scenes, total, err := s.sceneRepo.List(ctx, filter, p.request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	count, page := p.finish(total, len(scenes), func(i int) (interface{}, uint) {
+		return sceneSortValue(scenes[i], p.field.column), scenes[i].ID
+	})
+	return scenes[:count], page, nil
+}
+
 // GetSceneByID 根据ID获取场景
 func (s *SceneService) GetSceneByID(ctx context.Context, id uint) (*model.Scene, error) {
 	if id == 0 {
-		return nil, errors.New("invalid scene id")
+		return nil, invalidf("invalid scene id")
+	}
+	scene, err := s.sceneRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, notFound(err, "scene")
 	}
-	return s.sceneRepo.GetByID(ctx, id)
+	return scene, nil
 }
 
 // CreateScene 创建场景
-func (s *SceneService) CreateScene(ctx context.Context, scene *model.Scene) error {
-	if scene.Name == "" {
-		return errors.New("scene name is required")
+func (s *SceneService) CreateScene(ctx context.Context, req *CreateSceneRequest) (*model.Scene, error) {
+	scene := &model.Scene{
+		Name:        strings.TrimSpace(req.Name),
+		Description: req.Description,
+		Icon:        req.Icon,
+		Strictness:  req.Strictness,
 	}
-	return s.sceneRepo.Create(ctx, scene)
+	if err := s.validateScene(ctx, scene); err != nil {
+		return nil, err
+	}
+
+	if err := s.sceneRepo.Create(ctx, scene); err != nil {
+		return nil, err
+	}
+	return scene, nil
 }
 
-// UpdateScene 更新场景
-func (s *SceneService) UpdateScene(ctx context.Context, scene *model.Scene) error {
-	if scene.ID == 0 {
-		return errors.New("invalid scene id")
+// UpdateScene 整体更新场景
+func (s *SceneService) UpdateScene(ctx context.Context, id uint, req *UpdateSceneRequest) (*model.Scene, error) {
+	scene, err := s.GetSceneByID(ctx, id)
+	if err != nil {
+		return nil, err
 	}
-	if scene.Name == "" {
-		return errors.New("scene name is required")
+
+	scene.Name = strings.TrimSpace(req.Name)
+	scene.Description = req.Description
+	scene.Icon = req.Icon
+	scene.Strictness = req.Strictness
+	return s.saveScene(ctx, scene)
+}
+
+// PatchScene 部分更新场景
+func (s *SceneService) PatchScene(ctx context.Context, id uint, req *PatchSceneRequest) (*model.Scene, error) {
+	scene, err := s.GetSceneByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		scene.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Description != nil {
+		scene.Description = *req.Description
+	}
+	if req.Icon != nil {
+		scene.Icon = *req.Icon
+	}
+	if req.Strictness != nil {
+		scene.Strictness = *req.Strictness
 	}
-	return s.sceneRepo.Update(ctx, scene)
+	return s.saveScene(ctx, scene)
 }
 
-// DeleteScene 删除场景
+// DeleteScene 删除场景，场景下仍有句子时拒绝删除
 func (s *SceneService) DeleteScene(ctx context.Context, id uint) error {
 	if id == 0 {
-		return errors.New("invalid scene id")
+		return invalidf("invalid scene id")
+	}
+
+	count, err := s.sentenceRepo.CountBySceneID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrSceneInUse
+	}
+	return notFound(s.sceneRepo.Delete(ctx, id), "scene")
+}
+
+func (s *SceneService) saveScene(ctx context.Context, scene *model.Scene) (*model.Scene, error) {
+	if err := s.validateScene(ctx, scene); err != nil {
+		return nil, err
+	}
+	if err := s.sceneRepo.Update(ctx, scene); err != nil {
+		return nil, err
+	}
+	return scene, nil
+}
+
+// validateScene 校验场景字段并规范化评分严格程度，场景名称不能与其他场景重复
+func (s *SceneService) validateScene(ctx context.Context, scene *model.Scene) error {
+	if scene.Name == "" {
+		return invalidf("scene name is required")
+	}
+	strictness, err := textnorm.ParseStrictness(scene.Strictness)
+	if err != nil {
+		return invalidf("%s", err)
+	}
+	scene.Strictness = string(strictness)
+
+	existing, err := s.sceneRepo.GetByName(ctx, scene.Name)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+	if existing != nil && existing.ID != scene.ID {
+		return apperrors.Wrap(apperrors.KindConflict, repository.ErrDuplicateKey, fmt.Sprintf("scene name %q already exists", scene.Name))
+	}
+	return nil
+}
+
+// sceneSortValue 返回场景在排序列上的值，用于生成游标
+func sceneSortValue(scene *model.Scene, column string) interface{} {
+	switch column {
+	case "name":
+		return scene.Name
+	case "created_at":
+		return scene.CreatedAt
+	default:
+		return scene.ID
 	}
-	return s.sceneRepo.Delete(ctx, id)
 }
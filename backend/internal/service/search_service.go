@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"voicewriter/internal/i18n"
+	"voicewriter/internal/model"
+	"voicewriter/internal/repository"
+)
+
+// maxSearchQueryLength 搜索文本最大字符数
+const maxSearchQueryLength = 200
+
+// indexBatchSize 建立索引时每次查询翻译的句子数，避免 IN 条件的参数过多
+const indexBatchSize = 500
+
+// SearchRequest 句子全文搜索请求
+type SearchRequest struct {
+	Query    string
+	SceneID  uint
+	Language string
+	Limit    int
+	Offset   int
+}
+
+// SearchResult 搜索命中的句子及相关度
+type SearchResult struct {
+	Sentence   *model.Sentence   `json:"sentence"`
+	Score      float64           `json:"score"`
+	Highlights map[string]string `json:"highlights"`
+}
+
+// SearchResults 搜索结果列表
+type SearchResults []*SearchResult
+
+// Sentences 返回搜索结果中的句子，用于本地化翻译
+func (r SearchResults) Sentences() []*model.Sentence {
+	sentences := make([]*model.Sentence, len(r))
+	for i, result := range r {
+		sentences[i] = result.Sentence
+	}
+	return sentences
+}
+
+// SearchService 句子全文搜索服务
+type SearchService struct {
+	searchIndex     repository.SearchIndex
+	sentenceRepo    repository.SentenceRepository
+	translationRepo repository.TranslationRepository
+}
+
+// NewSearchService 创建搜索服务实例
+func NewSearchService(
+	searchIndex repository.SearchIndex,
+	sentenceRepo repository.SentenceRepository,
+	translationRepo repository.TranslationRepository,
+) *SearchService {
+	return &SearchService{
+		searchIndex:     searchIndex,
+		sentenceRepo:    sentenceRepo,
+		translationRepo: translationRepo,
+	}
+}
+
+// Reindex 用数据库中的全部句子及其翻译重建索引，返回索引的句子数
+func (s *SearchService) Reindex(ctx context.Context) (int, error) {
+	sentences, err := s.sentenceRepo.GetAll(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if err := indexSentences(ctx, s.searchIndex, s.translationRepo, sentences...); err != nil {
+		return 0, err
+	}
+	return len(sentences), nil
+}
+
+// Search 搜索原文和翻译，按相关度排序并返回高亮片段
+func (s *SearchService) Search(ctx context.Context, req *SearchRequest) (SearchResults, *PageInfo, error) {
+	text := strings.TrimSpace(req.Query)
+	if text == "" {
+		return nil, nil, fmt.Errorf("%w: q is required", ErrInvalidFilter)
+	}
+	if utf8.RuneCountInString(text) > maxSearchQueryLength {
+		return nil, nil, fmt.Errorf("%w: q must be at most %d characters", ErrInvalidFilter, maxSearchQueryLength)
+	}
+
+	query := repository.SearchQuery{
+		Text:    text,
+		SceneID: req.SceneID,
+		Limit:   req.Limit,
+		Offset:  max(req.Offset, 0),
+	}
+	if query.Limit <= 0 {
+		query.Limit = defaultPageLimit
+	}
+	if query.Limit > maxPageLimit {
+		query.Limit = maxPageLimit
+	}
+	if req.Language != "" {
+		language, err := i18n.Normalize(req.Language)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrInvalidFilter, err)
+		}
+		query.Language = language
+	}
+
+	hits, total, err := s.searchIndex.Search(ctx, query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ids := make([]uint, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.ID
+	}
+	sentences, err := s.sentenceRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	byID := make(map[uint]*model.Sentence, len(sentences))
+	for _, sentence := range sentences {
+		byID[sentence.ID] = sentence
+	}
+
+	// 按索引给出的相关度顺序返回，跳过索引中残留但已删除的句子
+	results := make(SearchResults, 0, len(hits))
+	for _, hit := range hits {
+		sentence, ok := byID[hit.ID]
+		if !ok {
+			continue
+		}
+		results = append(results, &SearchResult{
+			Sentence:   sentence,
+			Score:      hit.Score,
+			Highlights: hit.Highlights,
+		})
+	}
+
+	page := &PageInfo{
+		Total:   int64(total),
+		Limit:   query.Limit,
+		Offset:  query.Offset,
+		HasMore: query.Offset+len(hits) < total,
+	}
+	return results, page, nil
+}
+
+// indexSentences 读取句子在翻译表中的各语言翻译，连同句子一起写入搜索索引
+func indexSentences(ctx context.Context, index repository.SearchIndex, translationRepo repository.TranslationRepository, sentences ...*model.Sentence) error {
+	for start := 0; start < len(sentences); start += indexBatchSize {
+		batch := sentences[start:min(start+indexBatchSize, len(sentences))]
+		ids := make([]uint, len(batch))
+		for i, sentence := range batch {
+			ids[i] = sentence.ID
+		}
+		translations, err := translationRepo.ListSentenceTranslations(ctx, ids, nil)
+		if err != nil {
+			return err
+		}
+		if err := index.Index(ctx, searchDocuments(batch, translations)...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// searchDocuments 将句子和翻译表中的翻译转换为索引文档
+func searchDocuments(sentences []*model.Sentence, translations []*model.SentenceTranslation) []repository.SearchDocument {
+	bySentence := make(map[uint]map[string]string)
+	for _, translation := range translations {
+		if bySentence[translation.SentenceID] == nil {
+			bySentence[translation.SentenceID] = make(map[string]string)
+		}
+		bySentence[translation.SentenceID][translation.Locale] = translation.Text
+	}
+	docs := make([]repository.SearchDocument, len(sentences))
+	for i, sentence := range sentences {
+		docs[i] = repository.SearchDocument{
+			ID:           sentence.ID,
+			SceneID:      sentence.SceneID,
+			Language:     sentence.Language,
+			Content:      sentence.Content,
+			Translation:  sentence.Translation,
+			Translations: bySentence[sentence.ID],
+		}
+	}
+	return docs
+}
@@ -3,20 +3,78 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 
+	"voicewriter/internal/i18n"
 	"voicewriter/internal/model"
 	"voicewriter/internal/repository"
+	apperrors "voicewriter/pkg/errors"
 )
 
+// ErrSceneNotExist 句子引用的场景不存在
+var ErrSceneNotExist = apperrors.New(apperrors.KindValidation, "scene does not exist")
+
+// CreateSentenceRequest 创建句子请求
+type CreateSentenceRequest struct {
+	SceneID     uint   `json:"scene_id" binding:"required"`
+	Language    string `json:"language" binding:"max=20"` // 目标语言，默认 en
+	Content     string `json:"content" binding:"required"`
+	Translation string `json:"translation"`
+	AudioURL    string `json:"audio_url" binding:"max=255"`
+	Difficulty  string `json:"difficulty" binding:"omitempty,oneof=easy medium hard"`
+}
+
+// UpdateSentenceRequest 整体更新句子请求
+type UpdateSentenceRequest = CreateSentenceRequest
+
+// PatchSentenceRequest 部分更新句子请求，未提供的字段保持不变
+type PatchSentenceRequest struct {
+	SceneID     *uint   `json:"scene_id" binding:"omitempty,min=1"`
+	Language    *string `json:"language" binding:"omitempty,min=1,max=20"`
+	Content     *string `json:"content" binding:"omitempty,min=1"`
+	Translation *string `json:"translation"`
+	AudioURL    *string `json:"audio_url" binding:"omitempty,max=255"`
+	Difficulty  *string `json:"difficulty" binding:"omitempty,oneof=easy medium hard"`
+}
+
+// SentenceQuery 句子列表查询条件
+type SentenceQuery struct {
+	ListQuery
+	SceneID    uint
+	Difficulty string
+	Language   string
+	Query      string
+	HasAudio   *bool
+}
+
+// sentenceSortFields 句子列表可排序字段
+var sentenceSortFields = map[string]sortField{
+	"id":         {column: "id", kind: sortKindID},
+	"created_at": {column: "created_at", kind: sortKindTime},
+	"updated_at": {column: "updated_at", kind: sortKindTime},
+}
+
 // SentenceService 句子服务
 type SentenceService struct {
-	sentenceRepo repository.SentenceRepository
+	sentenceRepo    repository.SentenceRepository
+	sceneRepo       repository.SceneRepository
+	translationRepo repository.TranslationRepository
+	searchIndex     repository.SearchIndex
 }
 
-// NewSentenceService 创建句子服务实例
-func NewSentenceService(sentenceRepo repository.SentenceRepository) *SentenceService {
+// NewSentenceService 创建句子服务实例，句子变更时同步更新搜索索引
+func NewSentenceService(
+	sentenceRepo repository.SentenceRepository,
+	sceneRepo repository.SceneRepository,
+	translationRepo repository.TranslationRepository,
+	searchIndex repository.SearchIndex,
+) *SentenceService {
 	return &SentenceService{
-		sentenceRepo: sentenceRepo,
+		sentenceRepo:    sentenceRepo,
+		sceneRepo:       sceneRepo,
+		translationRepo: translationRepo,
+		searchIndex:     searchIndex,
 	}
 }
 
@@ -25,48 +83,193 @@ func (s *SentenceService) GetAllSentences(ctx context.Context) ([]*model.Sentenc
 	return s.sentenceRepo.GetAll(ctx)
 }
 
+// ListSentences 按条件分页查询句子，默认按ID升序
+func (s *SentenceService) ListSentences(ctx context.Context, query *SentenceQuery) ([]*model.Sentence, *PageInfo, error) {
+	filter := repository.SentenceFilter{
+		SceneID:    query.SceneID,
+		Difficulty: strings.ToLower(strings.TrimSpace(query.Difficulty)),
+		Query:      strings.TrimSpace(query.Query),
+		HasAudio:   query.HasAudio,
+	}
+	if filter.Difficulty != "" && !validDifficulties[filter.Difficulty] {
+		return nil, nil, fmt.Errorf("%w: difficulty must be easy, medium or hard", ErrInvalidFilter)
+	}
+	if query.Language != "" {
+		language, err := i18n.Normalize(query.Language)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrInvalidFilter, err)
+		}
+		filter.Language = language
+	}
+
+	p, err := newPager(query.ListQuery, sentenceSortFields, "id")
+	if err != nil {
+		return nil, nil, err
+	}
+	sentences, total, err := s.sentenceRepo.List(ctx, filter, p.request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	count, page := p.finish(total, len(sentences), func(i int) (interface{}, uint) {
+		return sentenceSortValue(sentences[i], p.field.column), sentences[i].ID
+	})
+	return sentences[:count], page, nil
+}
+
 // GetSentenceByID 根据ID获取句子
 func (s *SentenceService) GetSentenceByID(ctx context.Context, id uint) (*model.Sentence, error) {
 	if id == 0 {
-		return nil, errors.New("invalid sentence id")
+		return nil, invalidf("invalid sentence id")
 	}
-	return s.sentenceRepo.GetByID(ctx, id)
+	sentence, err := s.sentenceRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, notFound(err, "sentence")
+	}
+	return sentence, nil
 }
 
 // GetSentencesBySceneID 根据场景ID获取句子列表
 func (s *SentenceService) GetSentencesBySceneID(ctx context.Context, sceneID uint) ([]*model.Sentence, error) {
 	if sceneID == 0 {
-		return nil, errors.New("invalid scene id")
+		return nil, invalidf("invalid scene id")
 	}
 	return s.sentenceRepo.GetBySceneID(ctx, sceneID)
 }
 
 // CreateSentence 创建句子
-func (s *SentenceService) CreateSentence(ctx context.Context, sentence *model.Sentence) error {
-	if sentence.Content == "" {
-		return errors.New("sentence content is required")
+func (s *SentenceService) CreateSentence(ctx context.Context, req *CreateSentenceRequest) (*model.Sentence, error) {
+	sentence := &model.Sentence{
+		SceneID:     req.SceneID,
+		Language:    req.Language,
+		Content:     strings.TrimSpace(req.Content),
+		Translation: req.Translation,
+		AudioURL:    req.AudioURL,
+		Difficulty:  req.Difficulty,
 	}
-	if sentence.SceneID == 0 {
-		return errors.New("scene id is required")
+	if sentence.Difficulty == "" {
+		sentence.Difficulty = "easy"
 	}
-	return s.sentenceRepo.Create(ctx, sentence)
+	if err := s.validateSentence(ctx, sentence); err != nil {
+		return nil, err
+	}
+
+	if err := s.sentenceRepo.Create(ctx, sentence); err != nil {
+		return nil, err
+	}
+	if err := indexSentences(ctx, s.searchIndex, s.translationRepo, sentence); err != nil {
+		return nil, err
+	}
+	return sentence, nil
 }
 
-// UpdateSentence 更新句子
-func (s *SentenceService) UpdateSentence(ctx context.Context, sentence *model.Sentence) error {
-	if sentence.ID == 0 {
-		return errors.New("invalid sentence id")
+// UpdateSentence 整体更新句子
+func (s *SentenceService) UpdateSentence(ctx context.Context, id uint, req *UpdateSentenceRequest) (*model.Sentence, error) {
+	sentence, err := s.GetSentenceByID(ctx, id)
+	if err != nil {
+		return nil, err
 	}
-	if sentence.Content == "" {
-		return errors.New("sentence content is required")
+
+	sentence.SceneID = req.SceneID
+	sentence.Language = req.Language
+	sentence.Content = strings.TrimSpace(req.Content)
+	sentence.Translation = req.Translation
+	sentence.AudioURL = req.AudioURL
+	sentence.Difficulty = req.Difficulty
+	if sentence.Difficulty == "" {
+		sentence.Difficulty = "easy"
 	}
-	return s.sentenceRepo.Update(ctx, sentence)
+	return s.saveSentence(ctx, sentence)
+}
+
+// PatchSentence 部分更新句子
+func (s *SentenceService) PatchSentence(ctx context.Context, id uint, req *PatchSentenceRequest) (*model.Sentence, error) {
+	sentence, err := s.GetSentenceByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.SceneID != nil {
+		sentence.SceneID = *req.SceneID
+	}
+	if req.Language != nil {
+		sentence.Language = *req.Language
+	}
+	if req.Content != nil {
+		sentence.Content = strings.TrimSpace(*req.Content)
+	}
+	if req.Translation != nil {
+		sentence.Translation = *req.Translation
+	}
+	if req.AudioURL != nil {
+		sentence.AudioURL = *req.AudioURL
+	}
+	if req.Difficulty != nil {
+		sentence.Difficulty = *req.Difficulty
+	}
+	return s.saveSentence(ctx, sentence)
 }
 
 // DeleteSentence 删除句子
 func (s *SentenceService) DeleteSentence(ctx context.Context, id uint) error {
 	if id == 0 {
-		return errors.New("invalid sentence id")
+		return invalidf("invalid sentence id")
+	}
+	if err := s.sentenceRepo.Delete(ctx, id); err != nil {
+		return notFound(err, "sentence")
+	}
+	return s.searchIndex.Delete(ctx, id)
+}
+
+func (s *SentenceService) saveSentence(ctx context.Context, sentence *model.Sentence) (*model.Sentence, error) {
+	if err := s.validateSentence(ctx, sentence); err != nil {
+		return nil, err
+	}
+	// 关联对象可能已过期，只按外键保存
+	sentence.Scene = nil
+	if err := s.sentenceRepo.Update(ctx, sentence); err != nil {
+		return nil, err
+	}
+	if err := indexSentences(ctx, s.searchIndex, s.translationRepo, sentence); err != nil {
+		return nil, err
+	}
+	return sentence, nil
+}
+
+// validateSentence 校验句子字段并规范化语言代码，并确认引用的场景存在
+func (s *SentenceService) validateSentence(ctx context.Context, sentence *model.Sentence) error {
+	if sentence.Content == "" {
+		return invalidf("sentence content is required")
+	}
+	if sentence.Language == "" {
+		sentence.Language = i18n.DefaultTargetLanguage
+	}
+	language, err := i18n.Normalize(sentence.Language)
+	if err != nil {
+		return err
+	}
+	sentence.Language = language
+	if sentence.SceneID == 0 {
+		return invalidf("scene id is required")
+	}
+
+	if _, err := s.sceneRepo.GetByID(ctx, sentence.SceneID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrSceneNotExist
+		}
+		return err
+	}
+	return nil
+}
+
+// sentenceSortValue 返回句子在排序列上的值，用于生成游标
+func sentenceSortValue(sentence *model.Sentence, column string) interface{} {
+	switch column {
+	case "created_at":
+		return sentence.CreatedAt
+	case "updated_at":
+		return sentence.UpdatedAt
+	default:
+		return sentence.ID
 	}
-	return s.sentenceRepo.Delete(ctx, id)
 }
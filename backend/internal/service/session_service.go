@@ -0,0 +1,457 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"voicewriter/internal/model"
+	"voicewriter/internal/repository"
+	apperrors "voicewriter/pkg/errors"
+)
+
+const (
+	// defaultSessionLimit 会话默认包含的句子数
+	defaultSessionLimit = 10
+	// maxSessionLimit 会话最多包含的句子数
+	maxSessionLimit = 50
+)
+
+// ErrSessionFinished 会话已结束，不能继续作答
+var ErrSessionFinished = apperrors.New(apperrors.KindConflict, "session already finished")
+
+// sessionSortFields 会话列表可排序字段
+var sessionSortFields = map[string]sortField{
+	"id":         {column: "id", kind: sortKindID},
+	"started_at": {column: "started_at", kind: sortKindTime},
+}
+
+// StartSessionRequest 开始练习会话请求
+type StartSessionRequest struct {
+	Mode    string `json:"mode" binding:"required,oneof=scene review"`
+	SceneID uint   `json:"scene_id"`                     // mode 为 scene 时必填
+	Limit   int    `json:"limit" binding:"min=0,max=50"` // 句子数，默认 10
+}
+
+// SubmitAnswerRequest 会话中提交一句的答案
+type SubmitAnswerRequest struct {
+	SentenceID uint `json:"sentence_id" binding:"required"`
+	CheckAnswerRequest
+}
+
+// SessionQuery 会话列表查询条件
+type SessionQuery struct {
+	ListQuery
+	UserID string
+	Status string
+}
+
+// SessionSummary 会话小结，进行中的会话为当前进度的快照
+type SessionSummary struct {
+	Total       int
+	Answered    int
+	Completed   int
+	Skipped     int               // 未作答的句子数
+	Accuracy    float64           // 已作答句子的平均得分
+	DurationMs  int               // 作答用时合计
+	ElapsedMs   int64             // 从开始到结束（进行中为到现在）的时长
+	MissedWords []string          // 本次会话中漏写或拼错的词，不区分是否已在生词本中
+	Retry       []*model.Sentence // 作答了但未完全正确、需要再练的句子
+}
+
+// SessionDetail 会话、各句作答结果和小结
+type SessionDetail struct {
+	Session      *model.PracticeSession
+	Summary      *SessionSummary
+	NextPosition *int // 第一个未作答句子的位置，全部作答后为空
+}
+
+// SessionAnswer 提交答案的结果
+type SessionAnswer struct {
+	Item         *model.PracticeSessionItem
+	Result       *CheckResult
+	NextPosition *int
+}
+
+// SessionService 听写练习会话服务
+type SessionService struct {
+	sessionRepo     repository.SessionRepository
+	sceneRepo       repository.SceneRepository
+	sentenceRepo    repository.SentenceRepository
+	progressRepo    repository.ProgressRepository
+	reviewRepo      repository.ReviewRepository
+	progressService *ProgressService
+	txManager       repository.TxManager
+}
+
+// NewSessionService 创建练习会话服务实例，作答经 progressService 同时记录进度、作答记录、生词本和复习计划
+func NewSessionService(
+	sessionRepo repository.SessionRepository,
+	sceneRepo repository.SceneRepository,
+	sentenceRepo repository.SentenceRepository,
+	progressRepo repository.ProgressRepository,
+	reviewRepo repository.ReviewRepository,
+	progressService *ProgressService,
+	txManager repository.TxManager,
+) *SessionService {
+	return &SessionService{
+		sessionRepo:     sessionRepo,
+		sceneRepo:       sceneRepo,
+		sentenceRepo:    sentenceRepo,
+		progressRepo:    progressRepo,
+		reviewRepo:      reviewRepo,
+		progressService: progressService,
+		txManager:       txManager,
+	}
+}
+
+// StartSession 开始练习会话：场景会话按句子顺序排列，未完成的句子在前；复习会话按到期时间排列
+func (s *SessionService) StartSession(ctx context.Context, userID string, req *StartSessionRequest) (*SessionDetail, error) {
+	if userID == "" {
+		return nil, invalidf("user id is required")
+	}
+	limit := req.Limit
+	if limit < 0 || limit > maxSessionLimit {
+		return nil, invalidf("limit must be between 1 and %d", maxSessionLimit)
+	}
+	if limit == 0 {
+		limit = defaultSessionLimit
+	}
+
+	session := &model.PracticeSession{
+		UserID:    userID,
+		Mode:      req.Mode,
+		Status:    model.SessionStatusActive,
+		StartedAt: time.Now(),
+	}
+	var sentenceIDs []uint
+	var err error
+	switch req.Mode {
+	case model.SessionModeScene:
+		if req.SceneID == 0 {
+			return nil, invalidf("scene id is required")
+		}
+		session.SceneID = &req.SceneID
+		sentenceIDs, err = s.pickSceneSentences(ctx, userID, req.SceneID, limit)
+	case model.SessionModeReview:
+		sentenceIDs, err = s.pickReviewSentences(ctx, userID, limit)
+	default:
+		return nil, invalidf("unsupported session mode %q", req.Mode)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i, id := range sentenceIDs {
+		session.Items = append(session.Items, &model.PracticeSessionItem{Position: i, SentenceID: id})
+	}
+	session.Total = len(session.Items)
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
+		return nil, err
+	}
+	return s.GetSession(ctx, userID, session.ID)
+}
+
+// pickSceneSentences 选出场景中的句子，未完成的在前，各自保持句子原有顺序
+func (s *SessionService) pickSceneSentences(ctx context.Context, userID string, sceneID uint, limit int) ([]uint, error) {
+	if _, err := s.sceneRepo.GetByID(ctx, sceneID); err != nil {
+		return nil, notFound(err, "scene")
+	}
+	sentences, err := s.sentenceRepo.GetBySceneID(ctx, sceneID)
+	if err != nil {
+		return nil, err
+	}
+	if len(sentences) == 0 {
+		return nil, invalidf("scene has no sentences")
+	}
+	progress, err := s.progressRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	completed := make(map[uint]bool, len(progress))
+	for _, p := range progress {
+		completed[p.SentenceID] = p.Completed
+	}
+
+	sort.SliceStable(sentences, func(i, j int) bool {
+		if completed[sentences[i].ID] != completed[sentences[j].ID] {
+			return !completed[sentences[i].ID]
+		}
+		return sentences[i].ID < sentences[j].ID
+	})
+	ids := make([]uint, 0, min(limit, len(sentences)))
+	for _, sentence := range sentences[:min(limit, len(sentences))] {
+		ids = append(ids, sentence.ID)
+	}
+	return ids, nil
+}
+
+// pickReviewSentences 选出到期的复习句子，最早到期的在前
+func (s *SessionService) pickReviewSentences(ctx context.Context, userID string, limit int) ([]uint, error) {
+	states, err := s.reviewRepo.GetDue(ctx, userID, time.Now(), limit)
+	if err != nil {
+		return nil, err
+	}
+	if len(states) == 0 {
+		return nil, invalidf("no sentences are due for review")
+	}
+	ids := make([]uint, len(states))
+	for i, state := range states {
+		ids[i] = state.SentenceID
+	}
+	return ids, nil
+}
+
+// GetSession 获取当前用户的会话，用于在其他设备上继续
+func (s *SessionService) GetSession(ctx context.Context, userID string, id uint) (*SessionDetail, error) {
+	session, err := s.getOwned(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return newSessionDetail(session, time.Now()), nil
+}
+
+// ListSessions 分页查询当前用户的会话，默认最新的在前
+func (s *SessionService) ListSessions(ctx context.Context, query *SessionQuery) ([]*model.PracticeSession, *PageInfo, error) {
+	if query.UserID == "" {
+		return nil, nil, invalidf("user id is required")
+	}
+	if query.Status != "" && query.Status != model.SessionStatusActive && query.Status != model.SessionStatusFinished {
+		return nil, nil, invalidf("unsupported session status %q", query.Status)
+	}
+
+	p, err := newPager(query.ListQuery, sessionSortFields, "-id")
+	if err != nil {
+		return nil, nil, err
+	}
+	filter := repository.SessionFilter{UserID: query.UserID, Status: query.Status}
+	sessions, total, err := s.sessionRepo.List(ctx, filter, p.request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	count, page := p.finish(total, len(sessions), func(i int) (interface{}, uint) {
+		if p.field.column == "started_at" {
+			return sessions[i].StartedAt, sessions[i].ID
+		}
+		return sessions[i].ID, sessions[i].ID
+	})
+	return sessions[:count], page, nil
+}
+
+// SubmitAnswer 评分并记录会话中一句的答案；同一句可以重复作答，会话保留最近一次的得分
+func (s *SessionService) SubmitAnswer(ctx context.Context, userID string, id uint, req *SubmitAnswerRequest) (*SessionAnswer, error) {
+	if strings.TrimSpace(req.Answer) == "" {
+		return nil, invalidf("answer is required")
+	}
+	if req.DurationMs < 0 || req.AudioReplays < 0 {
+		return nil, invalidf("duration and audio replays must not be negative")
+	}
+	session, err := s.getOwned(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status != model.SessionStatusActive {
+		return nil, ErrSessionFinished
+	}
+	item := findSessionItem(session, req.SentenceID)
+	if item == nil {
+		return nil, invalidf("sentence %d is not part of this session", req.SentenceID)
+	}
+	if item.Sentence == nil {
+		return nil, notFound(repository.ErrNotFound, "sentence")
+	}
+
+	normalizer, err := sentenceNormalizer(ctx, s.sceneRepo, item.Sentence)
+	if err != nil {
+		return nil, err
+	}
+	tokens, accuracy := Grade(normalizer, item.Sentence.Content, req.Answer)
+	result := &CheckResult{
+		SentenceID: item.SentenceID,
+		Answer:     req.Answer,
+		Corrected:  item.Sentence.Content,
+		Tokens:     tokens,
+		Accuracy:   accuracy,
+		Correct:    isAllMatched(tokens),
+	}
+
+	now := time.Now()
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		active, err := s.sessionRepo.LockActive(ctx, session.ID)
+		if err != nil {
+			return err
+		}
+		if !active {
+			return ErrSessionFinished
+		}
+
+		// 与 POST /progress 一样记录进度，评分结果沿用上面的，不再重复评分
+		detail := &AttemptDetail{
+			SubmittedText: req.Answer,
+			DurationMs:    req.DurationMs,
+			AudioReplays:  req.AudioReplays,
+		}
+		if err := s.progressService.recordAttempt(ctx, userID, item.Sentence, detail, true, tokens, accuracy); err != nil {
+			return err
+		}
+
+		// 持有会话锁后重新读取，避免覆盖并发提交的结果
+		if session, err = s.sessionRepo.GetByID(ctx, session.ID); err != nil {
+			return err
+		}
+		item = findSessionItem(session, req.SentenceID)
+		item.Attempts++
+		item.Score = result.Accuracy
+		item.Completed = result.Correct
+		item.DurationMs += req.DurationMs
+		item.MissedWords = mergeWords(item.MissedWords, missedTokens(item.Sentence.Language, tokens))
+		item.AnsweredAt = &now
+		if err := s.sessionRepo.UpdateItem(ctx, item); err != nil {
+			return err
+		}
+
+		applySummary(session, summarizeSession(session, now))
+		return s.sessionRepo.Update(ctx, session)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &SessionAnswer{Item: item, Result: result, NextPosition: nextPosition(session)}, nil
+}
+
+// FinishSession 结束会话并保存小结；会话已结束时直接返回保存的结果
+func (s *SessionService) FinishSession(ctx context.Context, userID string, id uint) (*SessionDetail, error) {
+	session, err := s.getOwned(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status == model.SessionStatusFinished {
+		return newSessionDetail(session, time.Now()), nil
+	}
+
+	now := time.Now()
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		active, err := s.sessionRepo.LockActive(ctx, session.ID)
+		if err != nil || !active {
+			return err
+		}
+		if session, err = s.sessionRepo.GetByID(ctx, session.ID); err != nil {
+			return err
+		}
+		session.Status = model.SessionStatusFinished
+		session.FinishedAt = &now
+		applySummary(session, summarizeSession(session, now))
+		return s.sessionRepo.Update(ctx, session)
+	})
+	if err != nil {
+		return nil, err
+	}
+	// 并发结束时由另一个请求保存了小结，重新读取
+	if session.Status != model.SessionStatusFinished {
+		if session, err = s.sessionRepo.GetByID(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+	return newSessionDetail(session, now), nil
+}
+
+// getOwned 获取会话并校验归属，他人的会话按不存在处理
+func (s *SessionService) getOwned(ctx context.Context, userID string, id uint) (*model.PracticeSession, error) {
+	if userID == "" {
+		return nil, invalidf("user id is required")
+	}
+	session, err := s.sessionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, notFound(err, "session")
+	}
+	if session.UserID != userID {
+		return nil, notFound(repository.ErrNotFound, "session")
+	}
+	return session, nil
+}
+
+func newSessionDetail(session *model.PracticeSession, now time.Time) *SessionDetail {
+	return &SessionDetail{
+		Session:      session,
+		Summary:      summarizeSession(session, now),
+		NextPosition: nextPosition(session),
+	}
+}
+
+// summarizeSession 按各句的作答结果汇总会话
+func summarizeSession(session *model.PracticeSession, now time.Time) *SessionSummary {
+	summary := &SessionSummary{Total: len(session.Items), MissedWords: []string{}, Retry: []*model.Sentence{}}
+	var scoreSum float64
+	for _, item := range session.Items {
+		if item.AnsweredAt == nil {
+			summary.Skipped++
+			continue
+		}
+		summary.Answered++
+		scoreSum += item.Score
+		summary.DurationMs += item.DurationMs
+		if item.Completed {
+			summary.Completed++
+		} else if item.Sentence != nil {
+			summary.Retry = append(summary.Retry, item.Sentence)
+		}
+		for _, word := range strings.Fields(item.MissedWords) {
+			if !slices.Contains(summary.MissedWords, word) {
+				summary.MissedWords = append(summary.MissedWords, word)
+			}
+		}
+	}
+	if summary.Answered > 0 {
+		summary.Accuracy = round2(scoreSum / float64(summary.Answered))
+	}
+	end := now
+	if session.FinishedAt != nil {
+		end = *session.FinishedAt
+	}
+	summary.ElapsedMs = end.Sub(session.StartedAt).Milliseconds()
+	return summary
+}
+
+// applySummary 将小结中的统计写回会话，便于列表直接展示
+func applySummary(session *model.PracticeSession, summary *SessionSummary) {
+	session.Total = summary.Total
+	session.Answered = summary.Answered
+	session.Completed = summary.Completed
+	session.Accuracy = summary.Accuracy
+	session.DurationMs = summary.DurationMs
+}
+
+func findSessionItem(session *model.PracticeSession, sentenceID uint) *model.PracticeSessionItem {
+	for _, item := range session.Items {
+		if item.SentenceID == sentenceID {
+			return item
+		}
+	}
+	return nil
+}
+
+// nextPosition 返回第一个未作答句子的位置
+func nextPosition(session *model.PracticeSession) *int {
+	for _, item := range session.Items {
+		if item.AnsweredAt == nil {
+			position := item.Position
+			return &position
+		}
+	}
+	return nil
+}
+
+// mergeWords 将新出错的词并入以空格分隔的词表，保持首次出现的顺序
+func mergeWords(existing string, words []string) string {
+	merged := strings.Fields(existing)
+	for _, word := range words {
+		if !slices.Contains(merged, word) {
+			merged = append(merged, word)
+		}
+	}
+	return strings.Join(merged, " ")
+}
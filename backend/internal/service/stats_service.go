@@ -0,0 +1,486 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"voicewriter/internal/auth"
+	"voicewriter/internal/model"
+	"voicewriter/internal/repository"
+	apperrors "voicewriter/pkg/errors"
+)
+
+const (
+	// defaultTrendDays 准确率趋势默认覆盖的天数
+	defaultTrendDays = 30
+	// maxTrendDays 准确率趋势最多覆盖的天数
+	maxTrendDays = 365
+	// missedWordLimit 返回的易错词个数
+	missedWordLimit = 10
+	// populationTTL 全体用户指标分布的缓存时间
+	populationTTL = 5 * time.Minute
+)
+
+// difficultyOrder 难度的展示顺序，其他难度按名称排在后面
+var difficultyOrder = map[string]int{"easy": 0, "medium": 1, "hard": 2}
+
+// ErrStatsForbidden 只能查看自己的学习统计
+var ErrStatsForbidden = apperrors.New(apperrors.KindForbidden, "cannot view another user's statistics")
+
+// StatsQuery 学习统计查询条件
+type StatsQuery struct {
+	Viewer   *auth.Identity // 发起请求的用户
+	UserID   string         // 查看的用户，空为 Viewer 自己；只有管理员可以查看其他用户
+	Days     int            // 准确率趋势覆盖的天数，0 为默认 30 天
+	TimeZone string         // 按哪个时区划分日期，IANA 名称，空为 UTC
+}
+
+// LearnerStats 用户的学习统计
+type LearnerStats struct {
+	UserID        string
+	Summary       StatsSummary
+	Scenes        []SceneCompletion
+	Difficulties  []DifficultyCompletion
+	AccuracyTrend []DailyAccuracy
+	MissedWords   []MissedWord
+	Comparison    PopulationComparison
+}
+
+// StatsSummary 学习概况
+type StatsSummary struct {
+	TotalSentences     int64
+	PracticedSentences int64
+	CompletedSentences int64
+	CompletionRate     float64 // 已完成句子占全部句子的比例，0-1
+	TotalAttempts      int64
+	GradedAttempts     int64   // 服务端评过分的作答次数
+	AverageAccuracy    float64 // 评过分的作答的平均得分，0-100
+	PracticeTimeMs     int64
+	CurrentStreak      int // 截至今天或昨天连续练习的天数
+	LongestStreak      int
+	LastPracticedAt    *time.Time
+}
+
+// Completion 一组句子的完成情况
+type Completion struct {
+	Total          int64
+	Practiced      int64
+	Completed      int64
+	CompletionRate float64 // 0-1
+}
+
+// SceneCompletion 场景的完成情况
+type SceneCompletion struct {
+	SceneID   uint
+	SceneName string
+	Completion
+}
+
+// DifficultyCompletion 难度的完成情况
+type DifficultyCompletion struct {
+	Difficulty string
+	Completion
+}
+
+// DailyAccuracy 某天的作答情况，只包含有作答的日期
+type DailyAccuracy struct {
+	Date            string // YYYY-MM-DD
+	Attempts        int
+	GradedAttempts  int
+	AverageAccuracy float64 // 当天评过分的作答的平均得分，没有时为 0
+	PracticeTimeMs  int64
+}
+
+// MissedWord 生词本中累计出错次数最多的词
+type MissedWord struct {
+	Word  string
+	Count int
+}
+
+// PopulationComparison 与全体有练习记录的用户对比，平均得分只和有评分作答的用户对比
+type PopulationComparison struct {
+	Users              int
+	CompletedSentences MetricComparison
+	AverageAccuracy    MetricComparison
+	PracticeTimeMs     MetricComparison
+}
+
+// MetricComparison 单项指标与全体用户中位数的对比
+type MetricComparison struct {
+	Value      float64
+	Median     float64
+	Percentile float64 // 低于该用户的用户占比（相同值计一半），0-100
+}
+
+// StatsService 学习统计服务
+type StatsService struct {
+	progressRepo   repository.ProgressRepository
+	attemptRepo    repository.AttemptRepository
+	vocabularyRepo repository.VocabularyRepository
+	sceneRepo      repository.SceneRepository
+
+	mu         sync.Mutex
+	population *population
+}
+
+// population 全体用户各项指标的分布，每项升序排列
+type population struct {
+	users        int
+	completed    []float64
+	accuracy     []float64
+	practiceTime []float64
+	expiresAt    time.Time
+}
+
+// NewStatsService 创建学习统计服务实例
+func NewStatsService(
+	progressRepo repository.ProgressRepository,
+	attemptRepo repository.AttemptRepository,
+	vocabularyRepo repository.VocabularyRepository,
+	sceneRepo repository.SceneRepository,
+) *StatsService {
+	return &StatsService{
+		progressRepo:   progressRepo,
+		attemptRepo:    attemptRepo,
+		vocabularyRepo: vocabularyRepo,
+		sceneRepo:      sceneRepo,
+	}
+}
+
+// GetLearnerStats 计算用户的学习统计
+func (s *StatsService) GetLearnerStats(ctx context.Context, query *StatsQuery) (*LearnerStats, error) {
+	if query.Viewer == nil {
+		return nil, auth.ErrInvalidToken
+	}
+	userID := query.UserID
+	if userID == "" {
+		userID = query.Viewer.UID
+	}
+	if userID != query.Viewer.UID && query.Viewer.Role != model.RoleAdmin {
+		return nil, ErrStatsForbidden
+	}
+	days := query.Days
+	if days == 0 {
+		days = defaultTrendDays
+	}
+	if days < 1 || days > maxTrendDays {
+		return nil, invalidf("days must be between 1 and %d", maxTrendDays)
+	}
+	loc := time.UTC
+	if query.TimeZone != "" {
+		var err error
+		if loc, err = time.LoadLocation(query.TimeZone); err != nil {
+			return nil, invalidf("invalid time zone %q", query.TimeZone)
+		}
+	}
+
+	// 各项统计都在数据库中分组汇总，这里只合并汇总后的少量行
+	groups, err := s.progressRepo.SummarizeCompletion(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	scenes, err := s.sceneRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	buckets, err := s.attemptRepo.SummarizeActivity(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &LearnerStats{UserID: userID}
+	stats.Scenes, stats.Difficulties, stats.Summary = completionStats(groups, scenes)
+	byDay := dailyActivity(buckets, loc)
+	summarizeActivity(&stats.Summary, byDay, time.Now().In(loc))
+	stats.AccuracyTrend = accuracyTrend(byDay, time.Now().In(loc).AddDate(0, 0, -days+1))
+	if len(buckets) > 0 {
+		latest, err := s.attemptRepo.List(ctx, repository.AttemptFilter{UserID: userID, Limit: 1})
+		if err != nil {
+			return nil, err
+		}
+		if len(latest) > 0 {
+			stats.Summary.LastPracticedAt = &latest[0].CreatedAt
+		}
+	}
+	if stats.MissedWords, err = s.missedWords(ctx, userID); err != nil {
+		return nil, err
+	}
+	pop, err := s.loadPopulation(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stats.Comparison = pop.compare(&stats.Summary)
+	return stats, nil
+}
+
+// completionStats 把按 (场景, 难度) 汇总的完成情况合并为总体、各场景和各难度的完成情况
+func completionStats(groups []repository.CompletionGroup, scenes []*model.Scene) ([]SceneCompletion, []DifficultyCompletion, StatsSummary) {
+	var total Completion
+	byScene := make(map[uint]*Completion)
+	byDifficulty := make(map[string]*Completion)
+	for _, group := range groups {
+		scene := byScene[group.SceneID]
+		if scene == nil {
+			scene = &Completion{}
+			byScene[group.SceneID] = scene
+		}
+		difficulty := byDifficulty[group.Difficulty]
+		if difficulty == nil {
+			difficulty = &Completion{}
+			byDifficulty[group.Difficulty] = difficulty
+		}
+		for _, c := range []*Completion{&total, scene, difficulty} {
+			c.Total += group.Total
+			c.Practiced += group.Practiced
+			c.Completed += group.Completed
+		}
+	}
+	summary := StatsSummary{
+		TotalSentences:     total.Total,
+		PracticedSentences: total.Practiced,
+		CompletedSentences: total.Completed,
+		CompletionRate:     ratio(total.Completed, total.Total),
+	}
+
+	sceneStats := make([]SceneCompletion, 0, len(scenes))
+	for _, scene := range scenes {
+		c, ok := byScene[scene.ID]
+		if !ok {
+			c = &Completion{}
+		}
+		c.CompletionRate = ratio(c.Completed, c.Total)
+		sceneStats = append(sceneStats, SceneCompletion{SceneID: scene.ID, SceneName: scene.Name, Completion: *c})
+	}
+	sort.Slice(sceneStats, func(i, j int) bool { return sceneStats[i].SceneID < sceneStats[j].SceneID })
+
+	difficultyStats := make([]DifficultyCompletion, 0, len(byDifficulty))
+	for difficulty, c := range byDifficulty {
+		c.CompletionRate = ratio(c.Completed, c.Total)
+		difficultyStats = append(difficultyStats, DifficultyCompletion{Difficulty: difficulty, Completion: *c})
+	}
+	sort.Slice(difficultyStats, func(i, j int) bool {
+		a, b := difficultyStats[i].Difficulty, difficultyStats[j].Difficulty
+		ra, okA := difficultyOrder[a]
+		rb, okB := difficultyOrder[b]
+		switch {
+		case okA && okB:
+			return ra < rb
+		case okA != okB:
+			return okA
+		default:
+			return a < b
+		}
+	})
+	return sceneStats, difficultyStats, summary
+}
+
+// dailyStats 某天的作答汇总
+type dailyStats struct {
+	attempts   int64
+	graded     int64
+	scoreSum   float64 // 只计评过分的作答
+	durationMs int64
+}
+
+// dailyActivity 按 loc 时区把各时间段的作答汇总归入日期（YYYY-MM-DD）
+func dailyActivity(buckets []repository.ActivityBucket, loc *time.Location) map[string]*dailyStats {
+	byDay := make(map[string]*dailyStats)
+	for _, bucket := range buckets {
+		day := time.Unix(bucket.BucketStart, 0).In(loc).Format(time.DateOnly)
+		d, ok := byDay[day]
+		if !ok {
+			d = &dailyStats{}
+			byDay[day] = d
+		}
+		d.attempts += bucket.Attempts
+		d.graded += bucket.Graded
+		d.scoreSum += bucket.ScoreSum
+		d.durationMs += bucket.DurationMs
+	}
+	return byDay
+}
+
+// summarizeActivity 汇总作答次数、平均得分、练习时长和连续练习天数；没有评分的作答只计次数和时长
+func summarizeActivity(summary *StatsSummary, byDay map[string]*dailyStats, today time.Time) {
+	if len(byDay) == 0 {
+		return
+	}
+
+	var scoreSum float64
+	days := make(map[string]bool, len(byDay))
+	for day, d := range byDay {
+		summary.TotalAttempts += d.attempts
+		summary.GradedAttempts += d.graded
+		summary.PracticeTimeMs += d.durationMs
+		scoreSum += d.scoreSum
+		days[day] = true
+	}
+	summary.AverageAccuracy = average(scoreSum, summary.GradedAttempts)
+	summary.CurrentStreak, summary.LongestStreak = streaks(days, today)
+}
+
+// streaks 计算当前和最长的连续练习天数；今天还没练习时，截至昨天的连续天数仍算当前连续
+func streaks(days map[string]bool, today time.Time) (int, int) {
+	dates := make([]time.Time, 0, len(days))
+	for day := range days {
+		date, _ := time.Parse(time.DateOnly, day)
+		dates = append(dates, date)
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+	longest, run := 0, 0
+	for i, date := range dates {
+		if i > 0 && dates[i-1].AddDate(0, 0, 1).Equal(date) {
+			run++
+		} else {
+			run = 1
+		}
+		longest = max(longest, run)
+	}
+
+	current := 0
+	day := today
+	if !days[day.Format(time.DateOnly)] {
+		day = day.AddDate(0, 0, -1)
+	}
+	for days[day.Format(time.DateOnly)] {
+		current++
+		day = day.AddDate(0, 0, -1)
+	}
+	return current, longest
+}
+
+// accuracyTrend 返回 since 当天及之后每天的作答情况，按日期升序
+func accuracyTrend(byDay map[string]*dailyStats, since time.Time) []DailyAccuracy {
+	start := since.Format(time.DateOnly)
+	trend := make([]DailyAccuracy, 0, len(byDay))
+	for day, d := range byDay {
+		if day < start {
+			continue
+		}
+		trend = append(trend, DailyAccuracy{
+			Date:            day,
+			Attempts:        int(d.attempts),
+			GradedAttempts:  int(d.graded),
+			AverageAccuracy: average(d.scoreSum, d.graded),
+			PracticeTimeMs:  d.durationMs,
+		})
+	}
+	sort.Slice(trend, func(i, j int) bool { return trend[i].Date < trend[j].Date })
+	return trend
+}
+
+// missedWords 从生词本中取累计出错次数最多的词，出错次数在评分时已经记录，不需要重新比对作答
+func (s *StatsService) missedWords(ctx context.Context, userID string) ([]MissedWord, error) {
+	entries, _, err := s.vocabularyRepo.List(ctx, repository.VocabularyFilter{UserID: userID},
+		repository.PageRequest{SortBy: "miss_count", Desc: true, Limit: missedWordLimit})
+	if err != nil {
+		return nil, err
+	}
+	words := make([]MissedWord, 0, len(entries))
+	for _, entry := range entries {
+		words = append(words, MissedWord{Word: entry.Word, Count: entry.MissCount})
+	}
+	return words, nil
+}
+
+// loadPopulation 返回全体用户的指标分布，过期后在数据库中重新汇总；统计不要求实时，缓存避免每次请求都扫描全部用户
+func (s *StatsService) loadPopulation(ctx context.Context) (*population, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.population != nil && time.Now().Before(s.population.expiresAt) {
+		return s.population, nil
+	}
+
+	progress, err := s.progressRepo.SummarizeByUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	attempts, err := s.attemptRepo.SummarizeByUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pop := &population{expiresAt: time.Now().Add(populationTTL)}
+	practiced := make(map[string]bool, len(attempts))
+	for _, summary := range attempts {
+		practiced[summary.UserID] = true
+		pop.practiceTime = append(pop.practiceTime, float64(summary.DurationMs))
+		if summary.Graded > 0 {
+			pop.accuracy = append(pop.accuracy, summary.AvgScore)
+		}
+	}
+	completed := make(map[string]bool, len(progress))
+	for _, summary := range progress {
+		completed[summary.UserID] = true
+		pop.completed = append(pop.completed, float64(summary.Completed))
+	}
+	// 只有进度或只有作答记录的用户，另一项按 0 计入
+	for id := range practiced {
+		if !completed[id] {
+			pop.completed = append(pop.completed, 0)
+		}
+	}
+	for id := range completed {
+		if !practiced[id] {
+			pop.practiceTime = append(pop.practiceTime, 0)
+		}
+	}
+	pop.users = len(pop.completed)
+	sort.Float64s(pop.completed)
+	sort.Float64s(pop.accuracy)
+	sort.Float64s(pop.practiceTime)
+	s.population = pop
+	return pop, nil
+}
+
+// compare 计算用户的完成句数、平均得分和练习时长在全体用户中的位置
+func (p *population) compare(summary *StatsSummary) PopulationComparison {
+	return PopulationComparison{
+		Users:              p.users,
+		CompletedSentences: compareMetric(float64(summary.CompletedSentences), p.completed),
+		AverageAccuracy:    compareMetric(summary.AverageAccuracy, p.accuracy),
+		PracticeTimeMs:     compareMetric(float64(summary.PracticeTimeMs), p.practiceTime),
+	}
+}
+
+// compareMetric 计算中位数和百分位排名，sorted 为升序的全体用户指标，为空时只返回用户自身的值
+func compareMetric(value float64, sorted []float64) MetricComparison {
+	result := MetricComparison{Value: round2(value)}
+	if len(sorted) == 0 {
+		return result
+	}
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		result.Median = round2((sorted[mid-1] + sorted[mid]) / 2)
+	} else {
+		result.Median = round2(sorted[mid])
+	}
+
+	below := sort.SearchFloat64s(sorted, value)
+	equal := sort.Search(len(sorted), func(i int) bool { return sorted[i] > value }) - below
+	result.Percentile = round2((float64(below) + float64(equal)/2) / float64(len(sorted)) * 100)
+	return result
+}
+
+// average 计算平均得分，没有评分的作答时为 0
+func average(scoreSum float64, graded int64) float64 {
+	if graded == 0 {
+		return 0
+	}
+	return round2(scoreSum / float64(graded))
+}
+
+func ratio(part, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return round2(float64(part) / float64(total))
+}
+
+// round2 保留两位小数
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"voicewriter/internal/auth"
+	"voicewriter/internal/model"
+	"voicewriter/internal/repository"
+)
+
+func TestDailyActivity(t *testing.T) {
+	// 2026-03-01 23:45 UTC 的时间段在上海是 3 月 2 日，在纽约是 3 月 1 日；加德满都为 UTC+5:45
+	late := time.Date(2026, 3, 1, 23, 45, 0, 0, time.UTC).Unix()
+	buckets := []repository.ActivityBucket{
+		{BucketStart: late - repository.ActivityBucketSeconds, Attempts: 1, ScoreSum: 50, DurationMs: 100},
+		{BucketStart: late, Attempts: 2, ScoreSum: 150, DurationMs: 200},
+	}
+	cases := []struct {
+		zone string
+		want map[string]int64
+	}{
+		{"UTC", map[string]int64{"2026-03-01": 3}},
+		{"Asia/Shanghai", map[string]int64{"2026-03-02": 3}},
+		{"America/New_York", map[string]int64{"2026-03-01": 3}},
+		{"Asia/Kathmandu", map[string]int64{"2026-03-02": 3}},
+		{"Pacific/Chatham", map[string]int64{"2026-03-02": 3}},
+	}
+	for _, tc := range cases {
+		loc, err := time.LoadLocation(tc.zone)
+		if err != nil {
+			t.Fatal(err)
+		}
+		byDay := dailyActivity(buckets, loc)
+		if len(byDay) != len(tc.want) {
+			t.Errorf("%s: days = %v, want %v", tc.zone, byDay, tc.want)
+			continue
+		}
+		for day, attempts := range tc.want {
+			if d := byDay[day]; d == nil || d.attempts != attempts || d.scoreSum != 200 || d.durationMs != 300 {
+				t.Errorf("%s: %s = %+v, want %d attempts", tc.zone, day, d, attempts)
+			}
+		}
+	}
+
+	// 跨越午夜的两个时间段分属两天
+	byDay := dailyActivity([]repository.ActivityBucket{
+		{BucketStart: late, Attempts: 1},
+		{BucketStart: late + repository.ActivityBucketSeconds, Attempts: 1},
+	}, time.UTC)
+	if len(byDay) != 2 || byDay["2026-03-01"] == nil || byDay["2026-03-02"] == nil {
+		t.Errorf("midnight split = %v", byDay)
+	}
+}
+
+func TestStreaks(t *testing.T) {
+	today := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	days := func(dates ...string) map[string]bool {
+		m := make(map[string]bool)
+		for _, d := range dates {
+			m[d] = true
+		}
+		return m
+	}
+	cases := []struct {
+		name             string
+		days             map[string]bool
+		current, longest int
+	}{
+		{"none", days(), 0, 0},
+		{"today", days("2026-03-08", "2026-03-09", "2026-03-10"), 3, 3},
+		{"through yesterday", days("2026-03-08", "2026-03-09"), 2, 2},
+		{"broken", days("2026-03-01", "2026-03-02", "2026-03-03", "2026-03-08"), 0, 3},
+	}
+	for _, tc := range cases {
+		if current, longest := streaks(tc.days, today); current != tc.current || longest != tc.longest {
+			t.Errorf("%s: streaks = %d, %d; want %d, %d", tc.name, current, longest, tc.current, tc.longest)
+		}
+	}
+}
+
+// TestGetLearnerStatsForbidden 普通用户查看他人的统计在访问仓储之前就被拒绝
+func TestGetLearnerStatsForbidden(t *testing.T) {
+	s := NewStatsService(nil, nil, nil, nil)
+	viewer := &auth.Identity{UID: "alice", Role: model.RoleUser}
+	if _, err := s.GetLearnerStats(context.Background(), &StatsQuery{Viewer: viewer, UserID: "bob"}); !errors.Is(err, ErrStatsForbidden) {
+		t.Errorf("view another user = %v, want ErrStatsForbidden", err)
+	}
+	if _, err := s.GetLearnerStats(context.Background(), &StatsQuery{UserID: "bob"}); !errors.Is(err, auth.ErrInvalidToken) {
+		t.Errorf("no viewer = %v, want ErrInvalidToken", err)
+	}
+}
+
+// TestSummarizeActivityGraded 平均得分只计评过分的作答，未评分的作答仍计入次数和时长
+func TestSummarizeActivityGraded(t *testing.T) {
+	byDay := map[string]*dailyStats{
+		"2026-03-09": {attempts: 3, graded: 2, scoreSum: 150, durationMs: 300},
+		"2026-03-10": {attempts: 2, graded: 0, durationMs: 200},
+	}
+	var summary StatsSummary
+	summarizeActivity(&summary, byDay, time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC))
+	if summary.TotalAttempts != 5 || summary.GradedAttempts != 2 || summary.AverageAccuracy != 75 || summary.PracticeTimeMs != 500 {
+		t.Errorf("summary = %+v", summary)
+	}
+
+	trend := accuracyTrend(byDay, time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC))
+	want := []DailyAccuracy{
+		{Date: "2026-03-09", Attempts: 3, GradedAttempts: 2, AverageAccuracy: 75, PracticeTimeMs: 300},
+		{Date: "2026-03-10", Attempts: 2, GradedAttempts: 0, AverageAccuracy: 0, PracticeTimeMs: 200},
+	}
+	if len(trend) != len(want) || trend[0] != want[0] || trend[1] != want[1] {
+		t.Errorf("trend = %+v, want %+v", trend, want)
+	}
+}
+
+func TestCompareMetric(t *testing.T) {
+	population := []float64{10, 20, 20, 40}
+	cases := []struct {
+		value float64
+		want  MetricComparison
+	}{
+		{20, MetricComparison{Value: 20, Median: 20, Percentile: 50}},
+		{5, MetricComparison{Value: 5, Median: 20, Percentile: 0}},
+		{40, MetricComparison{Value: 40, Median: 20, Percentile: 87.5}},
+		{50, MetricComparison{Value: 50, Median: 20, Percentile: 100}},
+	}
+	for _, tc := range cases {
+		if got := compareMetric(tc.value, population); got != tc.want {
+			t.Errorf("compareMetric(%v) = %+v, want %+v", tc.value, got, tc.want)
+		}
+	}
+	if got := compareMetric(30, nil); got != (MetricComparison{Value: 30}) {
+		t.Errorf("empty population = %+v", got)
+	}
+}
@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"voicewriter/internal/i18n"
+	"voicewriter/internal/model"
+	"voicewriter/internal/repository"
+)
+
+// SetSentenceTranslationRequest 设置句子翻译请求
+type SetSentenceTranslationRequest struct {
+	Text string `json:"text" binding:"required"`
+}
+
+// SetSceneTranslationRequest 设置场景多语言名称请求
+type SetSceneTranslationRequest struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Description string `json:"description"`
+}
+
+// TranslationService 多语言内容服务：按请求语言替换句子翻译和场景名称，并维护翻译数据
+// 句子翻译变更时同步更新搜索索引
+type TranslationService struct {
+	translationRepo repository.TranslationRepository
+	sentenceRepo    repository.SentenceRepository
+	sceneRepo       repository.SceneRepository
+	searchIndex     repository.SearchIndex
+}
+
+// NewTranslationService 创建多语言内容服务实例
+func NewTranslationService(
+	translationRepo repository.TranslationRepository,
+	sentenceRepo repository.SentenceRepository,
+	sceneRepo repository.SceneRepository,
+	searchIndex repository.SearchIndex,
+) *TranslationService {
+	return &TranslationService{
+		translationRepo: translationRepo,
+		sentenceRepo:    sentenceRepo,
+		sceneRepo:       sceneRepo,
+		searchIndex:     searchIndex,
+	}
+}
+
+// LocalizeSentences 按 locales 的优先级将 Translation 替换为对应语言的翻译
+// 候选语言都没有翻译时保留默认语言（中文）翻译
+func (s *TranslationService) LocalizeSentences(ctx context.Context, sentences []*model.Sentence, locales []string) error {
+	for _, sentence := range sentences {
+		sentence.TranslationLocale = i18n.DefaultLocale
+	}
+	lookup := translationLocales(locales)
+	if len(sentences) == 0 || len(lookup) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(sentences))
+	for i, sentence := range sentences {
+		ids[i] = sentence.ID
+	}
+	translations, err := s.translationRepo.ListSentenceTranslations(ctx, ids, lookup)
+	if err != nil {
+		return err
+	}
+
+	texts := make(map[uint]map[string]string)
+	for _, t := range translations {
+		if texts[t.SentenceID] == nil {
+			texts[t.SentenceID] = make(map[string]string)
+		}
+		texts[t.SentenceID][t.Locale] = t.Text
+	}
+
+	for _, sentence := range sentences {
+		for _, locale := range locales {
+			if locale == i18n.DefaultLocale {
+				break
+			}
+			if text, ok := texts[sentence.ID][locale]; ok {
+				sentence.Translation = text
+				sentence.TranslationLocale = locale
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// LocalizeScenes 按 locales 的优先级将 Name/Description 替换为对应语言的版本
+func (s *TranslationService) LocalizeScenes(ctx context.Context, scenes []*model.Scene, locales []string) error {
+	for _, scene := range scenes {
+		scene.Locale = i18n.DefaultLocale
+	}
+	lookup := translationLocales(locales)
+	if len(scenes) == 0 || len(lookup) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(scenes))
+	for i, scene := range scenes {
+		ids[i] = scene.ID
+	}
+	translations, err := s.translationRepo.ListSceneTranslations(ctx, ids, lookup)
+	if err != nil {
+		return err
+	}
+
+	byScene := make(map[uint]map[string]*model.SceneTranslation)
+	for _, t := range translations {
+		if byScene[t.SceneID] == nil {
+			byScene[t.SceneID] = make(map[string]*model.SceneTranslation)
+		}
+		byScene[t.SceneID][t.Locale] = t
+	}
+
+	for _, scene := range scenes {
+		for _, locale := range locales {
+			if locale == i18n.DefaultLocale {
+				break
+			}
+			if t, ok := byScene[scene.ID][locale]; ok {
+				scene.Name = t.Name
+				scene.Description = t.Description
+				scene.Locale = locale
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// ListSentenceTranslations 获取句子的全部翻译
+func (s *TranslationService) ListSentenceTranslations(ctx context.Context, sentenceID uint) ([]*model.SentenceTranslation, error) {
+	if _, err := s.sentenceRepo.GetByID(ctx, sentenceID); err != nil {
+		return nil, notFound(err, "sentence")
+	}
+	return s.translationRepo.ListSentenceTranslations(ctx, []uint{sentenceID}, nil)
+}
+
+// SetSentenceTranslation 创建或覆盖句子在指定语言下的翻译
+func (s *TranslationService) SetSentenceTranslation(ctx context.Context, sentenceID uint, locale string, req *SetSentenceTranslationRequest) (*model.SentenceTranslation, error) {
+	locale, err := i18n.Normalize(locale)
+	if err != nil {
+		return nil, err
+	}
+	text := strings.TrimSpace(req.Text)
+	if text == "" {
+		return nil, invalidf("translation text is required")
+	}
+	sentence, err := s.sentenceRepo.GetByID(ctx, sentenceID)
+	if err != nil {
+		return nil, notFound(err, "sentence")
+	}
+
+	if err := s.translationRepo.UpsertSentenceTranslation(ctx, &model.SentenceTranslation{
+		SentenceID: sentenceID,
+		Locale:     locale,
+		Text:       text,
+	}); err != nil {
+		return nil, err
+	}
+	if err := indexSentences(ctx, s.searchIndex, s.translationRepo, sentence); err != nil {
+		return nil, err
+	}
+
+	// 冲突更新时部分数据库不回填主键，重新读取
+	translations, err := s.translationRepo.ListSentenceTranslations(ctx, []uint{sentenceID}, []string{locale})
+	if err != nil {
+		return nil, err
+	}
+	if len(translations) == 0 {
+		return nil, notFound(repository.ErrNotFound, "translation")
+	}
+	return translations[0], nil
+}
+
+// DeleteSentenceTranslation 删除句子在指定语言下的翻译
+func (s *TranslationService) DeleteSentenceTranslation(ctx context.Context, sentenceID uint, locale string) error {
+	locale, err := i18n.Normalize(locale)
+	if err != nil {
+		return err
+	}
+	if err := s.translationRepo.DeleteSentenceTranslation(ctx, sentenceID, locale); err != nil {
+		return notFound(err, "translation")
+	}
+	// 句子已删除时索引中也没有它，无需更新
+	sentence, err := s.sentenceRepo.GetByID(ctx, sentenceID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	return indexSentences(ctx, s.searchIndex, s.translationRepo, sentence)
+}
+
+// ListSceneTranslations 获取场景的全部多语言名称
+func (s *TranslationService) ListSceneTranslations(ctx context.Context, sceneID uint) ([]*model.SceneTranslation, error) {
+	if _, err := s.sceneRepo.GetByID(ctx, sceneID); err != nil {
+		return nil, notFound(err, "scene")
+	}
+	return s.translationRepo.ListSceneTranslations(ctx, []uint{sceneID}, nil)
+}
+
+// SetSceneTranslation 创建或覆盖场景在指定语言下的名称和描述
+func (s *TranslationService) SetSceneTranslation(ctx context.Context, sceneID uint, locale string, req *SetSceneTranslationRequest) (*model.SceneTranslation, error) {
+	locale, err := i18n.Normalize(locale)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, invalidf("scene name is required")
+	}
+	if _, err := s.sceneRepo.GetByID(ctx, sceneID); err != nil {
+		return nil, notFound(err, "scene")
+	}
+
+	if err := s.translationRepo.UpsertSceneTranslation(ctx, &model.SceneTranslation{
+		SceneID:     sceneID,
+		Locale:      locale,
+		Name:        name,
+		Description: req.Description,
+	}); err != nil {
+		return nil, err
+	}
+
+	translations, err := s.translationRepo.ListSceneTranslations(ctx, []uint{sceneID}, []string{locale})
+	if err != nil {
+		return nil, err
+	}
+	if len(translations) == 0 {
+		return nil, notFound(repository.ErrNotFound, "translation")
+	}
+	return translations[0], nil
+}
+
+// DeleteSceneTranslation 删除场景在指定语言下的名称和描述
+func (s *TranslationService) DeleteSceneTranslation(ctx context.Context, sceneID uint, locale string) error {
+	locale, err := i18n.Normalize(locale)
+	if err != nil {
+		return err
+	}
+	return notFound(s.translationRepo.DeleteSceneTranslation(ctx, sceneID, locale), "translation")
+}
+
+// translationLocales 返回需要查询翻译表的候选语言，排在默认语言之后的候选不会被使用
+func translationLocales(locales []string) []string {
+	var lookup []string
+	for _, locale := range locales {
+		if locale == i18n.DefaultLocale {
+			break
+		}
+		lookup = append(lookup, locale)
+	}
+	return lookup
+}
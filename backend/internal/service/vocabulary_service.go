@@ -0,0 +1,363 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"voicewriter/internal/content"
+	"voicewriter/internal/i18n"
+	"voicewriter/internal/model"
+	"voicewriter/internal/repository"
+	"voicewriter/internal/textnorm"
+)
+
+const (
+	// maxVocabularyWordLength 生词最大长度（字符），超长的词通常是漏掉空格的整段文本，不计入生词本
+	maxVocabularyWordLength = 100
+	// practiceWordLimit 练习模式选取的最薄弱词数
+	practiceWordLimit = 20
+	// practiceCandidateLimit 每个词最多检索的候选句子数
+	practiceCandidateLimit = 50
+	// defaultPracticeLimit 练习模式默认返回的句子数
+	defaultPracticeLimit = 10
+	// maxPracticeLimit 练习模式最多返回的句子数
+	maxPracticeLimit = 50
+	// exportBatchSize 导出时每批读取的生词数
+	exportBatchSize = 500
+)
+
+// vocabularySortFields 生词列表可排序字段
+var vocabularySortFields = map[string]sortField{
+	"id":           {column: "id", kind: sortKindID},
+	"word":         {column: "word", kind: sortKindString},
+	"miss_count":   {column: "miss_count", kind: sortKindInt},
+	"last_seen_at": {column: "last_seen_at", kind: sortKindTime},
+}
+
+// vocabularyColumns 导出的列
+var vocabularyColumns = []string{"word", "language", "miss_count", "last_seen_at", "starred", "archived", "sentence", "translation"}
+
+// VocabularyQuery 生词列表查询条件
+type VocabularyQuery struct {
+	ListQuery
+	UserID   string
+	Language string
+	Query    string
+	Starred  *bool
+	Archived *bool
+}
+
+// UpdateVocabularyRequest 更新生词请求，未提供的字段保持不变
+type UpdateVocabularyRequest struct {
+	Starred  *bool `json:"starred"`
+	Archived *bool `json:"archived"`
+}
+
+// PracticeQuery 生词练习查询条件
+type PracticeQuery struct {
+	UserID   string
+	Language string
+	Limit    int
+}
+
+// PracticeSentence 练习句子及其包含的薄弱词
+type PracticeSentence struct {
+	Sentence *model.Sentence `json:"sentence"`
+	Words    []string        `json:"words"`
+}
+
+// PracticeSet 生词练习内容：最薄弱的词和包含这些词最多的句子
+type PracticeSet struct {
+	Words     []*model.VocabularyEntry `json:"words"`
+	Sentences []PracticeSentence       `json:"sentences"`
+}
+
+// vocabularyRecord 导出的一条生词
+type vocabularyRecord struct {
+	Word        string    `json:"word"`
+	Language    string    `json:"language"`
+	MissCount   int       `json:"miss_count"`
+	LastSeenAt  time.Time `json:"last_seen_at"`
+	Starred     bool      `json:"starred"`
+	Archived    bool      `json:"archived"`
+	Sentence    string    `json:"sentence"`
+	Translation string    `json:"translation"`
+}
+
+// VocabularyService 生词本服务
+type VocabularyService struct {
+	vocabularyRepo repository.VocabularyRepository
+	sentenceRepo   repository.SentenceRepository
+	searchIndex    repository.SearchIndex
+}
+
+// NewVocabularyService 创建生词本服务实例
+func NewVocabularyService(
+	vocabularyRepo repository.VocabularyRepository,
+	sentenceRepo repository.SentenceRepository,
+	searchIndex repository.SearchIndex,
+) *VocabularyService {
+	return &VocabularyService{
+		vocabularyRepo: vocabularyRepo,
+		sentenceRepo:   sentenceRepo,
+		searchIndex:    searchIndex,
+	}
+}
+
+// ListVocabulary 分页查询用户的生词，默认按出错次数降序，未指定 Archived 时只列出未归档的词
+func (s *VocabularyService) ListVocabulary(ctx context.Context, query *VocabularyQuery) ([]*model.VocabularyEntry, *PageInfo, error) {
+	filter, err := vocabularyFilter(query)
+	if err != nil {
+		return nil, nil, err
+	}
+	if filter.Archived == nil {
+		archived := false
+		filter.Archived = &archived
+	}
+
+	p, err := newPager(query.ListQuery, vocabularySortFields, "-miss_count")
+	if err != nil {
+		return nil, nil, err
+	}
+	entries, total, err := s.vocabularyRepo.List(ctx, filter, p.request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	count, page := p.finish(total, len(entries), func(i int) (interface{}, uint) {
+		return vocabularySortValue(entries[i], p.field.column), entries[i].ID
+	})
+	return entries[:count], page, nil
+}
+
+// UpdateEntry 标星或归档生词，只能修改自己的生词
+func (s *VocabularyService) UpdateEntry(ctx context.Context, userID string, id uint, req *UpdateVocabularyRequest) (*model.VocabularyEntry, error) {
+	if id == 0 {
+		return nil, invalidf("invalid vocabulary id")
+	}
+	if req.Starred == nil && req.Archived == nil {
+		return nil, invalidf("starred or archived is required")
+	}
+
+	entry, err := s.vocabularyRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, notFound(err, "vocabulary entry")
+	}
+	// 不暴露其他用户的生词是否存在
+	if entry.UserID != userID {
+		return nil, notFound(repository.ErrNotFound, "vocabulary entry")
+	}
+
+	if req.Starred != nil {
+		entry.Starred = *req.Starred
+	}
+	if req.Archived != nil {
+		entry.Archived = *req.Archived
+	}
+	if err := s.vocabularyRepo.Update(ctx, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// Export 按 csv 或 jsonl 格式导出用户的生词，未指定 Archived 时包含已归档的词
+func (s *VocabularyService) Export(ctx context.Context, w io.Writer, format string, query *VocabularyQuery) error {
+	if format != content.FormatCSV && format != content.FormatJSONL {
+		return invalidf("unsupported export format %q, expected csv or jsonl", format)
+	}
+	filter, err := vocabularyFilter(query)
+	if err != nil {
+		return err
+	}
+
+	var records []vocabularyRecord
+	page := repository.PageRequest{Limit: exportBatchSize}
+	for {
+		entries, _, err := s.vocabularyRepo.List(ctx, filter, page)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			record := vocabularyRecord{
+				Word:       entry.Word,
+				Language:   entry.Language,
+				MissCount:  entry.MissCount,
+				LastSeenAt: entry.LastSeenAt.UTC(),
+				Starred:    entry.Starred,
+				Archived:   entry.Archived,
+			}
+			if entry.Sentence != nil {
+				record.Sentence = entry.Sentence.Content
+				record.Translation = entry.Sentence.Translation
+			}
+			records = append(records, record)
+		}
+		if len(entries) < exportBatchSize {
+			break
+		}
+		page.After = &repository.Cursor{ID: entries[len(entries)-1].ID}
+	}
+
+	if format == content.FormatJSONL {
+		encoder := json.NewEncoder(w)
+		for _, record := range records {
+			if err := encoder.Encode(record); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(vocabularyColumns); err != nil {
+		return err
+	}
+	for _, r := range records {
+		row := []string{
+			r.Word, r.Language, strconv.Itoa(r.MissCount), r.LastSeenAt.Format(time.RFC3339),
+			strconv.FormatBool(r.Starred), strconv.FormatBool(r.Archived), r.Sentence, r.Translation,
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// Practice 选出用户出错次数最多的未归档词，返回包含这些词最多的句子
+// 句子按所含薄弱词的出错次数之和降序排列
+func (s *VocabularyService) Practice(ctx context.Context, query *PracticeQuery) (*PracticeSet, error) {
+	archived := false
+	filter, err := vocabularyFilter(&VocabularyQuery{UserID: query.UserID, Language: query.Language, Archived: &archived})
+	if err != nil {
+		return nil, err
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultPracticeLimit
+	}
+	if limit > maxPracticeLimit {
+		limit = maxPracticeLimit
+	}
+
+	words, _, err := s.vocabularyRepo.List(ctx, filter, repository.PageRequest{SortBy: "miss_count", Desc: true, Limit: practiceWordLimit})
+	if err != nil {
+		return nil, err
+	}
+	set := &PracticeSet{Words: words, Sentences: []PracticeSentence{}}
+	if len(words) == 0 {
+		return set, nil
+	}
+
+	// 搜索索引同时匹配翻译且可能按前缀命中，候选句子需按原文分词再确认
+	weight := make(map[[2]string]int, len(words))
+	seen := make(map[uint]bool)
+	var candidates []uint
+	for _, entry := range words {
+		weight[[2]string{entry.Language, entry.Word}] = entry.MissCount
+		hits, _, err := s.searchIndex.Search(ctx, repository.SearchQuery{Text: entry.Word, Language: entry.Language, Limit: practiceCandidateLimit})
+		if err != nil {
+			return nil, err
+		}
+		for _, hit := range hits {
+			if !seen[hit.ID] {
+				seen[hit.ID] = true
+				candidates = append(candidates, hit.ID)
+			}
+		}
+	}
+
+	sentences, err := s.sentenceRepo.GetByIDs(ctx, candidates)
+	if err != nil {
+		return nil, err
+	}
+	scores := make(map[uint]int, len(sentences))
+	for _, sentence := range sentences {
+		var matched []string
+		for _, tok := range tokenize(textnorm.New(sentence.Language, textnorm.Normal), sentence.Content) {
+			key := [2]string{sentence.Language, tok.norm}
+			if w, ok := weight[key]; ok && !slices.Contains(matched, tok.norm) {
+				matched = append(matched, tok.norm)
+				scores[sentence.ID] += w
+			}
+		}
+		if len(matched) > 0 {
+			set.Sentences = append(set.Sentences, PracticeSentence{Sentence: sentence, Words: matched})
+		}
+	}
+
+	sort.Slice(set.Sentences, func(i, j int) bool {
+		a, b := set.Sentences[i], set.Sentences[j]
+		if scores[a.Sentence.ID] != scores[b.Sentence.ID] {
+			return scores[a.Sentence.ID] > scores[b.Sentence.ID]
+		}
+		if len(a.Words) != len(b.Words) {
+			return len(a.Words) > len(b.Words)
+		}
+		return a.Sentence.ID < b.Sentence.ID
+	})
+	if len(set.Sentences) > limit {
+		set.Sentences = set.Sentences[:limit]
+	}
+	return set, nil
+}
+
+// vocabularyFilter 校验并转换生词查询条件
+func vocabularyFilter(query *VocabularyQuery) (repository.VocabularyFilter, error) {
+	if query.UserID == "" {
+		return repository.VocabularyFilter{}, invalidf("user id is required")
+	}
+	filter := repository.VocabularyFilter{
+		UserID:   query.UserID,
+		Query:    strings.ToLower(strings.TrimSpace(query.Query)),
+		Starred:  query.Starred,
+		Archived: query.Archived,
+	}
+	if query.Language != "" {
+		language, err := i18n.Normalize(query.Language)
+		if err != nil {
+			return filter, fmt.Errorf("%w: %v", ErrInvalidFilter, err)
+		}
+		filter.Language = language
+	}
+	return filter, nil
+}
+
+// missedTokens 返回比对结果中漏写和拼错的原句词，已按句子语言规范化并去重
+func missedTokens(language string, diffs []TokenDiff) []string {
+	var words []string
+	for _, diff := range diffs {
+		if diff.Status != TokenMissing && diff.Status != TokenMisspelled {
+			continue
+		}
+		word := normalizeToken(language, diff.Expected)
+		if word == "" || utf8.RuneCountInString(word) > maxVocabularyWordLength || slices.Contains(words, word) {
+			continue
+		}
+		words = append(words, word)
+	}
+	return words
+}
+
+func vocabularySortValue(entry *model.VocabularyEntry, column string) interface{} {
+	switch column {
+	case "word":
+		return entry.Word
+	case "miss_count":
+		return entry.MissCount
+	case "last_seen_at":
+		return entry.LastSeenAt
+	default:
+		return entry.ID
+	}
+}
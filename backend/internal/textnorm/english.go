@@ -0,0 +1,178 @@
+package textnorm
+
+import (
+	"strconv"
+	"strings"
+)
+
+// english 英语规则：展开常见缩写，阿拉伯数字转换为英文数词
+var english = languageRules{
+	expand: expandEnglish,
+}
+
+// maxNumberDigits 超过该位数的数字不转换
+const maxNumberDigits = 9
+
+// englishContractions 不能按后缀规则展开的缩写；含义有歧义的 's、'd 只展开代词后的 's
+var englishContractions = map[string][]string{
+	"won't":   {"will", "not"},
+	"can't":   {"can", "not"},
+	"cannot":  {"can", "not"},
+	"shan't":  {"shall", "not"},
+	"i'm":     {"i", "am"},
+	"let's":   {"let", "us"},
+	"it's":    {"it", "is"},
+	"he's":    {"he", "is"},
+	"she's":   {"she", "is"},
+	"that's":  {"that", "is"},
+	"what's":  {"what", "is"},
+	"there's": {"there", "is"},
+	"here's":  {"here", "is"},
+	"where's": {"where", "is"},
+	"who's":   {"who", "is"},
+	"how's":   {"how", "is"},
+}
+
+// englishSuffixes 按后缀展开的缩写
+var englishSuffixes = []struct {
+	suffix string
+	word   string
+}{
+	{"n't", "not"},
+	{"'re", "are"},
+	{"'ve", "have"},
+	{"'ll", "will"},
+}
+
+var (
+	englishOnes = []string{
+		"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine",
+		"ten", "eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen", "seventeen", "eighteen", "nineteen",
+	}
+	englishTens   = []string{"", "", "twenty", "thirty", "forty", "fifty", "sixty", "seventy", "eighty", "ninety"}
+	englishScales = []struct {
+		value int
+		word  string
+	}{
+		{1000000000, "billion"},
+		{1000000, "million"},
+		{1000, "thousand"},
+	}
+	// englishOrdinals 不规则的序数词
+	englishOrdinals = map[string]string{
+		"one": "first", "two": "second", "three": "third", "five": "fifth",
+		"eight": "eighth", "nine": "ninth", "twelve": "twelfth",
+	}
+)
+
+func expandEnglish(word string) []string {
+	if words, ok := englishContractions[word]; ok {
+		return words
+	}
+	for _, s := range englishSuffixes {
+		if stem, ok := strings.CutSuffix(word, s.suffix); ok && stem != "" {
+			return []string{stem, s.word}
+		}
+	}
+	if words := englishNumeral(word); words != nil {
+		return words
+	}
+	return splitCompoundNumber(word)
+}
+
+// englishNumeral 将 "21"、"21st" 转换为 twenty one、twenty first
+func englishNumeral(word string) []string {
+	digits := strings.TrimRight(word, "abcdefghijklmnopqrstuvwxyz")
+	suffix := word[len(digits):]
+	if digits == "" || len(digits) > maxNumberDigits || (len(digits) > 1 && digits[0] == '0') {
+		return nil
+	}
+	n, err := strconv.Atoi(digits)
+	if err != nil {
+		return nil
+	}
+	words := englishNumber(n)
+	switch {
+	case suffix == "":
+		return words
+	case suffix == ordinalSuffix(n):
+		last := len(words) - 1
+		words[last] = englishOrdinal(words[last])
+		return words
+	}
+	return nil
+}
+
+// englishNumber 数字的英文读法，不带 and，如 105 -> one hundred five
+func englishNumber(n int) []string {
+	switch {
+	case n < 20:
+		return []string{englishOnes[n]}
+	case n < 100:
+		words := []string{englishTens[n/10]}
+		if n%10 != 0 {
+			words = append(words, englishOnes[n%10])
+		}
+		return words
+	case n < 1000:
+		words := []string{englishOnes[n/100], "hundred"}
+		if n%100 != 0 {
+			words = append(words, englishNumber(n%100)...)
+		}
+		return words
+	}
+	for _, scale := range englishScales {
+		if n >= scale.value {
+			words := append(englishNumber(n/scale.value), scale.word)
+			if n%scale.value != 0 {
+				words = append(words, englishNumber(n%scale.value)...)
+			}
+			return words
+		}
+	}
+	return nil
+}
+
+func ordinalSuffix(n int) string {
+	if n%100 >= 11 && n%100 <= 13 {
+		return "th"
+	}
+	switch n % 10 {
+	case 1:
+		return "st"
+	case 2:
+		return "nd"
+	case 3:
+		return "rd"
+	}
+	return "th"
+}
+
+func englishOrdinal(word string) string {
+	if ordinal, ok := englishOrdinals[word]; ok {
+		return ordinal
+	}
+	if stem, ok := strings.CutSuffix(word, "y"); ok {
+		return stem + "ieth"
+	}
+	return word + "th"
+}
+
+// splitCompoundNumber 拆开连写的十位和个位数词：去掉连字符后的 "twentyone" -> twenty one
+func splitCompoundNumber(word string) []string {
+	for _, tens := range englishTens[2:] {
+		unit, ok := strings.CutPrefix(word, tens)
+		if !ok || unit == "" {
+			continue
+		}
+		for _, one := range englishOnes[1:10] {
+			if unit == one {
+				return []string{tens, one}
+			}
+			if unit == englishOrdinal(one) {
+				return []string{tens, unit}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package textnorm
+
+import "strings"
+
+// japanese 日语规则：展开踊り字，旧字体统一为新字体；宽松级别下片假名统一为平假名。
+// 半角片假名由 NFKC 转换为全角；汉字与假名写法之间的对应需要读音词典，不在此处理
+var japanese = languageRules{
+	text: expandIterationMarks,
+	fold: foldJapanese,
+}
+
+// kanjiVariants 常见旧字体、异体字到常用汉字的映射
+var kanjiVariants = map[rune]rune{
+	'亞': '亜', '惡': '悪', '壓': '圧', '圓': '円', '假': '仮', '價': '価', '會': '会', '學': '学',
+	'氣': '気', '舊': '旧', '區': '区', '縣': '県', '檢': '検', '顯': '顕', '驗': '験', '國': '国',
+	'齋': '斎', '齊': '斉', '﨑': '崎', '實': '実', '壽': '寿', '從': '従', '處': '処', '將': '将',
+	'乘': '乗', '嶋': '島', '聲': '声', '靜': '静', '淺': '浅', '戰': '戦', '雙': '双', '裝': '装',
+	'體': '体', '對': '対', '臺': '台', '髙': '高', '澤': '沢', '團': '団', '傳': '伝', '圖': '図',
+	'當': '当', '燈': '灯', '讀': '読', '發': '発', '濱': '浜', '豐': '豊', '變': '変', '邊': '辺',
+	'邉': '辺', '辯': '弁', '辨': '弁', '瓣': '弁', '萬': '万', '滿': '満', '譯': '訳', '來': '来',
+	'樂': '楽', '覽': '覧', '兩': '両', '禮': '礼', '歷': '歴', '櫻': '桜', '驛': '駅', '關': '関',
+	'觀': '観', '廣': '広', '黑': '黒', '賣': '売', '鐵': '鉄', '轉': '転', '歸': '帰', '繪': '絵',
+	'續': '続', '醫': '医', '齒': '歯', '辭': '辞', '兒': '児', '號': '号', '藝': '芸', '惠': '恵',
+}
+
+// expandIterationMarks 把踊り字「々」替换为前一个汉字：人々 -> 人人
+func expandIterationMarks(text string) string {
+	if !strings.ContainsRune(text, '々') {
+		return text
+	}
+	runes := []rune(text)
+	for i, r := range runes {
+		if r == '々' && i > 0 {
+			runes[i] = runes[i-1]
+		}
+	}
+	return string(runes)
+}
+
+func foldJapanese(r rune, strictness Strictness) rune {
+	if v, ok := kanjiVariants[r]; ok {
+		return v
+	}
+	if strictness == Lenient {
+		return katakanaToHiragana(r)
+	}
+	return r
+}
+
+// katakanaToHiragana 片假名转换为对应的平假名，没有对应平假名的字符（如ヷ、长音符）保持不变
+func katakanaToHiragana(r rune) rune {
+	switch {
+	case r >= 'ァ' && r <= 'ヶ', r == 'ヽ', r == 'ヾ':
+		return r - ('ァ' - 'ぁ')
+	}
+	return r
+}
@@ -0,0 +1,175 @@
+// Package textnorm 听写答案规范化：按句子语言和场景的评分严格程度，把原句和用户输入转换为可以逐词比较的形式
+package textnorm
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Strictness 评分严格程度
+type Strictness string
+
+const (
+	// Strict 只统一 Unicode 组合形式和引号，区分大小写，不应用语言规则
+	Strict Strictness = "strict"
+	// Normal 默认级别：另外统一全角半角等兼容字符、忽略大小写，并应用语言规则（缩写、数字、异体字）
+	Normal Strictness = "normal"
+	// Lenient 在 Normal 的基础上忽略拉丁字母的变音符号，日语不区分平假名和片假名
+	Lenient Strictness = "lenient"
+)
+
+// Valid 是否为已知的严格程度
+func (s Strictness) Valid() bool {
+	switch s {
+	case Strict, Normal, Lenient:
+		return true
+	}
+	return false
+}
+
+// ParseStrictness 解析严格程度，不区分大小写，空字符串返回 Normal
+func ParseStrictness(value string) (Strictness, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return Normal, nil
+	}
+	s := Strictness(value)
+	if !s.Valid() {
+		return "", fmt.Errorf("strictness must be one of strict, normal, lenient, got %q", value)
+	}
+	return s, nil
+}
+
+// languageRules 语言相关的规则，Strict 级别不使用
+type languageRules struct {
+	// text 整句替换，在分词之前执行
+	text func(text string) string
+	// fold 逐字符替换，如异体字、假名
+	fold func(r rune, strictness Strictness) rune
+	// expand 将一个已规范化的词展开为多个词，如缩写和数字；不适用时返回 nil
+	expand func(word string) []string
+}
+
+// languages 按 BCP 47 主语言代码注册的规则，未注册的语言只做通用规范化
+var languages = map[string]languageRules{
+	"en": english,
+	"ja": japanese,
+}
+
+// Normalizer 某个语言和严格程度下的规范化流程，可以并发使用
+type Normalizer struct {
+	strictness Strictness
+	rules      languageRules
+}
+
+// New 创建规范化流程，未知的严格程度按 Normal 处理
+func New(language string, strictness Strictness) *Normalizer {
+	if !strictness.Valid() {
+		strictness = Normal
+	}
+	n := &Normalizer{strictness: strictness}
+	if strictness != Strict {
+		n.rules = languages[baseLanguage(language)]
+	}
+	return n
+}
+
+// Strictness 返回使用的严格程度
+func (n *Normalizer) Strictness() Strictness {
+	return n.strictness
+}
+
+// Text 整句规范化，在分词之前执行：Unicode 规范化、统一引号和连字符
+func (n *Normalizer) Text(text string) string {
+	if n.strictness == Strict {
+		text = norm.NFC.String(text)
+	} else {
+		// NFKC 把全角字母数字、半角片假名、连字等兼容字符转换为标准形式
+		text = norm.NFKC.String(text)
+	}
+	text = strings.Map(foldPunctuation, text)
+	if n.rules.text != nil {
+		text = n.rules.text(text)
+	}
+	return text
+}
+
+// Words 将一个词规范化为比较用的形式，缩写和数字可能展开为多个词；去掉标点后为空时返回 nil
+func (n *Normalizer) Words(raw string) []string {
+	word := n.Fold(raw)
+	if word == "" {
+		return nil
+	}
+	if n.rules.expand != nil {
+		if words := n.rules.expand(word); words != nil {
+			return words
+		}
+	}
+	return []string{word}
+}
+
+// Fold 规范化单个词但不展开缩写和数字：去掉标点（保留词内的撇号），按严格程度统一大小写、变音符号和字形
+func (n *Normalizer) Fold(raw string) string {
+	var b strings.Builder
+	for _, r := range raw {
+		switch {
+		case r == '\'' || r == '’':
+			b.WriteRune('\'')
+		case unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.IsMark(r):
+			if n.strictness != Strict {
+				r = unicode.ToLower(r)
+			}
+			if n.rules.fold != nil {
+				r = n.rules.fold(r, n.strictness)
+			}
+			b.WriteRune(r)
+		}
+	}
+	word := strings.Trim(b.String(), "'")
+	if n.strictness == Lenient {
+		word = stripLatinMarks(word)
+	}
+	return word
+}
+
+// foldPunctuation 把各种弯引号、撇号和连字符统一为 ASCII 形式
+func foldPunctuation(r rune) rune {
+	switch r {
+	case '‘', '’', '‚', '‛', 'ʼ', '′', '＇':
+		return '\''
+	case '“', '”', '„', '‟', '″', '＂':
+		return '"'
+	case '‐', '‑', '‒', '–', '—', '―', '−':
+		return '-'
+	}
+	return r
+}
+
+// stripLatinMarks 去掉拉丁字母上的变音符号（café -> cafe），其他文字的组合符号（如日语浊点）保留
+func stripLatinMarks(word string) string {
+	var b strings.Builder
+	latin := false
+	for _, r := range norm.NFD.String(word) {
+		if unicode.Is(unicode.Mn, r) {
+			if latin {
+				continue
+			}
+		} else {
+			latin = unicode.Is(unicode.Latin, r)
+		}
+		b.WriteRune(r)
+	}
+	return norm.NFC.String(b.String())
+}
+
+// baseLanguage 返回 BCP 47 标签的主语言代码，如 "en-US" -> "en"
+func baseLanguage(language string) string {
+	language = strings.ToLower(strings.TrimSpace(language))
+	if i := strings.IndexAny(language, "-_"); i >= 0 {
+		language = language[:i]
+	}
+	return language
+}
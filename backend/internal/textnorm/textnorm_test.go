@@ -0,0 +1,106 @@
+package textnorm
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// normalize 模拟评分时的流程：整句规范化、按空白分词，再逐词规范化
+func normalize(n *Normalizer, text string) string {
+	var words []string
+	for _, field := range strings.Fields(n.Text(text)) {
+		words = append(words, n.Words(field)...)
+	}
+	return strings.Join(words, " ")
+}
+
+func TestNormalizer(t *testing.T) {
+	cases := []struct {
+		language   string
+		strictness Strictness
+		a, b       string
+		equal      bool
+	}{
+		{"en", Normal, "Don't stop!", "do not stop", true},
+		{"en-US", Normal, "I can’t go.", "I cannot go", true},
+		{"en", Normal, "We’re here", "we are here", true},
+		{"en", Normal, "“Hello,” she said.", "hello she said", true},
+		{"en", Normal, "I have 21 cats", "I have twenty-one cats", true},
+		{"en", Normal, "It costs 105 dollars", "it costs one hundred five dollars", true},
+		{"en", Normal, "the 3rd floor", "the third floor", true},
+		{"en", Normal, "the 42nd street", "the forty-second street", true},
+		{"en", Normal, "ＨＥＬＬＯ world", "hello world", true},
+		{"en", Normal, "café", "cafe", false},
+		{"fr", Lenient, "Café crème", "cafe creme", true},
+		{"en", Strict, "Don't stop", "do not stop", false},
+		{"en", Strict, "Hello", "hello", false},
+		{"en", Strict, "Don’t stop", "Don't stop.", true},
+		{"en", Strict, "café", "café", true},
+		{"ja", Normal, "ｺﾝﾋﾟｭｰﾀｰ", "コンピューター", true},
+		{"ja", Normal, "學校の櫻", "学校の桜", true},
+		{"ja", Normal, "人々", "人人", true},
+		{"ja", Normal, "コーヒー", "こーひー", false},
+		{"ja", Lenient, "コーヒー", "こーひー", true},
+		{"ja", Lenient, "がっこう", "ガッコウ", true},
+		{"ja", Strict, "學校", "学校", false},
+		{"de", Normal, "GROSS", "gross", true},
+	}
+	for _, tc := range cases {
+		n := New(tc.language, tc.strictness)
+		a, b := normalize(n, tc.a), normalize(n, tc.b)
+		if (a == b) != tc.equal {
+			t.Errorf("%s/%s: %q -> %q, %q -> %q, want equal=%v", tc.language, tc.strictness, tc.a, a, tc.b, b, tc.equal)
+		}
+	}
+}
+
+func TestEnglishNumbers(t *testing.T) {
+	cases := map[string]string{
+		"0":         "zero",
+		"13":        "thirteen",
+		"40":        "forty",
+		"1000":      "one thousand",
+		"1,200":     "one thousand two hundred",
+		"2024":      "two thousand twenty four",
+		"1000001":   "one million one",
+		"11th":      "eleventh",
+		"20th":      "twentieth",
+		"007":       "007",
+		"1st-class": "1stclass",
+		"2st":       "2st",
+	}
+	n := New("en", Normal)
+	for in, want := range cases {
+		if got := strings.Join(n.Words(in), " "); got != want {
+			t.Errorf("Words(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestFold(t *testing.T) {
+	n := New("en", Normal)
+	if got := n.Fold("Don’t,"); got != "don't" {
+		t.Errorf("Fold kept punctuation or expanded: %q", got)
+	}
+	if got := n.Words("..."); got != nil {
+		t.Errorf("Words of punctuation = %v, want nil", got)
+	}
+	if got := New("ja", Lenient).Words("ヴァイオリン"); !slices.Equal(got, []string{"ゔぁいおりん"}) {
+		t.Errorf("katakana fold = %v", got)
+	}
+}
+
+func TestParseStrictness(t *testing.T) {
+	for in, want := range map[string]Strictness{"": Normal, "Strict": Strict, " lenient ": Lenient} {
+		if got, err := ParseStrictness(in); err != nil || got != want {
+			t.Errorf("ParseStrictness(%q) = %q, %v", in, got, err)
+		}
+	}
+	if _, err := ParseStrictness("loose"); err == nil {
+		t.Error("ParseStrictness accepted an unknown level")
+	}
+	if got := New("en", "loose").Strictness(); got != Normal {
+		t.Errorf("unknown strictness falls back to %q, want normal", got)
+	}
+}
@@ -0,0 +1,134 @@
+package tts
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrCacheMiss 缓存中没有对应音频
+var ErrCacheMiss = errors.New("audio not cached")
+
+// extensions 缓存文件扩展名与 MIME 类型的对应关系
+var extensions = map[string]string{
+	"audio/wav":  ".wav",
+	"audio/mpeg": ".mp3",
+	"audio/ogg":  ".ogg",
+}
+
+// CachedAudio 缓存中的音频文件，调用方负责关闭 File
+type CachedAudio struct {
+	File        *os.File
+	ContentType string
+	Size        int64
+	ModTime     time.Time
+}
+
+// FileCache 本地文件音频缓存，文件名由句子ID和内容哈希组成
+type FileCache struct {
+	dir string
+}
+
+// NewFileCache 创建文件缓存，目录不存在时自动创建
+func NewFileCache(dir string) (*FileCache, error) {
+	if dir == "" {
+		return nil, errors.New("cache dir is required")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cache dir: %w", err)
+	}
+	return &FileCache{dir: dir}, nil
+}
+
+// Dir 返回缓存目录
+func (c *FileCache) Dir() string {
+	return c.dir
+}
+
+// CheckWritable 确认缓存目录可写，写入并删除一个临时文件
+func (c *FileCache) CheckWritable() error {
+	tmp, err := os.CreateTemp(c.dir, ".probe-*.tmp")
+	if err != nil {
+		return fmt.Errorf("cache dir is not writable: %w", err)
+	}
+	name := tmp.Name()
+	tmp.Close()
+	if err := os.Remove(name); err != nil {
+		return fmt.Errorf("failed to remove probe file: %w", err)
+	}
+	return nil
+}
+
+// Key 生成缓存键：句子ID + 合成参数的内容哈希，原文或参数变化后键随之变化
+func Key(sentenceID uint, provider string, req *Request) string {
+	h := sha256.New()
+	for _, part := range []string{
+		provider,
+		req.Language,
+		req.Voice,
+		strconv.FormatFloat(req.Speed, 'f', 2, 64),
+		req.Text,
+	} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return fmt.Sprintf("%d-%s", sentenceID, hex.EncodeToString(h.Sum(nil))[:16])
+}
+
+// Open 打开缓存的音频，未命中时返回 ErrCacheMiss
+func (c *FileCache) Open(key string) (*CachedAudio, error) {
+	for contentType, ext := range extensions {
+		f, err := os.Open(c.path(key, ext))
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		info, err := f.Stat()
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		return &CachedAudio{
+			File:        f,
+			ContentType: contentType,
+			Size:        info.Size(),
+			ModTime:     info.ModTime(),
+		}, nil
+	}
+	return nil, ErrCacheMiss
+}
+
+// Put 写入缓存；先写临时文件再重命名，并发请求不会读到写了一半的文件
+func (c *FileCache) Put(key string, audio *Audio) error {
+	ext, ok := extensions[strings.ToLower(audio.ContentType)]
+	if !ok {
+		return fmt.Errorf("unsupported audio content type: %s", audio.ContentType)
+	}
+
+	tmp, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create cache file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(audio.Data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	return os.Rename(tmp.Name(), c.path(key, ext))
+}
+
+func (c *FileCache) path(key, ext string) string {
+	return filepath.Join(c.dir, key+ext)
+}
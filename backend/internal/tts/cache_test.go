@@ -0,0 +1,67 @@
+package tts
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileCache(t *testing.T) {
+	cache, err := NewFileCache(filepath.Join(t.TempDir(), "audio"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.CheckWritable(); err != nil {
+		t.Fatalf("CheckWritable: %v", err)
+	}
+
+	key := Key(1, ProviderTone, &Request{Text: "hello", Language: "en", Speed: 1})
+	if _, err := cache.Open(key); !errors.Is(err, ErrCacheMiss) {
+		t.Fatalf("Open before Put = %v, want ErrCacheMiss", err)
+	}
+
+	data := []byte("RIFF fake wav")
+	if err := cache.Put(key, &Audio{Data: data, ContentType: "audio/wav"}); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	cached, err := cache.Open(key)
+	if err != nil {
+		t.Fatalf("Open after Put: %v", err)
+	}
+	defer cached.File.Close()
+	got, _ := io.ReadAll(cached.File)
+	if string(got) != string(data) || cached.Size != int64(len(data)) || cached.ContentType != "audio/wav" {
+		t.Errorf("cached = %q (%d bytes, %s)", got, cached.Size, cached.ContentType)
+	}
+
+	// 临时文件在重命名后不应残留
+	entries, _ := os.ReadDir(cache.Dir())
+	if len(entries) != 1 || entries[0].Name() != key+".wav" {
+		t.Errorf("cache dir entries = %v, want only %s.wav", entries, key)
+	}
+
+	if err := cache.Put(key, &Audio{Data: data, ContentType: "audio/flac"}); err == nil {
+		t.Error("unsupported content type: want error")
+	}
+}
+
+func TestKey(t *testing.T) {
+	req := &Request{Text: "hello", Language: "en", Voice: "a", Speed: 1}
+	key := Key(7, ProviderTone, req)
+	if Key(7, ProviderTone, &Request{Text: "hello", Language: "en", Voice: "a", Speed: 1}) != key {
+		t.Error("key is not stable")
+	}
+	for name, other := range map[string]string{
+		"sentence": Key(8, ProviderTone, req),
+		"provider": Key(7, ProviderSilence, req),
+		"text":     Key(7, ProviderTone, &Request{Text: "hello!", Language: "en", Voice: "a", Speed: 1}),
+		"speed":    Key(7, ProviderTone, &Request{Text: "hello", Language: "en", Voice: "a", Speed: 1.5}),
+		"voice":    Key(7, ProviderTone, &Request{Text: "hello", Language: "en", Voice: "b", Speed: 1}),
+	} {
+		if other == key {
+			t.Errorf("changing %s did not change the key", name)
+		}
+	}
+}
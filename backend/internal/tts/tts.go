@@ -0,0 +1,48 @@
+package tts
+
+import (
+	"context"
+	"fmt"
+
+	"voicewriter/internal/config"
+)
+
+const (
+	// ProviderTone 离线提示音合成器（默认）
+	ProviderTone = "tone"
+	// ProviderSilence 离线静音合成器
+	ProviderSilence = "silence"
+)
+
+// Request 语音合成请求
+type Request struct {
+	Text     string
+	Language string
+	Voice    string
+	Speed    float64 // 1.0 为正常语速
+}
+
+// Audio 合成后的音频数据
+type Audio struct {
+	Data        []byte
+	ContentType string
+}
+
+// Synthesizer 语音合成接口，不同 TTS 提供方各自实现
+type Synthesizer interface {
+	// Name 返回提供方名称，参与缓存键计算，切换提供方后旧缓存自动失效
+	Name() string
+	Synthesize(ctx context.Context, req *Request) (*Audio, error)
+}
+
+// NewSynthesizer 根据配置创建语音合成器
+func NewSynthesizer(cfg *config.TTSConfig) (Synthesizer, error) {
+	switch cfg.Provider {
+	case "", ProviderTone:
+		return NewToneSynthesizer(), nil
+	case ProviderSilence:
+		return NewSilenceSynthesizer(), nil
+	default:
+		return nil, fmt.Errorf("unsupported tts provider: %s", cfg.Provider)
+	}
+}
@@ -0,0 +1,119 @@
+package tts
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"voicewriter/internal/config"
+)
+
+// wavHeader 标准 44 字节 PCM WAV 头
+type wavHeader struct {
+	RIFF          [4]byte
+	ChunkSize     uint32
+	WAVE          [4]byte
+	Fmt           [4]byte
+	FmtSize       uint32
+	Format        uint16
+	Channels      uint16
+	SampleRate    uint32
+	ByteRate      uint32
+	BlockAlign    uint16
+	BitsPerSample uint16
+	Data          [4]byte
+	DataSize      uint32
+}
+
+func synthesize(t *testing.T, s Synthesizer, req *Request) (*Audio, wavHeader) {
+	t.Helper()
+	audio, err := s.Synthesize(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Synthesize: %v", err)
+	}
+	var h wavHeader
+	if err := binary.Read(bytes.NewReader(audio.Data), binary.LittleEndian, &h); err != nil {
+		t.Fatalf("read header: %v", err)
+	}
+	return audio, h
+}
+
+func TestToneSynthesizerWAV(t *testing.T) {
+	audio, h := synthesize(t, NewToneSynthesizer(), &Request{Text: "hi there.", Speed: 1})
+	if audio.ContentType != "audio/wav" {
+		t.Errorf("content type = %q", audio.ContentType)
+	}
+	if string(h.RIFF[:]) != "RIFF" || string(h.WAVE[:]) != "WAVE" || string(h.Fmt[:]) != "fmt " || string(h.Data[:]) != "data" {
+		t.Fatalf("bad chunk ids: %+v", h)
+	}
+	if h.Format != 1 || h.Channels != 1 || h.SampleRate != wavSampleRate || h.BitsPerSample != 16 {
+		t.Errorf("format = %+v, want 16-bit mono PCM at %d Hz", h, wavSampleRate)
+	}
+	if h.ByteRate != wavSampleRate*2 || h.BlockAlign != 2 {
+		t.Errorf("byte rate = %d, block align = %d", h.ByteRate, h.BlockAlign)
+	}
+	if int(h.DataSize) != len(audio.Data)-44 || int(h.ChunkSize) != len(audio.Data)-8 {
+		t.Errorf("sizes = chunk %d, data %d for %d bytes", h.ChunkSize, h.DataSize, len(audio.Data))
+	}
+
+	// 头尾静音 + 7 个字符的音 + 1 个空格 + 1 个标点
+	wantSamples := 2*sampleCount(leadSilence, 1) + 7*sampleCount(toneUnitMs, 1) +
+		sampleCount(wordGapMs, 1) + sampleCount(wordGapMs*2, 1)
+	if int(h.DataSize) != wantSamples*2 {
+		t.Errorf("data size = %d, want %d", h.DataSize, wantSamples*2)
+	}
+}
+
+func TestToneSynthesizerSpeedAndDeterminism(t *testing.T) {
+	s := NewToneSynthesizer()
+	normal, h1 := synthesize(t, s, &Request{Text: "hello world", Speed: 1})
+	again, _ := synthesize(t, s, &Request{Text: "hello world", Speed: 1})
+	if !bytes.Equal(normal.Data, again.Data) {
+		t.Error("same request produced different audio")
+	}
+	_, h2 := synthesize(t, s, &Request{Text: "hello world", Speed: 2})
+	want := 2*sampleCount(leadSilence, 2) + 10*sampleCount(toneUnitMs, 2) + sampleCount(wordGapMs, 2)
+	if int(h2.DataSize) != want*2 || h2.DataSize >= h1.DataSize {
+		t.Errorf("double speed data size = %d, want %d (normal speed %d)", h2.DataSize, want*2, h1.DataSize)
+	}
+	_, h0 := synthesize(t, s, &Request{Text: "hello world"})
+	if h0.DataSize != h1.DataSize {
+		t.Errorf("zero speed = %d bytes, want normal speed %d", h0.DataSize, h1.DataSize)
+	}
+}
+
+func TestSilenceSynthesizer(t *testing.T) {
+	tone, _ := synthesize(t, NewToneSynthesizer(), &Request{Text: "abc"})
+	silence, _ := synthesize(t, NewSilenceSynthesizer(), &Request{Text: "abc"})
+	if len(silence.Data) != len(tone.Data) {
+		t.Errorf("silence = %d bytes, want same length as tone %d", len(silence.Data), len(tone.Data))
+	}
+	if !bytes.Equal(silence.Data[44:], make([]byte, len(silence.Data)-44)) {
+		t.Error("silence synthesizer produced non-zero samples")
+	}
+}
+
+func TestSynthesizeErrors(t *testing.T) {
+	s := NewToneSynthesizer()
+	if _, err := s.Synthesize(context.Background(), &Request{}); err == nil {
+		t.Error("empty text: want error")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := s.Synthesize(ctx, &Request{Text: "hi"}); err == nil {
+		t.Error("canceled context: want error")
+	}
+}
+
+func TestNewSynthesizer(t *testing.T) {
+	for provider, want := range map[string]string{"": ProviderTone, ProviderTone: ProviderTone, ProviderSilence: ProviderSilence} {
+		s, err := NewSynthesizer(&config.TTSConfig{Provider: provider})
+		if err != nil || s.Name() != want {
+			t.Errorf("NewSynthesizer(%q) = %v, %v; want %s", provider, s, err, want)
+		}
+	}
+	if _, err := NewSynthesizer(&config.TTSConfig{Provider: "cloud"}); err == nil {
+		t.Error("unknown provider: want error")
+	}
+}
@@ -0,0 +1,129 @@
+package tts
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"math"
+	"unicode"
+)
+
+const (
+	// wavSampleRate 采样率
+	wavSampleRate = 16000
+	// wavContentType WAV 音频的 MIME 类型
+	wavContentType = "audio/wav"
+
+	// 以下时长均为正常语速下的毫秒数
+	toneUnitMs  = 90
+	wordGapMs   = 120
+	leadSilence = 100
+)
+
+// toneSynthesizer 确定性的离线合成器：每个字符对应一个短音，词间留空
+// 不依赖任何外部服务，适用于测试和离线部署
+type toneSynthesizer struct {
+	silent bool
+}
+
+// NewToneSynthesizer 创建提示音合成器
+func NewToneSynthesizer() Synthesizer {
+	return &toneSynthesizer{}
+}
+
+// NewSilenceSynthesizer 创建静音合成器，输出与提示音等长的静音
+func NewSilenceSynthesizer() Synthesizer {
+	return &toneSynthesizer{silent: true}
+}
+
+func (s *toneSynthesizer) Name() string {
+	if s.silent {
+		return ProviderSilence
+	}
+	return ProviderTone
+}
+
+func (s *toneSynthesizer) Synthesize(ctx context.Context, req *Request) (*Audio, error) {
+	if req.Text == "" {
+		return nil, errors.New("text is required")
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	speed := req.Speed
+	if speed <= 0 {
+		speed = 1
+	}
+
+	var samples []int16
+	samples = appendSilence(samples, leadSilence, speed)
+	for _, r := range req.Text {
+		switch {
+		case unicode.IsSpace(r):
+			samples = appendSilence(samples, wordGapMs, speed)
+		case unicode.IsPunct(r):
+			samples = appendSilence(samples, wordGapMs*2, speed)
+		case s.silent:
+			samples = appendSilence(samples, toneUnitMs, speed)
+		default:
+			samples = appendTone(samples, runeFrequency(r), toneUnitMs, speed)
+		}
+	}
+	samples = appendSilence(samples, leadSilence, speed)
+
+	return &Audio{Data: encodeWAV(samples), ContentType: wavContentType}, nil
+}
+
+// runeFrequency 将字符映射到 A3 起两个八度内的半音
+func runeFrequency(r rune) float64 {
+	semitone := float64(unicode.ToLower(r) % 24)
+	return 220 * math.Pow(2, semitone/12)
+}
+
+func sampleCount(ms int, speed float64) int {
+	return int(float64(wavSampleRate*ms) / 1000 / speed)
+}
+
+func appendSilence(samples []int16, ms int, speed float64) []int16 {
+	return append(samples, make([]int16, sampleCount(ms, speed))...)
+}
+
+func appendTone(samples []int16, freq float64, ms int, speed float64) []int16 {
+	n := sampleCount(ms, speed)
+	for i := 0; i < n; i++ {
+		// 正弦窗包络，避免音与音之间的爆音
+		envelope := math.Sin(math.Pi * float64(i) / float64(n))
+		v := math.Sin(2*math.Pi*freq*float64(i)/wavSampleRate) * envelope * 0.3
+		samples = append(samples, int16(v*math.MaxInt16))
+	}
+	return samples
+}
+
+// encodeWAV 编码为 16 位单声道 PCM WAV
+func encodeWAV(samples []int16) []byte {
+	const (
+		channels      = 1
+		bitsPerSample = 16
+	)
+	dataSize := len(samples) * 2
+	byteRate := wavSampleRate * channels * bitsPerSample / 8
+
+	buf := bytes.NewBuffer(make([]byte, 0, 44+dataSize))
+	buf.WriteString("RIFF")
+	binary.Write(buf, binary.LittleEndian, uint32(36+dataSize))
+	buf.WriteString("WAVE")
+	buf.WriteString("fmt ")
+	binary.Write(buf, binary.LittleEndian, uint32(16))
+	binary.Write(buf, binary.LittleEndian, uint16(1)) // PCM
+	binary.Write(buf, binary.LittleEndian, uint16(channels))
+	binary.Write(buf, binary.LittleEndian, uint32(wavSampleRate))
+	binary.Write(buf, binary.LittleEndian, uint32(byteRate))
+	binary.Write(buf, binary.LittleEndian, uint16(channels*bitsPerSample/8))
+	binary.Write(buf, binary.LittleEndian, uint16(bitsPerSample))
+	buf.WriteString("data")
+	binary.Write(buf, binary.LittleEndian, uint32(dataSize))
+	binary.Write(buf, binary.LittleEndian, samples)
+	return buf.Bytes()
+}
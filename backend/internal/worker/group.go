@@ -0,0 +1,69 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+)
+
+// ErrClosed 任务组已关闭，不再接受新任务
+var ErrClosed = errors.New("worker group is shut down")
+
+// Group 后台任务组：任务不随发起它的请求取消，退出时由 Shutdown 等待全部任务完成
+type Group struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu     sync.Mutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// NewGroup 创建后台任务组
+func NewGroup() *Group {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Group{ctx: ctx, cancel: cancel}
+}
+
+// Go 在后台运行任务，任务返回的错误记录到日志；任务组已关闭时返回 ErrClosed
+// 传给任务的上下文只在 Shutdown 超时后取消
+func (g *Group) Go(name string, fn func(ctx context.Context) error) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.closed {
+		return ErrClosed
+	}
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if err := fn(g.ctx); err != nil {
+			slog.Error("background task failed", "task", name, "error", err)
+		}
+	}()
+	return nil
+}
+
+// Shutdown 停止接受新任务并等待已提交的任务完成
+// ctx 到期时取消仍在运行的任务并返回 ctx 的错误，不再等待任务退出
+func (g *Group) Shutdown(ctx context.Context) error {
+	g.mu.Lock()
+	g.closed = true
+	g.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		g.cancel()
+		return nil
+	case <-ctx.Done():
+		g.cancel()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,75 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Kind 业务错误类别，决定返回给客户端的 HTTP 状态码和错误码
+type Kind int
+
+const (
+	// KindInternal 未归类的内部错误
+	KindInternal Kind = iota
+	// KindValidation 请求参数不合法
+	KindValidation
+	// KindNotFound 资源不存在
+	KindNotFound
+	// KindConflict 与现有数据冲突
+	KindConflict
+	// KindUnauthorized 未认证或凭据无效
+	KindUnauthorized
+	// KindForbidden 已认证但无权操作
+	KindForbidden
+	// KindUnavailable 数据库等依赖暂时不可用，可稍后重试
+	KindUnavailable
+)
+
+// Error 带类别的业务错误，Message 可以直接返回给客户端，Err 为内部原因，只用于日志
+// Details 随错误响应一起返回给客户端，如逐行的校验结果，不能包含内部信息
+type Error struct {
+	Kind    Kind
+	Message string
+	Err     error
+	Details interface{}
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// New 创建业务错误
+func New(kind Kind, message string) *Error {
+	return &Error{Kind: kind, Message: message}
+}
+
+// Newf 按格式创建业务错误
+func Newf(kind Kind, format string, args ...interface{}) *Error {
+	return &Error{Kind: kind, Message: fmt.Sprintf(format, args...)}
+}
+
+// Wrap 创建带内部原因的业务错误
+func Wrap(kind Kind, err error, message string) *Error {
+	return &Error{Kind: kind, Message: message, Err: err}
+}
+
+// WithDetails 创建附带响应数据的业务错误
+func WithDetails(kind Kind, message string, details interface{}) *Error {
+	return &Error{Kind: kind, Message: message, Details: details}
+}
+
+// As 返回错误链中的第一个业务错误
+func As(err error) (*Error, bool) {
+	var e *Error
+	if errors.As(err, &e) {
+		return e, true
+	}
+	return nil, false
+}
@@ -6,6 +6,18 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// 响应错误码，成功时为 CodeSuccess；客户端应按错误码而不是 message 区分错误类型，已发布的错误码不再变更
+const (
+	CodeSuccess      = 0
+	CodeInternal     = 10000 // 服务器内部错误
+	CodeValidation   = 10001 // 请求参数不合法
+	CodeUnauthorized = 10002 // 未认证或凭据无效
+	CodeForbidden    = 10003 // 无权操作
+	CodeNotFound     = 10004 // 资源不存在
+	CodeConflict     = 10005 // 与现有数据冲突
+	CodeUnavailable  = 10006 // 依赖服务暂时不可用，可稍后重试
+)
+
 // Response 统一响应结构
 type Response struct {
 	Code    int         `json:"code"`
@@ -13,10 +25,27 @@ type Response struct {
 	Data    interface{} `json:"data,omitempty"`
 }
 
+// Page 分页信息：offset 分页时 Offset 有效，游标分页时用 NextCursor 请求下一页
+type Page struct {
+	Total      int64  `json:"total"`
+	Limit      int    `json:"limit"`
+	Offset     int    `json:"offset"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+}
+
+// PageResponse 分页响应结构，Data 为当前页的列表
+type PageResponse struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data"`
+	Page    *Page       `json:"page"`
+}
+
 // Success 成功响应
 func Success(c *gin.Context, data interface{}) {
 	c.JSON(http.StatusOK, Response{
-		Code:    0,
+		Code:    CodeSuccess,
 		Message: "success",
 		Data:    data,
 	})
@@ -25,16 +54,26 @@ func Success(c *gin.Context, data interface{}) {
 // SuccessWithMessage 带自定义消息的成功响应
 func SuccessWithMessage(c *gin.Context, message string, data interface{}) {
 	c.JSON(http.StatusOK, Response{
-		Code:    0,
+		Code:    CodeSuccess,
 		Message: message,
 		Data:    data,
 	})
 }
 
-// Error 错误响应
+// SuccessWithPage 分页列表的成功响应
+func SuccessWithPage(c *gin.Context, data interface{}, page *Page) {
+	c.JSON(http.StatusOK, PageResponse{
+		Code:    CodeSuccess,
+		Message: "success",
+		Data:    data,
+		Page:    page,
+	})
+}
+
+// Error 错误响应，错误码由 HTTP 状态码决定
 func Error(c *gin.Context, httpCode int, message string) {
 	c.JSON(httpCode, Response{
-		Code:    -1,
+		Code:    CodeForStatus(httpCode),
 		Message: message,
 	})
 }
@@ -47,11 +86,64 @@ func ErrorWithCode(c *gin.Context, httpCode int, code int, message string) {
 	})
 }
 
+// ErrorWithCodeAndData 带自定义错误码和数据的错误响应
+func ErrorWithCodeAndData(c *gin.Context, httpCode int, code int, message string, data interface{}) {
+	c.JSON(httpCode, Response{
+		Code:    code,
+		Message: message,
+		Data:    data,
+	})
+}
+
+// ErrorWithData 带数据的错误响应，用于返回校验报告等详细信息
+func ErrorWithData(c *gin.Context, httpCode int, message string, data interface{}) {
+	c.JSON(httpCode, Response{
+		Code:    CodeForStatus(httpCode),
+		Message: message,
+		Data:    data,
+	})
+}
+
+// CodeForStatus 返回 HTTP 错误状态码对应的错误码
+func CodeForStatus(httpCode int) int {
+	switch httpCode {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity, http.StatusRequestEntityTooLarge:
+		return CodeValidation
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusServiceUnavailable:
+		return CodeUnavailable
+	default:
+		return CodeInternal
+	}
+}
+
 // BadRequest 400错误
 func BadRequest(c *gin.Context, message string) {
 	Error(c, http.StatusBadRequest, message)
 }
 
+// Unauthorized 401错误
+func Unauthorized(c *gin.Context, message string) {
+	Error(c, http.StatusUnauthorized, message)
+}
+
+// Forbidden 403错误
+func Forbidden(c *gin.Context, message string) {
+	Error(c, http.StatusForbidden, message)
+}
+
+// Conflict 409错误
+func Conflict(c *gin.Context, message string) {
+	Error(c, http.StatusConflict, message)
+}
+
 // NotFound 404错误
 func NotFound(c *gin.Context, message string) {
 	Error(c, http.StatusNotFound, message)
@@ -61,3 +153,8 @@ func NotFound(c *gin.Context, message string) {
 func InternalServerError(c *gin.Context, message string) {
 	Error(c, http.StatusInternalServerError, message)
 }
+
+// ServiceUnavailable 503错误
+func ServiceUnavailable(c *gin.Context, message string) {
+	Error(c, http.StatusServiceUnavailable, message)
+}
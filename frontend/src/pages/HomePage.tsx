@@ -25,7 +25,7 @@ const HomePage: React.FC = () => {
   const loadScenes = async () => {
     setLoading(true);
     try {
-      const response = await sceneApi.getAll();
+      const response = await sceneApi.getAll({ limit: 100 });
       if (response.data.code === 0) {
         setScenes(response.data.data);
       }
@@ -1,5 +1,19 @@
 import axios from 'axios';
-import { Scene, Sentence, UserProgress, ApiResponse } from '../types';
+import {
+  Scene,
+  Sentence,
+  UserProgress,
+  SaveProgressRequest,
+  ApiResponse,
+  AuthResult,
+  AnonymousCredentials,
+  ClaimResult,
+  PageResponse,
+  ListParams,
+  SentenceListParams,
+  SearchParams,
+  SearchResult,
+} from '../types';
 
 const API_BASE_URL = process.env.REACT_APP_API_URL || 'http://localhost:8080/api/v1';
 
@@ -11,28 +25,55 @@ const api = axios.create({
   },
 });
 
+// 携带登录后保存的访问令牌
+api.interceptors.request.use((config) => {
+  const token = localStorage.getItem('access_token');
+  if (token) {
+    config.headers.Authorization = `Bearer ${token}`;
+  }
+  return config;
+});
+
+// 认证相关API
+export const authApi = {
+  register: (username: string, password: string) =>
+    api.post<ApiResponse<AuthResult>>('/auth/register', { username, password }),
+  login: (username: string, password: string) =>
+    api.post<ApiResponse<AuthResult>>('/auth/login', { username, password }),
+  refresh: (refreshToken: string) =>
+    api.post<ApiResponse<AuthResult>>('/auth/refresh', { refresh_token: refreshToken }),
+  issueAnonymous: () => api.post<ApiResponse<AnonymousCredentials>>('/auth/anonymous'),
+  claim: (anonymousId: string, claimSecret: string) =>
+    api.post<ApiResponse<ClaimResult>>('/auth/claim', { anonymous_id: anonymousId, claim_secret: claimSecret }),
+};
+
 // 场景相关API
 export const sceneApi = {
-  getAll: () => api.get<ApiResponse<Scene[]>>('/scenes'),
+  getAll: (params?: ListParams & { q?: string }) => api.get<PageResponse<Scene>>('/scenes', { params }),
   getById: (id: number) => api.get<ApiResponse<Scene>>(`/scenes/${id}`),
 };
 
 // 句子相关API
 export const sentenceApi = {
-  getAll: () => api.get<ApiResponse<Sentence[]>>('/sentences'),
+  getAll: (params?: SentenceListParams) => api.get<PageResponse<Sentence>>('/sentences', { params }),
   getById: (id: number) => api.get<ApiResponse<Sentence>>(`/sentences/${id}`),
   getByScene: (sceneId: number) => api.get<ApiResponse<Sentence[]>>(`/sentences/scene/${sceneId}`),
 };
 
-// 音频相关API
+// 全文搜索API
+export const searchApi = {
+  search: (params: SearchParams) => api.get<PageResponse<SearchResult>>('/search', { params }),
+};
+
+// 音频相关API（直接返回音频文件，可作为 <audio> 的 src）
 export const audioApi = {
-  getUrl: (id: number) => api.get<ApiResponse<{ url: string }>>(`/audio/${id}`),
+  getUrl: (id: number) => `${API_BASE_URL}/audio/${id}`,
 };
 
 // 用户进度相关API
 export const progressApi = {
-  getByUser: (userId: string) => api.get<ApiResponse<UserProgress[]>>(`/progress/${userId}`),
-  save: (progress: Partial<UserProgress>) => api.post<ApiResponse<null>>('/progress', progress),
+  getMine: () => api.get<ApiResponse<UserProgress[]>>('/progress'),
+  save: (progress: SaveProgressRequest) => api.post<ApiResponse<null>>('/progress', progress),
 };
 
 export default api;
@@ -3,6 +3,8 @@ export interface Scene {
   name: string;
   description: string;
   icon: string;
+  strictness?: 'strict' | 'normal' | 'lenient';
+  locale?: string;
   created_at?: string;
   updated_at?: string;
 }
@@ -10,8 +12,10 @@ export interface Scene {
 export interface Sentence {
   id: number;
   scene_id: number;
+  language: string;
   content: string;
   translation: string;
+  translation_locale?: string;
   audio_url: string;
   difficulty: 'easy' | 'medium' | 'hard';
   created_at?: string;
@@ -20,13 +24,53 @@ export interface Sentence {
 
 export interface UserProgress {
   id: number;
-  user_id: string;
   sentence_id: number;
   completed: boolean;
   attempts: number;
   last_attempt?: string;
-  created_at?: string;
-  updated_at?: string;
+}
+
+// 保存进度时只提交这些字段，作答次数和时间由服务端维护
+export interface SaveProgressRequest {
+  sentence_id: number;
+  completed?: boolean;
+  submitted_text?: string;
+  duration_ms?: number;
+  audio_replays?: number;
+}
+
+export interface User {
+  id: number;
+  uid: string;
+  username: string;
+  role: 'user' | 'admin';
+}
+
+export interface TokenPair {
+  access_token: string;
+  refresh_token: string;
+  token_type: string;
+  expires_at: string;
+}
+
+// 匿名身份刷新令牌时 user 为 null
+export interface AuthResult {
+  user: User | null;
+  tokens: TokenPair;
+}
+
+// 匿名身份的令牌可以记录进度，claim_secret 只在签发时返回一次
+export interface AnonymousCredentials {
+  anonymous_id: string;
+  claim_secret: string;
+  tokens: TokenPair;
+}
+
+export interface ClaimResult {
+  progress: number;
+  attempts: number;
+  reviews: number;
+  vocabulary: number;
 }
 
 export interface ApiResponse<T> {
@@ -34,3 +78,51 @@ export interface ApiResponse<T> {
   data: T;
   message: string;
 }
+
+export interface Page {
+  total: number;
+  limit: number;
+  offset: number;
+  next_cursor?: string;
+  has_more: boolean;
+}
+
+export interface PageResponse<T> extends ApiResponse<T[]> {
+  page: Page;
+}
+
+export interface ListParams {
+  sort?: string;
+  limit?: number;
+  offset?: number;
+  cursor?: string;
+  lang?: string;
+}
+
+export interface SentenceListParams extends ListParams {
+  scene_id?: number;
+  difficulty?: 'easy' | 'medium' | 'hard';
+  language?: string;
+  q?: string;
+  has_audio?: boolean;
+}
+
+export interface SearchParams {
+  q: string;
+  scene_id?: number;
+  language?: string;
+  limit?: number;
+  offset?: number;
+  lang?: string;
+}
+
+export interface SearchResult {
+  sentence: Sentence;
+  score: number;
+  // 已转义的 HTML，命中部分用 <em> 标记；其他语言的翻译键为 translation.<语言>
+  highlights: {
+    content?: string;
+    translation?: string;
+    [key: `translation.${string}`]: string | undefined;
+  };
+}